@@ -0,0 +1,731 @@
+// Package analytics computes recurring revenue and cohort metrics from
+// subscriptions and transactions.
+//
+// MRR counts each subscription's recurring items at their list price,
+// normalised to a month, from its first billing until it is canceled or
+// paused. A subscription which has since resumed is not counted where its
+// billed transactions skip a period, showing it was paused. A subscription's
+// items at a point in the past are its current items when it has not
+// changed since; otherwise they are rebuilt from the transaction which
+// billed the period containing that point. Changes made later in that
+// period are only seen when they were billed straight away: subscriptions
+// with a subscription_update transaction in between, or without a billing
+// transaction at all, are counted with their current items and reported as
+// unresolved. Discounts and tax are excluded.
+//
+// Amounts in different currencies are never added together: results are
+// always broken down by currency, and optionally by product and country.
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/texm/go-paddle"
+)
+
+type Dimension string
+
+const (
+	DimensionProduct = Dimension("product")
+	DimensionCountry = Dimension("country")
+)
+
+type Params struct {
+	From time.Time
+	To   time.Time
+	// GroupBy breaks results down by product, country or both, in addition
+	// to currency.
+	GroupBy []Dimension
+}
+
+func (p *Params) validate() error {
+	if !p.To.After(p.From) {
+		return fmt.Errorf("%w: to must be after from", paddle.ErrInvalidParams)
+	}
+	for _, d := range p.GroupBy {
+		if d != DimensionProduct && d != DimensionCountry {
+			return fmt.Errorf("%w: unknown dimension %q", paddle.ErrInvalidParams, d)
+		}
+	}
+	return nil
+}
+
+func (p *Params) groupedBy(d Dimension) bool {
+	for _, g := range p.GroupBy {
+		if g == d {
+			return true
+		}
+	}
+	return false
+}
+
+type Segment struct {
+	CurrencyCode paddle.CurrencyCode `json:"currency_code"`
+	ProductId    string              `json:"product_id,omitempty"`
+	// CountryCode is empty when the address could not be found.
+	CountryCode string `json:"country_code,omitempty"`
+}
+
+type Metrics struct {
+	Segment
+
+	// StartMRR and MRR are the monthly recurring revenue at From and To.
+	StartMRR paddle.Money `json:"start_mrr"`
+	MRR      paddle.Money `json:"mrr"`
+	ARR      paddle.Money `json:"arr"`
+	// NewMRR comes from customers who were not paying at From and
+	// ChurnedMRR from those who were but are not at To. ExpansionMRR and
+	// ContractionMRR are the increases and decreases from customers paying
+	// at both, so MRR is StartMRR plus NewMRR and ExpansionMRR, less
+	// ContractionMRR and ChurnedMRR.
+	NewMRR         paddle.Money `json:"new_mrr"`
+	ExpansionMRR   paddle.Money `json:"expansion_mrr"`
+	ContractionMRR paddle.Money `json:"contraction_mrr"`
+	ChurnedMRR     paddle.Money `json:"churned_mrr"`
+	// RetainedMRR is the MRR at To of customers who were paying at From.
+	RetainedMRR paddle.Money `json:"retained_mrr"`
+
+	StartCustomers   int `json:"start_customers"`
+	Customers        int `json:"customers"`
+	NewCustomers     int `json:"new_customers"`
+	ChurnedCustomers int `json:"churned_customers"`
+
+	// NetRevenueRetention is RetainedMRR over StartMRR. It is nil when
+	// there was nothing at From or any subscription is unresolved.
+	NetRevenueRetention *float64 `json:"net_revenue_retention,omitempty"`
+	// CustomerChurn is ChurnedCustomers over StartCustomers and
+	// RevenueChurn is ChurnedMRR over StartMRR. They are zero when there
+	// was nothing at From.
+	CustomerChurn float64 `json:"customer_churn"`
+	RevenueChurn  float64 `json:"revenue_churn"`
+	// Unresolved counts the subscriptions whose items at From or To could
+	// not be rebuilt and which are counted with their current items.
+	Unresolved int `json:"unresolved"`
+
+	// Revenue is the total, less tax, of completed and paid transactions
+	// billed during the range.
+	Revenue      paddle.Money `json:"revenue"`
+	Transactions int          `json:"transactions"`
+	// Payout and Earnings are the transactions' payout totals, after
+	// adjustments, in the payout currency. Paddle does not split them by
+	// item, so they are nil when grouped by product.
+	Payout   *paddle.Money `json:"payout,omitempty"`
+	Earnings *paddle.Money `json:"earnings,omitempty"`
+}
+
+// Cohort follows the customers who started paying in the same month, by
+// the MRR of their subscriptions in its currency.
+type Cohort struct {
+	CurrencyCode paddle.CurrencyCode `json:"currency_code"`
+	// Month is the first day, in UTC, of the month the customers started
+	// paying in. Only customers who started during the range are counted.
+	Month     time.Time `json:"month"`
+	Customers int       `json:"customers"`
+	// Periods has the cohort at the end of each month from Month, the last
+	// ending at To.
+	Periods []*CohortPeriod `json:"periods"`
+}
+
+type CohortPeriod struct {
+	// At is the first instant of the next month, or To.
+	At        time.Time    `json:"at"`
+	Customers int          `json:"customers"`
+	MRR       paddle.Money `json:"mrr"`
+	// CustomerRetention is Customers over the cohort's Customers and
+	// RevenueRetention is MRR over the first period's MRR. RevenueRetention
+	// is nil when the first period's MRR is zero or either period has
+	// unresolved subscriptions.
+	CustomerRetention float64  `json:"customer_retention"`
+	RevenueRetention  *float64 `json:"revenue_retention,omitempty"`
+	Unresolved        int      `json:"unresolved"`
+}
+
+type Report struct {
+	From     time.Time  `json:"from"`
+	To       time.Time  `json:"to"`
+	Segments []*Metrics `json:"segments"`
+	Cohorts  []*Cohort  `json:"cohorts"`
+}
+
+// Compute reads subscriptions and transactions from source and computes
+// metrics for the range.
+func Compute(ctx context.Context, source Source, params Params) (*Report, error) {
+	if validateErr := params.validate(); validateErr != nil {
+		return nil, validateErr
+	}
+	subscriptions, subscriptionsErr := source.Subscriptions(ctx)
+	if subscriptionsErr != nil {
+		return nil, fmt.Errorf("failed to list subscriptions: %w", subscriptionsErr)
+	}
+	// Subscriptions updated since From are rebuilt from the transaction
+	// billing their period at From, which may be a billing cycle earlier.
+	from := params.From
+	for _, sub := range subscriptions {
+		if start := cycleBefore(params.From, sub.BillingCycle); sub.UpdatedAt.After(params.From) && start.Before(from) {
+			from = start
+		}
+	}
+	transactions, transactionsErr := source.Transactions(ctx, from, params.To)
+	if transactionsErr != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", transactionsErr)
+	}
+
+	r := &report{
+		params:    params,
+		source:    source,
+		segments:  map[Segment]*segment{},
+		countries: map[string]string{},
+		billed:    map[string][]*paddle.Transaction{},
+	}
+	for _, txn := range transactions {
+		r.addBilled(txn)
+	}
+	for _, sub := range subscriptions {
+		if addErr := r.addSubscription(ctx, sub); addErr != nil {
+			return nil, addErr
+		}
+	}
+	for _, txn := range transactions {
+		if addErr := r.addTransaction(ctx, txn); addErr != nil {
+			return nil, addErr
+		}
+	}
+	if cohortsErr := r.addCohorts(ctx, subscriptions); cohortsErr != nil {
+		return nil, cohortsErr
+	}
+	return r.finish(), nil
+}
+
+type segment struct {
+	metrics *Metrics
+	start   map[string]paddle.Amount
+	end     map[string]paddle.Amount
+	// unresolved holds the ids of subscriptions counted with their current
+	// items.
+	unresolved map[string]bool
+}
+
+type report struct {
+	params    Params
+	source    Source
+	segments  map[Segment]*segment
+	countries map[string]string
+	// billed holds each subscription's billed transactions, oldest first.
+	billed  map[string][]*paddle.Transaction
+	cohorts []*Cohort
+}
+
+func (r *report) key(currency paddle.CurrencyCode, productId string, country string) Segment {
+	key := Segment{CurrencyCode: currency}
+	if r.params.groupedBy(DimensionProduct) {
+		key.ProductId = productId
+	}
+	if r.params.groupedBy(DimensionCountry) {
+		key.CountryCode = country
+	}
+	return key
+}
+
+func (r *report) segment(key Segment) *segment {
+	seg, exists := r.segments[key]
+	if !exists {
+		zero := paddle.NewMoney(paddle.Amount{}, key.CurrencyCode)
+		seg = &segment{
+			metrics: &Metrics{
+				Segment: key, StartMRR: zero, MRR: zero, ARR: zero, NewMRR: zero, ExpansionMRR: zero,
+				ContractionMRR: zero, ChurnedMRR: zero, RetainedMRR: zero, Revenue: zero,
+			},
+			start:      map[string]paddle.Amount{},
+			end:        map[string]paddle.Amount{},
+			unresolved: map[string]bool{},
+		}
+		r.segments[key] = seg
+	}
+	return seg
+}
+
+func (r *report) country(ctx context.Context, customerId string, addressId string) (string, error) {
+	if country, ok := r.countries[addressId]; ok {
+		return country, nil
+	}
+	address, addressErr := r.source.Address(ctx, customerId, addressId)
+	if addressErr != nil {
+		return "", fmt.Errorf("failed to get address %s: %w", addressId, addressErr)
+	}
+	country := ""
+	if address != nil {
+		country = address.CountryCode
+	}
+	r.countries[addressId] = country
+	return country, nil
+}
+
+// startedPaying returns when the subscription was first billed, or nil if
+// it has not been.
+func startedPaying(sub *paddle.Subscription) *time.Time {
+	start := sub.FirstBilledAt
+	if start == nil && sub.Status != paddle.SubscriptionStatusTrialing {
+		start = sub.StartedAt
+	}
+	return start
+}
+
+// payingAt reports whether the subscription was being billed at t, and
+// whether that could be established. Its status only shows whether it is
+// paused now, so a subscription changed since t which is not paused or
+// canceled now is checked against its billed transactions: it was not
+// paying at t if it was billed for a later period but not for one
+// containing t, as when it was paused and has since resumed, and it is
+// unresolved if it was billed for neither.
+func (r *report) payingAt(sub *paddle.Subscription, t time.Time) (paying bool, resolved bool) {
+	start := startedPaying(sub)
+	if start == nil || start.After(t) {
+		return false, true
+	}
+	if sub.Status == paddle.SubscriptionStatusCanceled && (sub.CanceledAt == nil || !sub.CanceledAt.After(t)) {
+		return false, true
+	}
+	if sub.Status == paddle.SubscriptionStatusPaused && (sub.PausedAt == nil || !sub.PausedAt.After(t)) {
+		return false, true
+	}
+	if !sub.UpdatedAt.After(t) {
+		return true, true
+	}
+	// Transactions are listed from at least a billing cycle before t, so a
+	// period containing t was billed if there was one.
+	billedAfter := false
+	for _, txn := range r.billed[sub.Id] {
+		period := txn.BillingPeriod
+		switch {
+		case period == nil:
+		case !period.StartsAt.After(t) && period.EndsAt.After(t):
+			return true, true
+		case period.StartsAt.After(t):
+			billedAfter = true
+		}
+	}
+	if billedAfter {
+		return false, true
+	}
+	return true, false
+}
+
+// monthly normalises an amount billed every cycle to a month.
+func monthly(a paddle.Amount, cycle paddle.TimeInterval) (paddle.Amount, error) {
+	n := int64(max(cycle.Frequency, 1))
+	switch cycle.Interval {
+	case paddle.TimePeriodIntervalDay:
+		return a.Scale(365, 12*n)
+	case paddle.TimePeriodIntervalWeek:
+		return a.Scale(52, 12*n)
+	case paddle.TimePeriodIntervalMonth:
+		return a.Scale(1, n)
+	case paddle.TimePeriodIntervalYear:
+		return a.Scale(1, 12*n)
+	}
+	return paddle.Amount{}, fmt.Errorf("unknown billing interval %q", cycle.Interval)
+}
+
+// cycleBefore returns t less one billing cycle.
+func cycleBefore(t time.Time, cycle paddle.TimeInterval) time.Time {
+	n := max(cycle.Frequency, 1)
+	switch cycle.Interval {
+	case paddle.TimePeriodIntervalDay:
+		return t.AddDate(0, 0, -n)
+	case paddle.TimePeriodIntervalWeek:
+		return t.AddDate(0, 0, -7*n)
+	case paddle.TimePeriodIntervalMonth:
+		return t.AddDate(0, -n, 0)
+	}
+	return t.AddDate(-n, 0, 0)
+}
+
+func unitPrice(price *paddle.Price, country string) paddle.Money {
+	for _, override := range price.UnitPriceOverrides {
+		for _, c := range override.CountryCodes {
+			if c == country {
+				return override.UnitPrice
+			}
+		}
+	}
+	return price.UnitPrice
+}
+
+func (r *report) addBilled(txn *paddle.Transaction) {
+	if txn.SubscriptionId == nil || txn.BilledAt == nil {
+		return
+	}
+	switch txn.Status {
+	case paddle.TransactionStatusBilled, paddle.TransactionStatusPaid, paddle.TransactionStatusCompleted:
+	default:
+		return
+	}
+	billed := append(r.billed[*txn.SubscriptionId], txn)
+	sort.SliceStable(billed, func(i, j int) bool { return billed[i].BilledAt.Before(*billed[j].BilledAt) })
+	r.billed[*txn.SubscriptionId] = billed
+}
+
+type item struct {
+	price    *paddle.Price
+	quantity int
+}
+
+func currentItems(sub *paddle.Subscription) []item {
+	var items []item
+	for i := range sub.Items {
+		if sub.Items[i].Status == paddle.SubscriptionItemStatusActive && sub.Items[i].Recurring {
+			items = append(items, item{&sub.Items[i].Price, sub.Items[i].Quantity})
+		}
+	}
+	return items
+}
+
+// itemsAt returns the subscription's recurring items at t: its current
+// items if it has not been updated since, or else those of the latest
+// transaction billing the period containing t, as long as no
+// subscription_update transaction was billed after it. When neither
+// applies it returns the current items and resolved is false.
+func (r *report) itemsAt(sub *paddle.Subscription, t time.Time) (items []item, resolved bool) {
+	if !sub.UpdatedAt.After(t) {
+		return currentItems(sub), true
+	}
+	var basis *paddle.Transaction
+	for _, txn := range r.billed[sub.Id] {
+		if txn.BilledAt.After(t) {
+			break
+		}
+		switch txn.Origin {
+		case paddle.TransactionOriginWeb, paddle.TransactionOriginApi, paddle.TransactionOriginSubscriptionRecurring:
+			basis = txn
+		case paddle.TransactionOriginSubscriptionUpdate:
+			basis = nil
+		}
+	}
+	var period *paddle.TimePeriod
+	if basis != nil {
+		period = basis.BillingPeriod
+	}
+	if period == nil || period.StartsAt.After(t) || !period.EndsAt.After(t) {
+		return currentItems(sub), false
+	}
+	for i := range basis.Items {
+		if basis.Items[i].Price.BillingCycle != nil {
+			items = append(items, item{&basis.Items[i].Price, basis.Items[i].Quantity})
+		}
+	}
+	return items, true
+}
+
+// value returns the subscription's MRR at t by segment, and whether its
+// items at t were resolved.
+func (r *report) value(ctx context.Context, sub *paddle.Subscription, t time.Time) (map[Segment]paddle.Amount, bool, error) {
+	items, resolved := r.itemsAt(sub, t)
+
+	needsCountry := r.params.groupedBy(DimensionCountry)
+	for _, item := range items {
+		needsCountry = needsCountry || len(item.price.UnitPriceOverrides) > 0
+	}
+	country := ""
+	if needsCountry && sub.AddressId != "" {
+		var countryErr error
+		if country, countryErr = r.country(ctx, sub.CustomerId, sub.AddressId); countryErr != nil {
+			return nil, false, countryErr
+		}
+	}
+
+	mrr := map[Segment]paddle.Amount{}
+	for _, item := range items {
+		cycle := sub.BillingCycle
+		if item.price.BillingCycle != nil {
+			cycle = *item.price.BillingCycle
+		}
+		unit := unitPrice(item.price, country)
+		amount, monthlyErr := monthly(unit.Amount.Mul(int64(item.quantity)), cycle)
+		if monthlyErr != nil {
+			return nil, false, fmt.Errorf("subscription %s: %w", sub.Id, monthlyErr)
+		}
+		key := r.key(unit.CurrencyCode, item.price.ProductId, country)
+		mrr[key] = mrr[key].Add(amount)
+	}
+	return mrr, resolved, nil
+}
+
+func (r *report) addSubscription(ctx context.Context, sub *paddle.Subscription) error {
+	points := []struct {
+		t   time.Time
+		end bool
+	}{
+		{r.params.From, false},
+		{r.params.To, true},
+	}
+	for _, p := range points {
+		paying, payingResolved := r.payingAt(sub, p.t)
+		if !paying {
+			continue
+		}
+		mrr, resolved, valueErr := r.value(ctx, sub, p.t)
+		if valueErr != nil {
+			return valueErr
+		}
+		resolved = resolved && payingResolved
+		for key, amount := range mrr {
+			seg := r.segment(key)
+			total, customers := &seg.metrics.StartMRR, seg.start
+			if p.end {
+				total, customers = &seg.metrics.MRR, seg.end
+			}
+			total.Amount = total.Amount.Add(amount)
+			customers[sub.CustomerId] = customers[sub.CustomerId].Add(amount)
+			if !resolved {
+				seg.unresolved[sub.Id] = true
+			}
+		}
+	}
+	return nil
+}
+func (r *report) addTransaction(ctx context.Context, txn *paddle.Transaction) error {
+	if txn.Status != paddle.TransactionStatusCompleted && txn.Status != paddle.TransactionStatusPaid {
+		return nil
+	}
+	if txn.BilledAt == nil || txn.BilledAt.Before(r.params.From) || !txn.BilledAt.Before(r.params.To) {
+		return nil
+	}
+
+	country := ""
+	if r.params.groupedBy(DimensionCountry) {
+		if txn.Address != nil {
+			country = txn.Address.CountryCode
+		} else if txn.AddressId != nil && txn.CustomerId != nil {
+			var countryErr error
+			if country, countryErr = r.country(ctx, *txn.CustomerId, *txn.AddressId); countryErr != nil {
+				return countryErr
+			}
+		}
+	}
+
+	if r.params.groupedBy(DimensionProduct) {
+		counted := map[*segment]bool{}
+		for _, line := range txn.Details.LineItems {
+			seg := r.segment(r.key(txn.CurrencyCode, line.Product.Id, country))
+			seg.metrics.Revenue.Amount = seg.metrics.Revenue.Amount.Add(line.Totals.Total.Sub(line.Totals.Tax))
+			if !counted[seg] {
+				seg.metrics.Transactions++
+				counted[seg] = true
+			}
+		}
+		return nil
+	}
+
+	seg := r.segment(r.key(txn.CurrencyCode, "", country))
+	m := seg.metrics
+	totals := txn.Details.Totals
+	m.Revenue.Amount = m.Revenue.Amount.Add(totals.Total.Sub(totals.Tax))
+	m.Transactions++
+
+	var payout, earnings paddle.Money
+	switch {
+	case txn.Details.AdjustedPayoutTotals != nil:
+		t := txn.Details.AdjustedPayoutTotals
+		payout, earnings = paddle.NewMoney(t.Total, t.CurrencyCode), paddle.NewMoney(t.Earnings, t.CurrencyCode)
+	case txn.Details.PayoutTotals != nil:
+		t := txn.Details.PayoutTotals
+		payout, earnings = paddle.NewMoney(t.Total, t.CurrencyCode), paddle.NewMoney(t.Earnings, t.CurrencyCode)
+	default:
+		return nil
+	}
+	var addErr error
+	if m.Payout, addErr = addMoney(m.Payout, payout); addErr != nil {
+		return fmt.Errorf("transaction %s: %w", txn.Id, addErr)
+	}
+	if m.Earnings, addErr = addMoney(m.Earnings, earnings); addErr != nil {
+		return fmt.Errorf("transaction %s: %w", txn.Id, addErr)
+	}
+	return nil
+}
+
+func addMoney(total *paddle.Money, m paddle.Money) (*paddle.Money, error) {
+	if total == nil {
+		return &m, nil
+	}
+	sum, addErr := total.Add(m)
+	if addErr != nil {
+		return nil, addErr
+	}
+	return &sum, nil
+}
+
+// addCohorts groups the customers who started paying during the range by
+// currency and month, and follows each group's MRR until To.
+func (r *report) addCohorts(ctx context.Context, subscriptions []*paddle.Subscription) error {
+	type customerKey struct {
+		currency   paddle.CurrencyCode
+		customerId string
+	}
+	started := map[customerKey]time.Time{}
+	for _, sub := range subscriptions {
+		start := startedPaying(sub)
+		if start == nil {
+			continue
+		}
+		key := customerKey{sub.CurrencyCode, sub.CustomerId}
+		if first, ok := started[key]; !ok || start.Before(first) {
+			started[key] = *start
+		}
+	}
+
+	type cohortKey struct {
+		currency paddle.CurrencyCode
+		month    time.Time
+	}
+	members := map[cohortKey]map[string][]*paddle.Subscription{}
+	for _, sub := range subscriptions {
+		first, ok := started[customerKey{sub.CurrencyCode, sub.CustomerId}]
+		if !ok || first.Before(r.params.From) || !first.Before(r.params.To) {
+			continue
+		}
+		first = first.UTC()
+		key := cohortKey{sub.CurrencyCode, time.Date(first.Year(), first.Month(), 1, 0, 0, 0, 0, time.UTC)}
+		if members[key] == nil {
+			members[key] = map[string][]*paddle.Subscription{}
+		}
+		members[key][sub.CustomerId] = append(members[key][sub.CustomerId], sub)
+	}
+
+	for key, customers := range members {
+		c := &Cohort{CurrencyCode: key.currency, Month: key.month, Customers: len(customers)}
+		for month := key.month.AddDate(0, 1, 0); ; month = month.AddDate(0, 1, 0) {
+			at := month
+			if at.After(r.params.To) {
+				at = r.params.To
+			}
+			period, periodErr := r.cohortPeriod(ctx, c, customers, at)
+			if periodErr != nil {
+				return periodErr
+			}
+			c.Periods = append(c.Periods, period)
+			if !at.Before(r.params.To) {
+				break
+			}
+		}
+		r.cohorts = append(r.cohorts, c)
+	}
+	return nil
+}
+
+func (r *report) cohortPeriod(ctx context.Context, c *Cohort, customers map[string][]*paddle.Subscription, at time.Time) (*CohortPeriod, error) {
+	period := &CohortPeriod{At: at, MRR: paddle.NewMoney(paddle.Amount{}, c.CurrencyCode)}
+	for _, subs := range customers {
+		paying := false
+		for _, sub := range subs {
+			subPaying, payingResolved := r.payingAt(sub, at)
+			if !subPaying {
+				continue
+			}
+			paying = true
+			mrr, resolved, valueErr := r.value(ctx, sub, at)
+			if valueErr != nil {
+				return nil, valueErr
+			}
+			if !resolved || !payingResolved {
+				period.Unresolved++
+			}
+			for key, amount := range mrr {
+				if key.CurrencyCode == c.CurrencyCode {
+					period.MRR.Amount = period.MRR.Amount.Add(amount)
+				}
+			}
+		}
+		if paying {
+			period.Customers++
+		}
+	}
+	period.CustomerRetention = float64(period.Customers) / float64(c.Customers)
+	return period, nil
+}
+
+func (r *report) finish() *Report {
+	report := &Report{From: r.params.From, To: r.params.To, Segments: []*Metrics{}, Cohorts: []*Cohort{}}
+	for _, seg := range r.segments {
+		m := seg.metrics
+		for customerId, start := range seg.start {
+			m.StartCustomers++
+			end, retained := seg.end[customerId]
+			if !retained {
+				m.ChurnedCustomers++
+				m.ChurnedMRR.Amount = m.ChurnedMRR.Amount.Add(start)
+				continue
+			}
+			m.RetainedMRR.Amount = m.RetainedMRR.Amount.Add(end)
+			if change := end.Sub(start); change.Sign() > 0 {
+				m.ExpansionMRR.Amount = m.ExpansionMRR.Amount.Add(change)
+			} else {
+				m.ContractionMRR.Amount = m.ContractionMRR.Amount.Sub(change)
+			}
+		}
+		for customerId, end := range seg.end {
+			m.Customers++
+			if _, existing := seg.start[customerId]; !existing {
+				m.NewCustomers++
+				m.NewMRR.Amount = m.NewMRR.Amount.Add(end)
+			}
+		}
+
+		m.Unresolved = len(seg.unresolved)
+		if m.Unresolved == 0 && !m.StartMRR.Amount.IsZero() {
+			nrr := ratio(m.RetainedMRR.Amount, m.StartMRR.Amount)
+			m.NetRevenueRetention = &nrr
+		}
+		m.RevenueChurn = ratio(m.ChurnedMRR.Amount, m.StartMRR.Amount)
+		if m.StartCustomers > 0 {
+			m.CustomerChurn = float64(m.ChurnedCustomers) / float64(m.StartCustomers)
+		}
+
+		m.ARR.Amount = m.MRR.Amount.Mul(12).Round()
+		for _, money := range []*paddle.Money{&m.StartMRR, &m.MRR, &m.NewMRR, &m.ExpansionMRR, &m.ContractionMRR, &m.ChurnedMRR, &m.RetainedMRR, &m.Revenue} {
+			money.Amount = money.Amount.Round()
+		}
+		report.Segments = append(report.Segments, m)
+	}
+	sort.Slice(report.Segments, func(i, j int) bool {
+		a, b := report.Segments[i].Segment, report.Segments[j].Segment
+		if a.CurrencyCode != b.CurrencyCode {
+			return a.CurrencyCode < b.CurrencyCode
+		}
+		if a.ProductId != b.ProductId {
+			return a.ProductId < b.ProductId
+		}
+		return a.CountryCode < b.CountryCode
+	})
+
+	for _, c := range r.cohorts {
+		first := c.Periods[0]
+		for _, p := range c.Periods {
+			if first.Unresolved == 0 && p.Unresolved == 0 && !first.MRR.Amount.IsZero() {
+				retention := ratio(p.MRR.Amount, first.MRR.Amount)
+				p.RevenueRetention = &retention
+			}
+		}
+		for _, p := range c.Periods {
+			p.MRR.Amount = p.MRR.Amount.Round()
+		}
+		report.Cohorts = append(report.Cohorts, c)
+	}
+	sort.Slice(report.Cohorts, func(i, j int) bool {
+		a, b := report.Cohorts[i], report.Cohorts[j]
+		if a.CurrencyCode != b.CurrencyCode {
+			return a.CurrencyCode < b.CurrencyCode
+		}
+		return a.Month.Before(b.Month)
+	})
+	return report
+}
+
+func ratio(a paddle.Amount, b paddle.Amount) float64 {
+	if b.IsZero() {
+		return 0
+	}
+	return a.Float64() / b.Float64()
+}
@@ -0,0 +1,366 @@
+package analytics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/texm/go-paddle"
+)
+
+var monthlyCycle = paddle.TimeInterval{Interval: paddle.TimePeriodIntervalMonth, Frequency: 1}
+
+var (
+	basic = paddle.Price{Id: "pri_basic", ProductId: "pro_basic", BillingCycle: &monthlyCycle, UnitPrice: paddle.NewMoney(paddle.NewAmount(1000), paddle.CurrencyCodeUSD)}
+	pro   = paddle.Price{Id: "pri_pro", ProductId: "pro_pro", BillingCycle: &monthlyCycle, UnitPrice: paddle.NewMoney(paddle.NewAmount(3000), paddle.CurrencyCodeUSD)}
+)
+
+func date(month time.Month, day int) time.Time {
+	return time.Date(2024, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+type fakeSource struct {
+	subscriptions []*paddle.Subscription
+	transactions  []*paddle.Transaction
+	// from is the start of the range transactions were last listed from.
+	from time.Time
+}
+
+func (s *fakeSource) Subscriptions(context.Context) ([]*paddle.Subscription, error) {
+	return s.subscriptions, nil
+}
+
+func (s *fakeSource) Transactions(_ context.Context, from time.Time, to time.Time) ([]*paddle.Transaction, error) {
+	s.from = from
+	var txns []*paddle.Transaction
+	for _, txn := range s.transactions {
+		if !txn.BilledAt.Before(from) && txn.BilledAt.Before(to) {
+			txns = append(txns, txn)
+		}
+	}
+	return txns, nil
+}
+
+func (s *fakeSource) Address(context.Context, string, string) (*paddle.Address, error) {
+	return nil, nil
+}
+
+// subscription returns an active monthly subscription first billed at
+// started and last updated at updated, with a quantity of the price.
+func subscription(id string, customerId string, started time.Time, updated time.Time, price paddle.Price, quantity int) *paddle.Subscription {
+	return &paddle.Subscription{
+		Id:            id,
+		CustomerId:    customerId,
+		Status:        paddle.SubscriptionStatusActive,
+		CurrencyCode:  paddle.CurrencyCodeUSD,
+		BillingCycle:  monthlyCycle,
+		StartedAt:     &started,
+		FirstBilledAt: &started,
+		UpdatedAt:     updated,
+		Items: []paddle.SubscriptionItem{{
+			Status:    paddle.SubscriptionItemStatusActive,
+			Recurring: true,
+			Quantity:  quantity,
+			Price:     price,
+		}},
+	}
+}
+
+func canceled(sub *paddle.Subscription, at time.Time) *paddle.Subscription {
+	sub.Status = paddle.SubscriptionStatusCanceled
+	sub.CanceledAt = &at
+	sub.UpdatedAt = at
+	return sub
+}
+
+// billed returns a completed transaction for a month of the subscription
+// from start, with a quantity of the price.
+func billed(id string, subscriptionId string, origin paddle.TransactionOrigin, start time.Time, price paddle.Price, quantity int) *paddle.Transaction {
+	return &paddle.Transaction{
+		Id:             id,
+		Status:         paddle.TransactionStatusCompleted,
+		SubscriptionId: &subscriptionId,
+		CurrencyCode:   paddle.CurrencyCodeUSD,
+		Origin:         origin,
+		BilledAt:       &start,
+		BillingPeriod:  &paddle.TimePeriod{StartsAt: start, EndsAt: start.AddDate(0, 1, 0)},
+		Items:          []paddle.TransactionItem{{Price: price, Quantity: quantity}},
+	}
+}
+
+func wantMoney(t *testing.T, name string, got paddle.Money, want int64) {
+	t.Helper()
+	if got.Amount.Cmp(paddle.NewAmount(want)) != 0 {
+		t.Errorf("%s = %s, want %d", name, got.Amount, want)
+	}
+}
+
+func TestCompute(t *testing.T) {
+	source := &fakeSource{
+		subscriptions: []*paddle.Subscription{
+			// Upgraded from basic to pro after From.
+			subscription("sub_up", "ctm_1", date(1, 5), date(3, 10), pro, 1),
+			// Downgraded from three seats to one after From.
+			subscription("sub_down", "ctm_2", date(12, 20).AddDate(-1, 0, 0), date(3, 20), basic, 1),
+			canceled(subscription("sub_churned", "ctm_3", date(1, 15), date(1, 15), basic, 2), date(3, 1)),
+			subscription("sub_new", "ctm_4", date(2, 10), date(2, 10), basic, 1),
+			// Unchanged since before From, so it needs no transactions.
+			subscription("sub_steady", "ctm_5", date(1, 2), date(1, 2), basic, 1),
+		},
+		transactions: []*paddle.Transaction{
+			billed("txn_up_1", "sub_up", paddle.TransactionOriginWeb, date(1, 5), basic, 1),
+			billed("txn_up_2", "sub_up", paddle.TransactionOriginSubscriptionRecurring, date(2, 5), basic, 1),
+			billed("txn_down_1", "sub_down", paddle.TransactionOriginSubscriptionRecurring, date(1, 20), basic, 3),
+			billed("txn_churned_1", "sub_churned", paddle.TransactionOriginWeb, date(1, 15), basic, 2),
+		},
+	}
+	report, err := Compute(context.Background(), source, Params{From: date(2, 1), To: date(4, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := date(1, 1); !source.from.Equal(want) {
+		t.Errorf("transactions listed from %v, want a billing cycle before From, %v", source.from, want)
+	}
+	if len(report.Segments) != 1 {
+		t.Fatalf("Compute() returned %d segments, want 1", len(report.Segments))
+	}
+	m := report.Segments[0]
+	wantMoney(t, "StartMRR", m.StartMRR, 1000+3000+2000+1000)
+	wantMoney(t, "MRR", m.MRR, 3000+1000+1000+1000)
+	wantMoney(t, "ARR", m.ARR, 12*6000)
+	wantMoney(t, "NewMRR", m.NewMRR, 1000)
+	wantMoney(t, "ExpansionMRR", m.ExpansionMRR, 2000)
+	wantMoney(t, "ContractionMRR", m.ContractionMRR, 2000)
+	wantMoney(t, "ChurnedMRR", m.ChurnedMRR, 2000)
+	wantMoney(t, "RetainedMRR", m.RetainedMRR, 5000)
+	if m.StartCustomers != 4 || m.Customers != 4 || m.NewCustomers != 1 || m.ChurnedCustomers != 1 {
+		t.Errorf("customers = %d at start, %d at end, %d new and %d churned, want 4, 4, 1 and 1",
+			m.StartCustomers, m.Customers, m.NewCustomers, m.ChurnedCustomers)
+	}
+	if m.NetRevenueRetention == nil || *m.NetRevenueRetention != 5000.0/7000 {
+		t.Errorf("NetRevenueRetention = %v, want %v", m.NetRevenueRetention, 5000.0/7000)
+	}
+	if m.CustomerChurn != 0.25 || m.RevenueChurn != 2000.0/7000 || m.Unresolved != 0 {
+		t.Errorf("churn = %v of customers and %v of revenue with %d unresolved", m.CustomerChurn, m.RevenueChurn, m.Unresolved)
+	}
+}
+
+func TestComputeByProduct(t *testing.T) {
+	source := &fakeSource{
+		subscriptions: []*paddle.Subscription{subscription("sub_up", "ctm_1", date(1, 5), date(3, 10), pro, 1)},
+		transactions:  []*paddle.Transaction{billed("txn_up_1", "sub_up", paddle.TransactionOriginWeb, date(1, 5), basic, 1)},
+	}
+	report, err := Compute(context.Background(), source, Params{From: date(2, 1), To: date(4, 1), GroupBy: []Dimension{DimensionProduct}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Segments) != 2 {
+		t.Fatalf("Compute() returned %d segments, want 2", len(report.Segments))
+	}
+	// Moving between products churns from one and is new to the other.
+	basicMetrics, proMetrics := report.Segments[0], report.Segments[1]
+	if basicMetrics.ProductId != "pro_basic" || basicMetrics.ChurnedCustomers != 1 {
+		t.Errorf("%s churned %d customers, want pro_basic with 1", basicMetrics.ProductId, basicMetrics.ChurnedCustomers)
+	}
+	wantMoney(t, "basic ChurnedMRR", basicMetrics.ChurnedMRR, 1000)
+	if proMetrics.ProductId != "pro_pro" || proMetrics.NewCustomers != 1 {
+		t.Errorf("%s gained %d customers, want pro_pro with 1", proMetrics.ProductId, proMetrics.NewCustomers)
+	}
+	wantMoney(t, "pro NewMRR", proMetrics.NewMRR, 3000)
+}
+
+func TestItemsAt(t *testing.T) {
+	tests := []struct {
+		name         string
+		updated      time.Time
+		transactions []*paddle.Transaction
+		wantQuantity int
+		wantResolved bool
+	}{
+		{name: "unchanged since", updated: date(2, 1), wantQuantity: 5, wantResolved: true},
+		{name: "no transactions", updated: date(3, 1), wantQuantity: 5},
+		{name: "billed period", updated: date(3, 1),
+			transactions: []*paddle.Transaction{
+				billed("txn_1", "sub_1", paddle.TransactionOriginWeb, date(1, 10), basic, 1),
+				billed("txn_3", "sub_1", paddle.TransactionOriginSubscriptionRecurring, date(3, 10), basic, 3),
+				billed("txn_2", "sub_1", paddle.TransactionOriginSubscriptionRecurring, date(2, 10), basic, 2),
+			},
+			wantQuantity: 2, wantResolved: true},
+		{name: "one-off charge", updated: date(3, 1),
+			transactions: []*paddle.Transaction{
+				billed("txn_1", "sub_1", paddle.TransactionOriginSubscriptionRecurring, date(2, 10), basic, 2),
+				billed("txn_2", "sub_1", paddle.TransactionOriginSubscriptionCharge, date(2, 12), basic, 7),
+			},
+			wantQuantity: 2, wantResolved: true},
+		{name: "updated after the billing", updated: date(3, 1),
+			transactions: []*paddle.Transaction{
+				billed("txn_1", "sub_1", paddle.TransactionOriginSubscriptionRecurring, date(2, 10), basic, 2),
+				billed("txn_2", "sub_1", paddle.TransactionOriginSubscriptionUpdate, date(2, 12), basic, 3),
+			},
+			wantQuantity: 5},
+		{name: "billed period ended", updated: date(3, 1),
+			transactions: []*paddle.Transaction{billed("txn_1", "sub_1", paddle.TransactionOriginSubscriptionRecurring, date(1, 10), basic, 2)},
+			wantQuantity: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &report{billed: map[string][]*paddle.Transaction{}}
+			for _, txn := range tt.transactions {
+				r.addBilled(txn)
+			}
+			sub := subscription("sub_1", "ctm_1", date(1, 10), tt.updated, basic, 5)
+			items, resolved := r.itemsAt(sub, date(2, 15))
+			if len(items) != 1 || items[0].quantity != tt.wantQuantity || resolved != tt.wantResolved {
+				t.Errorf("itemsAt() = %+v, %v, want quantity %d, %v", items, resolved, tt.wantQuantity, tt.wantResolved)
+			}
+		})
+	}
+}
+
+func TestPayingAt(t *testing.T) {
+	paused := func(sub *paddle.Subscription, at time.Time) *paddle.Subscription {
+		sub.Status = paddle.SubscriptionStatusPaused
+		sub.PausedAt = &at
+		return sub
+	}
+	tests := []struct {
+		name         string
+		sub          *paddle.Subscription
+		transactions []*paddle.Transaction
+		wantPaying   bool
+		wantResolved bool
+	}{
+		{name: "not started", sub: subscription("sub_1", "ctm_1", date(3, 1), date(3, 1), basic, 1), wantResolved: true},
+		{name: "unchanged since", sub: subscription("sub_1", "ctm_1", date(1, 10), date(2, 1), basic, 1), wantPaying: true, wantResolved: true},
+		{name: "canceled before", sub: canceled(subscription("sub_1", "ctm_1", date(1, 10), date(2, 1), basic, 1), date(2, 10)), wantResolved: true},
+		{name: "canceled after", sub: canceled(subscription("sub_1", "ctm_1", date(1, 10), date(2, 1), basic, 1), date(3, 10)),
+			transactions: []*paddle.Transaction{billed("txn_1", "sub_1", paddle.TransactionOriginSubscriptionRecurring, date(2, 10), basic, 1)},
+			wantPaying:   true, wantResolved: true},
+		{name: "paused before", sub: paused(subscription("sub_1", "ctm_1", date(1, 10), date(2, 10), basic, 1), date(2, 10)), wantResolved: true},
+		{name: "billed period", sub: subscription("sub_1", "ctm_1", date(1, 10), date(3, 1), basic, 1),
+			transactions: []*paddle.Transaction{
+				billed("txn_1", "sub_1", paddle.TransactionOriginWeb, date(1, 10), basic, 1),
+				billed("txn_2", "sub_1", paddle.TransactionOriginSubscriptionRecurring, date(2, 10), basic, 1),
+			},
+			wantPaying: true, wantResolved: true},
+		{name: "paused and resumed since", sub: subscription("sub_1", "ctm_1", date(1, 10), date(3, 1), basic, 1),
+			transactions: []*paddle.Transaction{
+				billed("txn_1", "sub_1", paddle.TransactionOriginWeb, date(1, 10), basic, 1),
+				billed("txn_2", "sub_1", paddle.TransactionOriginSubscriptionRecurring, date(3, 1), basic, 1),
+			},
+			wantResolved: true},
+		{name: "no transactions", sub: subscription("sub_1", "ctm_1", date(1, 10), date(3, 1), basic, 1), wantPaying: true},
+		{name: "billed period ended", sub: subscription("sub_1", "ctm_1", date(1, 10), date(3, 1), basic, 1),
+			transactions: []*paddle.Transaction{billed("txn_1", "sub_1", paddle.TransactionOriginWeb, date(1, 10), basic, 1)},
+			wantPaying:   true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &report{billed: map[string][]*paddle.Transaction{}}
+			for _, txn := range tt.transactions {
+				r.addBilled(txn)
+			}
+			paying, resolved := r.payingAt(tt.sub, date(2, 15))
+			if paying != tt.wantPaying || resolved != tt.wantResolved {
+				t.Errorf("payingAt() = %v, %v, want %v, %v", paying, resolved, tt.wantPaying, tt.wantResolved)
+			}
+		})
+	}
+}
+
+func TestComputePausedAtFrom(t *testing.T) {
+	// Paused from 2 January to 15 February, so not paying at From.
+	source := &fakeSource{
+		subscriptions: []*paddle.Subscription{subscription("sub_1", "ctm_1", date(12, 2).AddDate(-1, 0, 0), date(2, 15), basic, 1)},
+		transactions: []*paddle.Transaction{
+			billed("txn_1", "sub_1", paddle.TransactionOriginSubscriptionRecurring, date(12, 2).AddDate(-1, 0, 0), basic, 1),
+			billed("txn_2", "sub_1", paddle.TransactionOriginSubscriptionRecurring, date(2, 15), basic, 1),
+		},
+	}
+	report, err := Compute(context.Background(), source, Params{From: date(2, 1), To: date(3, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := report.Segments[0]
+	wantMoney(t, "StartMRR", m.StartMRR, 0)
+	wantMoney(t, "MRR", m.MRR, 1000)
+	if m.StartCustomers != 0 || m.NewCustomers != 1 || m.Unresolved != 0 {
+		t.Errorf("customers = %d at start and %d new with %d unresolved, want 0, 1 and 0", m.StartCustomers, m.NewCustomers, m.Unresolved)
+	}
+}
+
+func TestComputeUnresolved(t *testing.T) {
+	source := &fakeSource{subscriptions: []*paddle.Subscription{subscription("sub_1", "ctm_1", date(1, 5), date(3, 10), pro, 1)}}
+	report, err := Compute(context.Background(), source, Params{From: date(2, 1), To: date(4, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := report.Segments[0]
+	wantMoney(t, "StartMRR", m.StartMRR, 3000)
+	if m.Unresolved != 1 || m.NetRevenueRetention != nil {
+		t.Errorf("Unresolved = %d and NetRevenueRetention = %v, want 1 and nil", m.Unresolved, m.NetRevenueRetention)
+	}
+}
+
+func TestCohorts(t *testing.T) {
+	source := &fakeSource{
+		subscriptions: []*paddle.Subscription{
+			subscription("sub_a", "ctm_a", date(1, 10), date(1, 10), basic, 1),
+			canceled(subscription("sub_b", "ctm_b", date(1, 20), date(1, 20), basic, 2), date(2, 15)),
+			subscription("sub_c", "ctm_c", date(2, 3), date(2, 3), pro, 1),
+			// Started before the range, so in no cohort.
+			subscription("sub_d", "ctm_d", date(12, 1).AddDate(-1, 0, 0), date(1, 1), pro, 1),
+			// A customer's later subscriptions stay in their first cohort.
+			subscription("sub_a2", "ctm_a", date(2, 20), date(2, 20), pro, 1),
+		},
+		transactions: []*paddle.Transaction{
+			billed("txn_b_1", "sub_b", paddle.TransactionOriginWeb, date(1, 20), basic, 2),
+		},
+	}
+	report, err := Compute(context.Background(), source, Params{From: date(1, 1), To: date(3, 15)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type period struct {
+		at                time.Time
+		customers         int
+		mrr               int64
+		customerRetention float64
+		revenueRetention  float64
+	}
+	want := []struct {
+		month     time.Time
+		customers int
+		periods   []period
+	}{
+		{date(1, 1), 2, []period{
+			{date(2, 1), 2, 3000, 1, 1},
+			{date(3, 1), 1, 4000, 0.5, 4000.0 / 3000},
+			{date(3, 15), 1, 4000, 0.5, 4000.0 / 3000},
+		}},
+		{date(2, 1), 1, []period{
+			{date(3, 1), 1, 3000, 1, 1},
+			{date(3, 15), 1, 3000, 1, 1},
+		}},
+	}
+	if len(report.Cohorts) != len(want) {
+		t.Fatalf("Compute() returned %d cohorts, want %d", len(report.Cohorts), len(want))
+	}
+	for i, c := range report.Cohorts {
+		w := want[i]
+		if !c.Month.Equal(w.month) || c.Customers != w.customers || len(c.Periods) != len(w.periods) {
+			t.Errorf("cohort %d = %v with %d customers and %d periods, want %v with %d and %d",
+				i, c.Month, c.Customers, len(c.Periods), w.month, w.customers, len(w.periods))
+			continue
+		}
+		for j, p := range c.Periods {
+			wp := w.periods[j]
+			if !p.At.Equal(wp.at) || p.Customers != wp.customers || p.CustomerRetention != wp.customerRetention ||
+				p.RevenueRetention == nil || *p.RevenueRetention != wp.revenueRetention || p.Unresolved != 0 {
+				t.Errorf("cohort %v at %v = %+v, want %+v", c.Month, p.At, p, wp)
+			}
+			wantMoney(t, "MRR", p.MRR, wp.mrr)
+		}
+	}
+}
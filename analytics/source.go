@@ -0,0 +1,74 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/texm/go-paddle"
+	"github.com/texm/go-paddle/mirror"
+)
+
+// Source provides the subscriptions and transactions metrics are computed
+// from.
+type Source interface {
+	Subscriptions(ctx context.Context) ([]*paddle.Subscription, error)
+	// Transactions returns the transactions billed from from, inclusive, to
+	// to, exclusive.
+	Transactions(ctx context.Context, from time.Time, to time.Time) ([]*paddle.Transaction, error)
+	// Address returns nil if the address does not exist.
+	Address(ctx context.Context, customerId string, id string) (*paddle.Address, error)
+}
+
+type apiSource struct {
+	api paddle.API
+}
+
+// FromAPI lists resources from the Paddle API.
+func FromAPI(api paddle.API) Source {
+	return &apiSource{api: api}
+}
+
+func (s *apiSource) Subscriptions(ctx context.Context) ([]*paddle.Subscription, error) {
+	return s.api.SubscriptionsAPI().List(ctx, nil)
+}
+
+func (s *apiSource) Transactions(ctx context.Context, from time.Time, to time.Time) ([]*paddle.Transaction, error) {
+	return s.api.TransactionsAPI().List(ctx, &paddle.ListTransactionsParams{
+		Include:  &paddle.TransactionIncludeParam{Address: true},
+		BilledAt: paddle.Between(from, to),
+	})
+}
+
+func (s *apiSource) Address(ctx context.Context, customerId string, id string) (*paddle.Address, error) {
+	address, getErr := s.api.AddressesAPI().Get(ctx, customerId, id)
+	if errors.Is(getErr, paddle.ErrNotFound) {
+		return nil, nil
+	}
+	return address, getErr
+}
+
+type mirrorSource struct {
+	m *mirror.Mirror
+}
+
+// FromMirror reads resources from a local mirror.
+func FromMirror(m *mirror.Mirror) Source {
+	return &mirrorSource{m: m}
+}
+
+func (s *mirrorSource) Subscriptions(ctx context.Context) ([]*paddle.Subscription, error) {
+	return s.m.Subscriptions(ctx, mirror.SubscriptionQuery{})
+}
+
+func (s *mirrorSource) Transactions(ctx context.Context, from time.Time, to time.Time) ([]*paddle.Transaction, error) {
+	return s.m.Transactions(ctx, mirror.TransactionQuery{BilledFrom: from, BilledTo: to})
+}
+
+func (s *mirrorSource) Address(ctx context.Context, _ string, id string) (*paddle.Address, error) {
+	address, getErr := s.m.Address(ctx, id)
+	if errors.Is(getErr, mirror.ErrNotFound) {
+		return nil, nil
+	}
+	return address, getErr
+}
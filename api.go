@@ -0,0 +1,17 @@
+package paddle
+
+import (
+	"context"
+	"net/http"
+)
+
+// API is the set of operations provided by *Client. Accept it rather than
+// *Client to substitute a fake, such as the mocks in package paddlemock.
+type API interface {
+	generatedAPI
+
+	TestAuthentication(ctx context.Context) error
+	ParseWebhook(req *http.Request) (*WebhookEvent, error)
+}
+
+var _ API = (*Client)(nil)
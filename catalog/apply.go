@@ -0,0 +1,111 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/texm/go-paddle"
+)
+
+type Result struct {
+	// Ids maps every key in the manifest to its Paddle ID.
+	Ids map[string]string `json:"ids"`
+	// Applied holds the changes made, in order.
+	Applied []*Change `json:"applied"`
+}
+
+// Apply makes the changes in the plan. It stops at the first failure; the
+// result then holds the changes already applied, and running Diff again
+// produces a plan for the remainder.
+func Apply(ctx context.Context, api paddle.API, plan *Plan) (*Result, error) {
+	result := &Result{Ids: map[string]string{}}
+	for key, id := range plan.ids {
+		result.Ids[key] = id
+	}
+
+	for _, c := range plan.Changes {
+		id, applyErr := apply(ctx, api, c, result.Ids)
+		if applyErr != nil {
+			return result, fmt.Errorf("failed to %s %s %s: %w", c.Action, c.Kind, c.Key, applyErr)
+		}
+		if c.Action != ActionArchive {
+			result.Ids[c.Key] = id
+		}
+		result.Applied = append(result.Applied, c)
+	}
+	return result, nil
+}
+
+func apply(ctx context.Context, api paddle.API, c *Change, ids map[string]string) (string, error) {
+	products, prices := api.ProductsAPI(), api.PricesAPI()
+	archived := paddle.StatusArchived
+
+	switch {
+	case c.Kind == KindProduct && c.Action == ActionCreate:
+		data := customData(c.product.CustomData, c.product.Key)
+		product, createErr := products.Create(ctx, &paddle.CreateProductParams{
+			Name:        c.product.Name,
+			TaxCategory: c.product.TaxCategory,
+			Description: optional(c.product.Description),
+			ImageUrl:    optional(c.product.ImageUrl),
+			CustomData:  &data,
+		})
+		if createErr != nil {
+			return "", createErr
+		}
+		return product.Id, nil
+
+	case c.Kind == KindProduct && c.Action == ActionUpdate:
+		product, updateErr := products.Update(ctx, c.Id, c.productUpdate)
+		if updateErr != nil {
+			return "", updateErr
+		}
+		return product.Id, nil
+
+	case c.Kind == KindProduct && c.Action == ActionArchive:
+		_, archiveErr := products.Update(ctx, c.Id, &paddle.UpdateProductParams{Status: &archived})
+		return c.Id, archiveErr
+
+	case c.Kind == KindPrice && c.Action == ActionCreate:
+		productId, ok := ids[c.productKey]
+		if !ok {
+			return "", fmt.Errorf("product %s has no id", c.productKey)
+		}
+		data := customData(c.price.CustomData, c.price.Key)
+		price, createErr := prices.Create(ctx, &paddle.CreatePriceParams{
+			ProductId:          productId,
+			Description:        c.price.Description,
+			Name:               optional(c.price.Name),
+			BillingCycle:       c.price.BillingCycle,
+			TrialPeriod:        c.price.TrialPeriod,
+			TaxMode:            c.price.TaxMode,
+			UnitPrice:          c.price.UnitPrice,
+			UnitPriceOverrides: c.price.UnitPriceOverrides,
+			Quantity:           c.price.Quantity,
+			CustomData:         &data,
+		})
+		if createErr != nil {
+			return "", createErr
+		}
+		return price.Id, nil
+
+	case c.Kind == KindPrice && c.Action == ActionUpdate:
+		price, updateErr := prices.Update(ctx, c.Id, c.priceUpdate)
+		if updateErr != nil {
+			return "", updateErr
+		}
+		return price.Id, nil
+
+	case c.Kind == KindPrice && c.Action == ActionArchive:
+		_, archiveErr := prices.Update(ctx, c.Id, &paddle.UpdatePriceParams{Status: &archived})
+		return c.Id, archiveErr
+	}
+	return "", fmt.Errorf("unsupported change %s %s", c.Action, c.Kind)
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
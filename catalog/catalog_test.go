@@ -0,0 +1,247 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/texm/go-paddle"
+	"github.com/texm/go-paddle/paddletest"
+)
+
+const manifestYAML = `
+products:
+  - key: pro
+    name: Pro
+    description: For teams
+    tax_category: saas
+    custom_data:
+      tier: 2
+    prices:
+      - key: pro-monthly
+        description: Monthly
+        billing_cycle: {frequency: 1, interval: month}
+        trial_period: {frequency: 14, interval: day}
+        unit_price: {amount: "1500", currency_code: USD}
+        quantity: {minimum: 1, maximum: 50}
+      - key: pro-yearly
+        description: Yearly
+        billing_cycle: {frequency: 1, interval: year}
+        unit_price: {amount: "15000", currency_code: USD}
+        unit_price_overrides:
+          - country_codes: [GB]
+            unit_price: {amount: "12000", currency_code: GBP}
+`
+
+const manifestJSON = `{
+  "products": [{
+    "key": "pro",
+    "name": "Pro",
+    "description": "For teams",
+    "tax_category": "saas",
+    "custom_data": {"tier": 2},
+    "prices": [{
+      "key": "pro-monthly",
+      "description": "Monthly",
+      "billing_cycle": {"frequency": 1, "interval": "month"},
+      "trial_period": {"frequency": 14, "interval": "day"},
+      "unit_price": {"amount": "1500", "currency_code": "USD"},
+      "quantity": {"minimum": 1, "maximum": 50}
+    }, {
+      "key": "pro-yearly",
+      "description": "Yearly",
+      "billing_cycle": {"frequency": 1, "interval": "year"},
+      "unit_price": {"amount": "15000", "currency_code": "USD"},
+      "unit_price_overrides": [{
+        "country_codes": ["GB"],
+        "unit_price": {"amount": "12000", "currency_code": "GBP"}
+      }]
+    }]
+  }]
+}`
+
+func TestParseYAMLMatchesJSON(t *testing.T) {
+	fromYAML, yamlErr := ParseYAML([]byte(manifestYAML))
+	if yamlErr != nil {
+		t.Fatal(yamlErr)
+	}
+	fromJSON, jsonErr := ParseJSON([]byte(manifestJSON))
+	if jsonErr != nil {
+		t.Fatal(jsonErr)
+	}
+	gotYAML, _ := json.Marshal(fromYAML)
+	gotJSON, _ := json.Marshal(fromJSON)
+	if string(gotYAML) != string(gotJSON) {
+		t.Errorf("ParseYAML() = %s, want ParseJSON() = %s", gotYAML, gotJSON)
+	}
+	if q := fromYAML.Products[0].Prices[0].Quantity; q == nil || *q != (paddle.MinMax{Minimum: 1, Maximum: 50}) {
+		t.Errorf("ParseYAML() quantity = %v, want 1-50", q)
+	}
+	if _, err := ParseYAML([]byte("products: [")); !errors.Is(err, ErrInvalidManifest) {
+		t.Errorf("ParseYAML() with invalid YAML = %v, want %v", err, ErrInvalidManifest)
+	}
+}
+
+func newCatalogServer(t *testing.T) (*paddletest.Server, *paddle.Client) {
+	t.Helper()
+	srv := paddletest.NewServer()
+	t.Cleanup(srv.Close)
+	return srv, srv.PaddleClient(nil)
+}
+
+func monthly() *paddle.TimeInterval {
+	return &paddle.TimeInterval{Frequency: 1, Interval: paddle.TimePeriodIntervalMonth}
+}
+
+func usd(minorUnits int64) paddle.Money {
+	return paddle.Money{Amount: paddle.NewAmount(minorUnits), CurrencyCode: paddle.CurrencyCodeUSD}
+}
+
+// addPrice adds a price matching spec to the server, as Apply would have
+// created it.
+func addPrice(srv *paddletest.Server, productId string, spec *Price) *paddle.Price {
+	return srv.AddPrice(paddle.Price{
+		ProductId:    productId,
+		Description:  spec.Description,
+		BillingCycle: spec.BillingCycle,
+		TrialPeriod:  spec.TrialPeriod,
+		TaxMode:      paddle.TaxModeAccountSetting,
+		UnitPrice:    spec.UnitPrice,
+		Quantity:     defaultQuantity,
+		CustomData:   customData(nil, spec.Key),
+	})
+}
+
+func TestDiffApply(t *testing.T) {
+	ctx := context.Background()
+	srv, client := newCatalogServer(t)
+
+	kept := &Price{Key: "basic-monthly", Description: "Monthly", BillingCycle: monthly(), UnitPrice: usd(500)}
+	basic := srv.AddProduct(paddle.Product{Name: "Basic (old)", TaxCategory: paddle.TaxCategorySaas, CustomData: customData(nil, "basic")})
+	drifted := addPrice(srv, basic.Id, kept)
+	drifted.Quantity = paddle.MinMax{Minimum: 1, Maximum: 10}
+	srv.AddPrice(*drifted)
+
+	legacy := srv.AddProduct(paddle.Product{Name: "Legacy", TaxCategory: paddle.TaxCategorySaas, CustomData: customData(nil, "legacy")})
+	legacyPrice := addPrice(srv, legacy.Id, &Price{Key: "legacy-monthly", Description: "Monthly", UnitPrice: usd(100)})
+	unmanaged := srv.AddProduct(paddle.Product{Name: "Unmanaged", TaxCategory: paddle.TaxCategorySaas})
+
+	m := &Manifest{Products: []*Product{
+		{Key: "basic", Name: "Basic", TaxCategory: paddle.TaxCategorySaas, Prices: []*Price{kept}},
+		{Key: "pro", Name: "Pro", TaxCategory: paddle.TaxCategorySaas, CustomData: paddle.CustomData{"tier": "2"}, Prices: []*Price{
+			{Key: "pro-monthly", Description: "Monthly", BillingCycle: monthly(), UnitPrice: usd(1500)},
+		}},
+	}}
+
+	plan, diffErr := Diff(ctx, client, m)
+	if diffErr != nil {
+		t.Fatal(diffErr)
+	}
+	want := []Change{
+		{Action: ActionUpdate, Kind: KindProduct, Key: "basic", Id: basic.Id, Fields: []string{"name"}},
+		{Action: ActionUpdate, Kind: KindPrice, Key: "basic-monthly", Id: drifted.Id, Fields: []string{"quantity"}},
+		{Action: ActionCreate, Kind: KindProduct, Key: "pro"},
+		{Action: ActionCreate, Kind: KindPrice, Key: "pro-monthly"},
+		{Action: ActionArchive, Kind: KindPrice, Key: "legacy-monthly", Id: legacyPrice.Id},
+		{Action: ActionArchive, Kind: KindProduct, Key: "legacy", Id: legacy.Id},
+	}
+	if len(plan.Changes) != len(want) {
+		t.Fatalf("Diff() = %s, want %d changes", plan, len(want))
+	}
+	for i, c := range plan.Changes {
+		got := Change{Action: c.Action, Kind: c.Kind, Key: c.Key, Id: c.Id, Fields: c.Fields}
+		if !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("change %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+
+	result, applyErr := Apply(ctx, client, plan)
+	if applyErr != nil {
+		t.Fatal(applyErr)
+	}
+	if len(result.Applied) != len(want) {
+		t.Errorf("Apply() applied %d changes, want %d", len(result.Applied), len(want))
+	}
+	for _, key := range []string{"basic", "basic-monthly", "pro", "pro-monthly"} {
+		if result.Ids[key] == "" {
+			t.Errorf("Apply() ids = %v, want an id for %s", result.Ids, key)
+		}
+	}
+
+	pro, getErr := client.Products.Get(ctx, result.Ids["pro"], false)
+	if getErr != nil {
+		t.Fatal(getErr)
+	}
+	if pro.CustomData[KeyField] != "pro" || pro.CustomData["tier"] != "2" {
+		t.Errorf("created product custom_data = %v, want its key and tier", pro.CustomData)
+	}
+	proMonthly, getErr := client.Prices.Get(ctx, result.Ids["pro-monthly"], false)
+	if getErr != nil {
+		t.Fatal(getErr)
+	}
+	if proMonthly.CustomData[KeyField] != "pro-monthly" || proMonthly.ProductId != pro.Id {
+		t.Errorf("created price = %+v, want key pro-monthly under %s", proMonthly, pro.Id)
+	}
+	if p, _ := client.Prices.Get(ctx, drifted.Id, false); p.Quantity != defaultQuantity {
+		t.Errorf("updated price quantity = %+v, want %+v", p.Quantity, defaultQuantity)
+	}
+	for id, wantStatus := range map[string]paddle.Status{legacy.Id: paddle.StatusArchived, unmanaged.Id: paddle.StatusActive} {
+		if p, _ := client.Products.Get(ctx, id, false); p.Status != wantStatus {
+			t.Errorf("product %s status = %s, want %s", id, p.Status, wantStatus)
+		}
+	}
+	if p, _ := client.Prices.Get(ctx, legacyPrice.Id, false); p.Status != paddle.StatusArchived {
+		t.Errorf("price %s status = %s, want archived", legacyPrice.Id, p.Status)
+	}
+
+	again, againErr := Diff(ctx, client, m)
+	if againErr != nil {
+		t.Fatal(againErr)
+	}
+	if !again.Empty() {
+		t.Errorf("Diff() after Apply = %s, want no changes", again)
+	}
+}
+
+func TestDiffErrors(t *testing.T) {
+	week := &paddle.TimeInterval{Frequency: 7, Interval: paddle.TimePeriodIntervalDay}
+	tests := []struct {
+		name  string
+		price *Price
+		// moved places the existing price under a different product.
+		moved   bool
+		wantErr string
+	}{
+		{"price moved between products", &Price{BillingCycle: monthly(), TrialPeriod: week}, true, "cannot move between products"},
+		{"billing_cycle removed", &Price{TrialPeriod: week}, false, "cannot be removed"},
+		{"trial_period removed", &Price{BillingCycle: monthly()}, false, "cannot be removed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, client := newCatalogServer(t)
+			plan := srv.AddProduct(paddle.Product{Name: "Plan", TaxCategory: paddle.TaxCategorySaas, CustomData: customData(nil, "plan")})
+			other := srv.AddProduct(paddle.Product{Name: "Other", TaxCategory: paddle.TaxCategorySaas, CustomData: customData(nil, "other")})
+			owner := plan.Id
+			if tt.moved {
+				owner = other.Id
+			}
+			addPrice(srv, owner, &Price{
+				Key: "plan-monthly", Description: "Monthly", UnitPrice: usd(500),
+				BillingCycle: monthly(), TrialPeriod: week,
+			})
+			tt.price.Key, tt.price.Description, tt.price.UnitPrice = "plan-monthly", "Monthly", usd(500)
+
+			m := &Manifest{Products: []*Product{
+				{Key: "plan", Name: "Plan", TaxCategory: paddle.TaxCategorySaas, Prices: []*Price{tt.price}},
+				{Key: "other", Name: "Other", TaxCategory: paddle.TaxCategorySaas},
+			}}
+			_, err := Diff(context.Background(), client, m)
+			if !errors.Is(err, ErrInvalidManifest) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Diff() = %v, want %v: %s", err, ErrInvalidManifest, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,8 @@
+module github.com/texm/go-paddle/catalog
+
+go 1.21
+
+require (
+	github.com/texm/go-paddle v0.1.0
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,152 @@
+// Package catalog keeps the products and prices of a Paddle account in sync
+// with a declarative manifest, so sandbox and live can be held identical.
+//
+//	m, err := catalog.Load("catalog.yaml")
+//	plan, err := catalog.Diff(ctx, client, m)
+//	fmt.Print(plan)
+//	result, err := catalog.Apply(ctx, client, plan)
+//
+// Every product and price in the manifest has a key which is stored in its
+// custom_data under KeyField. Resources are matched by that key, so
+// manifests never contain environment-specific Paddle IDs; Apply reports
+// the ID each key resolved to instead.
+package catalog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/texm/go-paddle"
+)
+
+// KeyField is the custom_data field holding a resource's manifest key.
+const KeyField = "catalog_key"
+
+var ErrInvalidManifest = errors.New("invalid catalog manifest")
+
+type Manifest struct {
+	Products []*Product `json:"products"`
+}
+
+type Product struct {
+	Key         string             `json:"key"`
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	TaxCategory paddle.TaxCategory `json:"tax_category"`
+	ImageUrl    string             `json:"image_url,omitempty"`
+	CustomData  paddle.CustomData  `json:"custom_data,omitempty"`
+	Prices      []*Price           `json:"prices"`
+}
+
+type Price struct {
+	Key                string                         `json:"key"`
+	Description        string                         `json:"description"`
+	Name               string                         `json:"name,omitempty"`
+	BillingCycle       *paddle.TimeInterval           `json:"billing_cycle,omitempty"`
+	TrialPeriod        *paddle.TimeInterval           `json:"trial_period,omitempty"`
+	TaxMode            paddle.TaxMode                 `json:"tax_mode,omitempty"`
+	UnitPrice          paddle.Money                   `json:"unit_price"`
+	UnitPriceOverrides []paddle.CurrencyPriceOverride `json:"unit_price_overrides,omitempty"`
+	Quantity           *paddle.MinMax                 `json:"quantity,omitempty"`
+	CustomData         paddle.CustomData              `json:"custom_data,omitempty"`
+}
+
+// Load reads a manifest from a .json, .yaml or .yml file.
+func Load(path string) (*Manifest, error) {
+	data, readErr := os.ReadFile(path)
+	if readErr != nil {
+		return nil, readErr
+	}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		return ParseJSON(data)
+	case ".yaml", ".yml":
+		return ParseYAML(data)
+	default:
+		return nil, fmt.Errorf("%w: unsupported file extension %q", ErrInvalidManifest, ext)
+	}
+}
+
+func ParseJSON(data []byte) (*Manifest, error) {
+	var m Manifest
+	if jsonErr := json.Unmarshal(data, &m); jsonErr != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidManifest, jsonErr)
+	}
+	return &m, m.Validate()
+}
+
+// ParseYAML parses a YAML manifest. It uses the same field names as JSON,
+// with amounts in the currency's minor units, e.g. amount: "1000" for $10.
+func ParseYAML(data []byte) (*Manifest, error) {
+	var doc any
+	if yamlErr := yaml.Unmarshal(data, &doc); yamlErr != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidManifest, yamlErr)
+	}
+	asJSON, jsonErr := json.Marshal(doc)
+	if jsonErr != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidManifest, jsonErr)
+	}
+	return ParseJSON(asJSON)
+}
+
+func (m *Manifest) Validate() error {
+	keys := map[string]bool{}
+	unique := func(key string) error {
+		if key == "" {
+			return fmt.Errorf("%w: every product and price needs a key", ErrInvalidManifest)
+		}
+		if keys[key] {
+			return fmt.Errorf("%w: duplicate key %q", ErrInvalidManifest, key)
+		}
+		keys[key] = true
+		return nil
+	}
+
+	for _, p := range m.Products {
+		if err := unique(p.Key); err != nil {
+			return err
+		}
+		if p.Name == "" {
+			return fmt.Errorf("%w: product %s: name is required", ErrInvalidManifest, p.Key)
+		}
+		if !p.TaxCategory.IsKnown() {
+			return fmt.Errorf("%w: product %s: unknown tax_category %q", ErrInvalidManifest, p.Key, p.TaxCategory)
+		}
+		for _, price := range p.Prices {
+			if err := unique(price.Key); err != nil {
+				return err
+			}
+			if price.Description == "" {
+				return fmt.Errorf("%w: price %s: description is required", ErrInvalidManifest, price.Key)
+			}
+			if !price.UnitPrice.CurrencyCode.IsKnown() {
+				return fmt.Errorf("%w: price %s: unknown currency %q", ErrInvalidManifest, price.Key, price.UnitPrice.CurrencyCode)
+			}
+			if price.TaxMode != "" && !price.TaxMode.IsKnown() {
+				return fmt.Errorf("%w: price %s: unknown tax_mode %q", ErrInvalidManifest, price.Key, price.TaxMode)
+			}
+		}
+	}
+	return nil
+}
+
+// customData returns the resource's custom data with its key recorded.
+func customData(data paddle.CustomData, key string) paddle.CustomData {
+	withKey := paddle.CustomData{}
+	for k, v := range data {
+		withKey[k] = v
+	}
+	withKey[KeyField] = key
+	return withKey
+}
+
+func keyOf(data paddle.CustomData) string {
+	key, _ := data[KeyField].(string)
+	return key
+}
@@ -0,0 +1,287 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/texm/go-paddle"
+)
+
+type Action string
+
+const (
+	ActionCreate  = Action("create")
+	ActionUpdate  = Action("update")
+	ActionArchive = Action("archive")
+)
+
+type Kind string
+
+const (
+	KindProduct = Kind("product")
+	KindPrice   = Kind("price")
+)
+
+// Change is a single create, update or archive needed to bring the account
+// in line with the manifest.
+type Change struct {
+	Action Action `json:"action"`
+	Kind   Kind   `json:"kind"`
+	Key    string `json:"key"`
+	// Id is the Paddle ID of the existing resource, empty for creates.
+	Id string `json:"id,omitempty"`
+	// Fields lists the fields an update changes.
+	Fields []string `json:"fields,omitempty"`
+
+	productUpdate *paddle.UpdateProductParams
+	priceUpdate   *paddle.UpdatePriceParams
+	product       *Product
+	price         *Price
+	productKey    string
+}
+
+type Plan struct {
+	Changes []*Change `json:"changes"`
+
+	// ids holds the Paddle ID of every key which already exists.
+	ids map[string]string
+}
+
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+func (p *Plan) String() string {
+	if p.Empty() {
+		return "No changes. The catalog matches the manifest.\n"
+	}
+	var b strings.Builder
+	symbols := map[Action]string{ActionCreate: "+", ActionUpdate: "~", ActionArchive: "-"}
+	for _, c := range p.Changes {
+		fmt.Fprintf(&b, "%s %s %s", symbols[c.Action], c.Kind, c.Key)
+		if c.Id != "" {
+			fmt.Fprintf(&b, " (%s)", c.Id)
+		}
+		if len(c.Fields) > 0 {
+			fmt.Fprintf(&b, ": %s", strings.Join(c.Fields, ", "))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+var allStatuses = []paddle.Status{paddle.StatusActive, paddle.StatusArchived}
+
+// Diff compares the manifest with the account's products and prices.
+// Products and prices carrying a key which is no longer in the manifest
+// are archived; resources without a key are left alone.
+func Diff(ctx context.Context, api paddle.API, m *Manifest) (*Plan, error) {
+	if validateErr := m.Validate(); validateErr != nil {
+		return nil, validateErr
+	}
+
+	products, productsErr := api.ProductsAPI().List(ctx, &paddle.ListProductsParams{Status: allStatuses})
+	if productsErr != nil {
+		return nil, fmt.Errorf("failed to list products: %w", productsErr)
+	}
+	prices, pricesErr := api.PricesAPI().List(ctx, &paddle.ListPricesParams{Status: allStatuses})
+	if pricesErr != nil {
+		return nil, fmt.Errorf("failed to list prices: %w", pricesErr)
+	}
+
+	remoteProducts := map[string]*paddle.Product{}
+	for _, p := range products {
+		if key := keyOf(p.CustomData); key != "" {
+			remoteProducts[key] = p
+		}
+	}
+	remotePrices := map[string]*paddle.Price{}
+	for _, p := range prices {
+		if key := keyOf(p.CustomData); key != "" {
+			remotePrices[key] = p
+		}
+	}
+
+	plan := &Plan{ids: map[string]string{}}
+	wanted := map[string]bool{}
+	for _, spec := range m.Products {
+		wanted[spec.Key] = true
+		remote, exists := remoteProducts[spec.Key]
+		if !exists {
+			plan.Changes = append(plan.Changes, &Change{Action: ActionCreate, Kind: KindProduct, Key: spec.Key, product: spec})
+		} else {
+			plan.ids[spec.Key] = remote.Id
+			if update, fields := diffProduct(spec, remote); len(fields) > 0 {
+				plan.Changes = append(plan.Changes, &Change{
+					Action: ActionUpdate, Kind: KindProduct, Key: spec.Key, Id: remote.Id,
+					Fields: fields, productUpdate: update,
+				})
+			}
+		}
+
+		for _, priceSpec := range spec.Prices {
+			wanted[priceSpec.Key] = true
+			remotePrice, priceExists := remotePrices[priceSpec.Key]
+			if !priceExists {
+				plan.Changes = append(plan.Changes, &Change{
+					Action: ActionCreate, Kind: KindPrice, Key: priceSpec.Key,
+					price: priceSpec, productKey: spec.Key,
+				})
+				continue
+			}
+			if !exists || remotePrice.ProductId != remote.Id {
+				return nil, fmt.Errorf("%w: price %s belongs to product %s, not %s; prices cannot move between products",
+					ErrInvalidManifest, priceSpec.Key, remotePrice.ProductId, spec.Key)
+			}
+			plan.ids[priceSpec.Key] = remotePrice.Id
+			update, fields, diffErr := diffPrice(priceSpec, remotePrice)
+			if diffErr != nil {
+				return nil, diffErr
+			}
+			if len(fields) > 0 {
+				plan.Changes = append(plan.Changes, &Change{
+					Action: ActionUpdate, Kind: KindPrice, Key: priceSpec.Key, Id: remotePrice.Id,
+					Fields: fields, priceUpdate: update,
+				})
+			}
+		}
+	}
+
+	// Archive prices before their products.
+	for _, p := range prices {
+		if key := keyOf(p.CustomData); key != "" && !wanted[key] && p.Status == paddle.StatusActive {
+			plan.Changes = append(plan.Changes, &Change{Action: ActionArchive, Kind: KindPrice, Key: key, Id: p.Id})
+		}
+	}
+	for _, p := range products {
+		if key := keyOf(p.CustomData); key != "" && !wanted[key] && p.Status == paddle.StatusActive {
+			plan.Changes = append(plan.Changes, &Change{Action: ActionArchive, Kind: KindProduct, Key: key, Id: p.Id})
+		}
+	}
+	return plan, nil
+}
+
+func diffProduct(spec *Product, remote *paddle.Product) (*paddle.UpdateProductParams, []string) {
+	update := &paddle.UpdateProductParams{}
+	var fields []string
+	if spec.Name != remote.Name {
+		update.Name = &spec.Name
+		fields = append(fields, "name")
+	}
+	if spec.Description != deref(remote.Description) {
+		update.Description = &spec.Description
+		fields = append(fields, "description")
+	}
+	if spec.TaxCategory != remote.TaxCategory {
+		update.TaxCategory = &spec.TaxCategory
+		fields = append(fields, "tax_category")
+	}
+	if spec.ImageUrl != deref(remote.ImageUrl) {
+		update.ImageUrl = &spec.ImageUrl
+		fields = append(fields, "image_url")
+	}
+	if data := customData(spec.CustomData, spec.Key); !equalJSON(data, remote.CustomData) {
+		update.CustomData = &data
+		fields = append(fields, "custom_data")
+	}
+	if remote.Status != paddle.StatusActive {
+		status := paddle.StatusActive
+		update.Status = &status
+		fields = append(fields, "status")
+	}
+	return update, fields
+}
+
+// defaultQuantity is the quantity Paddle gives a price created without one.
+var defaultQuantity = paddle.MinMax{Minimum: 1, Maximum: 100}
+
+func diffPrice(spec *Price, remote *paddle.Price) (*paddle.UpdatePriceParams, []string, error) {
+	// Paddle cannot remove a billing cycle or trial from an existing price.
+	if (spec.BillingCycle == nil && remote.BillingCycle != nil) || (spec.TrialPeriod == nil && remote.TrialPeriod != nil) {
+		return nil, nil, fmt.Errorf("%w: price %s: billing_cycle and trial_period cannot be removed; use a new key",
+			ErrInvalidManifest, spec.Key)
+	}
+
+	update := &paddle.UpdatePriceParams{}
+	var fields []string
+	if spec.Description != remote.Description {
+		update.Description = &spec.Description
+		fields = append(fields, "description")
+	}
+	if spec.Name != remote.Name {
+		update.Name = &spec.Name
+		fields = append(fields, "name")
+	}
+	if !equalJSON(spec.BillingCycle, remote.BillingCycle) {
+		update.BillingCycle = spec.BillingCycle
+		fields = append(fields, "billing_cycle")
+	}
+	if !equalJSON(spec.TrialPeriod, remote.TrialPeriod) {
+		update.TrialPeriod = spec.TrialPeriod
+		fields = append(fields, "trial_period")
+	}
+	taxMode := spec.TaxMode
+	if taxMode == "" {
+		taxMode = paddle.TaxModeAccountSetting
+	}
+	if taxMode != remote.TaxMode {
+		update.TaxMode = &taxMode
+		fields = append(fields, "tax_mode")
+	}
+	if cmp, cmpErr := spec.UnitPrice.Cmp(remote.UnitPrice); cmpErr != nil || cmp != 0 {
+		update.UnitPrice = &spec.UnitPrice
+		fields = append(fields, "unit_price")
+	}
+	if !equalJSON(nonNil(spec.UnitPriceOverrides), nonNil(remote.UnitPriceOverrides)) {
+		overrides := nonNil(spec.UnitPriceOverrides)
+		update.UnitPriceOverrides = &overrides
+		fields = append(fields, "unit_price_overrides")
+	}
+	quantity := defaultQuantity
+	if spec.Quantity != nil {
+		quantity = *spec.Quantity
+	}
+	if quantity != remote.Quantity {
+		update.Quantity = &quantity
+		fields = append(fields, "quantity")
+	}
+	if data := customData(spec.CustomData, spec.Key); !equalJSON(data, remote.CustomData) {
+		update.CustomData = &data
+		fields = append(fields, "custom_data")
+	}
+	if remote.Status != paddle.StatusActive {
+		status := paddle.StatusActive
+		update.Status = &status
+		fields = append(fields, "status")
+	}
+	return update, fields, nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+// equalJSON compares values by their JSON encoding, which normalises map
+// ordering and numbers decoded from different sources.
+func equalJSON(a any, b any) bool {
+	normalise := func(v any) string {
+		data, _ := json.Marshal(v)
+		var decoded any
+		_ = json.Unmarshal(data, &decoded)
+		data, _ = json.Marshal(decoded)
+		return string(data)
+	}
+	return normalise(a) == normalise(b)
+}
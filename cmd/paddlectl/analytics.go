@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/texm/go-paddle"
+	"github.com/texm/go-paddle/analytics"
+)
+
+func percent(f float64) string {
+	return strconv.FormatFloat(f*100, 'f', 1, 64) + "%"
+}
+
+func optionalPercent(f *float64) string {
+	if f == nil {
+		return "-"
+	}
+	return percent(*f)
+}
+
+func optionalMoney(m *paddle.Money) string {
+	if m == nil {
+		return "-"
+	}
+	return m.String()
+}
+
+var metricsColumns = []column[analytics.Metrics]{
+	{"currency", func(m *analytics.Metrics) string { return string(m.CurrencyCode) }},
+	{"product_id", func(m *analytics.Metrics) string { return str(optional(m.ProductId)) }},
+	{"country", func(m *analytics.Metrics) string { return str(optional(m.CountryCode)) }},
+	{"start_mrr", func(m *analytics.Metrics) string { return m.StartMRR.String() }},
+	{"mrr", func(m *analytics.Metrics) string { return m.MRR.String() }},
+	{"arr", func(m *analytics.Metrics) string { return m.ARR.String() }},
+	{"new_mrr", func(m *analytics.Metrics) string { return m.NewMRR.String() }},
+	{"expansion_mrr", func(m *analytics.Metrics) string { return m.ExpansionMRR.String() }},
+	{"contraction_mrr", func(m *analytics.Metrics) string { return m.ContractionMRR.String() }},
+	{"churned_mrr", func(m *analytics.Metrics) string { return m.ChurnedMRR.String() }},
+	{"nrr", func(m *analytics.Metrics) string { return optionalPercent(m.NetRevenueRetention) }},
+	{"customers", func(m *analytics.Metrics) string { return strconv.Itoa(m.Customers) }},
+	{"customer_churn", func(m *analytics.Metrics) string { return percent(m.CustomerChurn) }},
+	{"revenue_churn", func(m *analytics.Metrics) string { return percent(m.RevenueChurn) }},
+	{"revenue", func(m *analytics.Metrics) string { return m.Revenue.String() }},
+	{"earnings", func(m *analytics.Metrics) string { return optionalMoney(m.Earnings) }},
+	{"unresolved", func(m *analytics.Metrics) string { return strconv.Itoa(m.Unresolved) }},
+}
+
+// cohortPeriod is one row of the cohorts table.
+type cohortPeriod struct {
+	*analytics.Cohort
+	*analytics.CohortPeriod
+}
+
+var cohortColumns = []column[cohortPeriod]{
+	{"currency", func(p *cohortPeriod) string { return string(p.CurrencyCode) }},
+	{"month", func(p *cohortPeriod) string { return p.Month.Format("2006-01") }},
+	{"customers", func(p *cohortPeriod) string { return strconv.Itoa(p.Cohort.Customers) }},
+	{"at", func(p *cohortPeriod) string { return timestamp(&p.At) }},
+	{"paying", func(p *cohortPeriod) string { return strconv.Itoa(p.CohortPeriod.Customers) }},
+	{"mrr", func(p *cohortPeriod) string { return p.MRR.String() }},
+	{"customer_retention", func(p *cohortPeriod) string { return percent(p.CustomerRetention) }},
+	{"revenue_retention", func(p *cohortPeriod) string { return optionalPercent(p.RevenueRetention) }},
+	{"unresolved", func(p *cohortPeriod) string { return strconv.Itoa(p.Unresolved) }},
+}
+
+// analyticsRange parses the --from and --to flags, along with any others
+// already defined on fs.
+func analyticsRange(name string, fs *flag.FlagSet, args []string) (analytics.Params, error) {
+	from := fs.String("from", "", "RFC 3339 start of the range, inclusive")
+	to := fs.String("to", "", "RFC 3339 end of the range, exclusive; defaults to now")
+	if _, parseErr := parse(fs, args); parseErr != nil {
+		return analytics.Params{}, parseErr
+	}
+	if *from == "" {
+		return analytics.Params{}, fmt.Errorf("%s: --from is required", name)
+	}
+	params := analytics.Params{To: time.Now()}
+	for _, f := range []struct {
+		value string
+		t     *time.Time
+	}{{*from, &params.From}, {*to, &params.To}} {
+		if f.value == "" {
+			continue
+		}
+		t, timeErr := time.Parse(time.RFC3339, f.value)
+		if timeErr != nil {
+			return analytics.Params{}, fmt.Errorf("%s: %w", name, timeErr)
+		}
+		*f.t = t
+	}
+	return params, nil
+}
+
+func analyticsReport(ctx context.Context, a *app, name string, args []string) error {
+	fs := a.flags(name)
+	by := fs.String("by", "", "comma-separated breakdowns: product, country")
+	params, paramsErr := analyticsRange(name, fs, args)
+	if paramsErr != nil {
+		return paramsErr
+	}
+	params.GroupBy = enumList[analytics.Dimension](*by)
+	report, computeErr := analytics.Compute(ctx, analytics.FromAPI(a.client), params)
+	if computeErr != nil {
+		return computeErr
+	}
+	return render(a, metricsColumns, report.Segments)
+}
+
+// analyticsCohorts writes a row for each month of each cohort; in JSON it
+// writes the cohorts with their periods nested.
+func analyticsCohorts(ctx context.Context, a *app, name string, args []string) error {
+	params, paramsErr := analyticsRange(name, a.flags(name), args)
+	if paramsErr != nil {
+		return paramsErr
+	}
+	report, computeErr := analytics.Compute(ctx, analytics.FromAPI(a.client), params)
+	if computeErr != nil {
+		return computeErr
+	}
+	var rows []*cohortPeriod
+	for _, c := range report.Cohorts {
+		for _, p := range c.Periods {
+			rows = append(rows, &cohortPeriod{c, p})
+		}
+	}
+	return write(a, cohortColumns, rows, report.Cohorts)
+}
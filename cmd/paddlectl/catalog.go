@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/texm/go-paddle/catalog"
+)
+
+var changeColumns = []column[catalog.Change]{
+	{"action", func(c *catalog.Change) string { return string(c.Action) }},
+	{"kind", func(c *catalog.Change) string { return string(c.Kind) }},
+	{"key", func(c *catalog.Change) string { return c.Key }},
+	{"id", func(c *catalog.Change) string { return str(optional(c.Id)) }},
+	{"fields", func(c *catalog.Change) string { return strings.Join(c.Fields, ", ") }},
+}
+
+func catalogPlan(ctx context.Context, a *app, name string, args []string) error {
+	fs := a.flags(name)
+	manifestPath := fs.String("f", "", "path to the catalog manifest (.yaml or .json)")
+	if _, parseErr := parse(fs, args); parseErr != nil {
+		return parseErr
+	}
+	plan, planErr := loadPlan(ctx, a, name, *manifestPath)
+	if planErr != nil {
+		return planErr
+	}
+	if a.format == formatTable {
+		_, writeErr := fmt.Fprint(a.out, plan)
+		return writeErr
+	}
+	return render(a, changeColumns, plan.Changes)
+}
+
+func catalogApply(ctx context.Context, a *app, name string, args []string) error {
+	fs := a.mutatingFlags(name)
+	manifestPath := fs.String("f", "", "path to the catalog manifest (.yaml or .json)")
+	idsOut := fs.String("ids-out", "", "write the Paddle ID of every key to this JSON file")
+	if _, parseErr := parse(fs, args); parseErr != nil {
+		return parseErr
+	}
+	plan, planErr := loadPlan(ctx, a, name, *manifestPath)
+	if planErr != nil {
+		return planErr
+	}
+
+	if !plan.Empty() {
+		fmt.Fprint(a.prompt, plan)
+		if confirmErr := a.confirm("Apply %d changes?", len(plan.Changes)); confirmErr != nil {
+			return confirmErr
+		}
+	}
+	result, applyErr := catalog.Apply(ctx, a.client, plan)
+	if *idsOut != "" && result != nil {
+		data, _ := json.MarshalIndent(result.Ids, "", "  ")
+		if writeErr := os.WriteFile(*idsOut, append(data, '\n'), 0o644); writeErr != nil && applyErr == nil {
+			applyErr = writeErr
+		}
+	}
+	if applyErr != nil {
+		return applyErr
+	}
+	return render(a, changeColumns, result.Applied)
+}
+
+func loadPlan(ctx context.Context, a *app, name string, manifestPath string) (*catalog.Plan, error) {
+	if manifestPath == "" {
+		return nil, fmt.Errorf("%s: -f is required", name)
+	}
+	m, loadErr := catalog.Load(manifestPath)
+	if loadErr != nil {
+		return nil, loadErr
+	}
+	return catalog.Diff(ctx, a.client, m)
+}
@@ -0,0 +1,569 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/texm/go-paddle"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(ctx context.Context, a *app, name string, args []string) error
+}
+
+type group struct {
+	name     string
+	commands []*command
+}
+
+var groups = []*group{
+	{"customers", []*command{
+		{"list", "[--search text] [--email a,b] [--status active,archived]", listCustomers},
+		{"get", "<customer-id>", getCustomer},
+		{"create", "--email address [--name name]", createCustomer},
+		{"update", "<customer-id> [--email address] [--name name]", updateCustomer},
+	}},
+	{"subscriptions", []*command{
+		{"list", "[--customer-id a,b] [--price-id a,b] [--status active,past_due]", listSubscriptions},
+		{"get", "<subscription-id>", getSubscription},
+		{"cancel", "<subscription-id> [--effective-from next_billing_period|immediately]", cancelSubscription},
+		{"pause", "<subscription-id> [--effective-from next_billing_period|immediately] [--resume-at t]", pauseSubscription},
+		{"resume", "<subscription-id>", resumeSubscription},
+		{"remove-scheduled-change", "<subscription-id>", removeScheduledChange},
+		{"update-payment-method", "<subscription-id>", updatePaymentMethod},
+	}},
+	{"products", []*command{
+		{"list", "[--status active,archived] [--include-prices]", listProducts},
+		{"get", "<product-id> [--include-prices]", getProduct},
+	}},
+	{"prices", []*command{
+		{"list", "[--product-id a,b] [--status active,archived]", listPrices},
+		{"get", "<price-id> [--include-product]", getPrice},
+	}},
+	{"transactions", []*command{
+		{"list", "[--customer-id a,b] [--subscription-id a,b] [--status billed,paid] [--created-after t] [--created-before t]", listTransactions},
+		{"get", "<transaction-id>", getTransaction},
+	}},
+	{"addresses", []*command{
+		{"list", "<customer-id> [--search text] [--status active,archived]", listAddresses},
+		{"get", "<customer-id> <address-id>", getAddress},
+	}},
+	{"businesses", []*command{
+		{"list", "<customer-id> [--search text] [--status active,archived]", listBusinesses},
+		{"get", "<customer-id> <business-id>", getBusiness},
+	}},
+	{"discounts", []*command{
+		{"list", "[--code a,b] [--status active,archived]", listDiscounts},
+		{"get", "<discount-id>", getDiscount},
+	}},
+	{"adjustments", []*command{
+		{"list", "[--transaction-id a,b] [--customer-id a,b] [--status pending_approval,approved]", listAdjustments},
+	}},
+	{"analytics", []*command{
+		{"report", "--from t [--to t] [--by product,country]", analyticsReport},
+		{"cohorts", "--from t [--to t]", analyticsCohorts},
+	}},
+	{"catalog", []*command{
+		{"plan", "-f manifest.yaml", catalogPlan},
+		{"apply", "-f manifest.yaml [--ids-out ids.json]", catalogApply},
+	}},
+}
+
+func findCommand(resource string, name string) (*command, error) {
+	for _, g := range groups {
+		if g.name != resource {
+			continue
+		}
+		for _, c := range g.commands {
+			if c.name == name {
+				return c, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown command %q for %s", name, resource)
+	}
+	return nil, fmt.Errorf("unknown resource %q", resource)
+}
+
+func list(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+func enumList[T ~string](s string) []T {
+	var values []T
+	for _, v := range list(s) {
+		values = append(values, T(v))
+	}
+	return values
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func listCustomers(ctx context.Context, a *app, name string, args []string) error {
+	fs := a.flags(name)
+	search := fs.String("search", "", "search by id, name or email")
+	email := fs.String("email", "", "comma-separated emails")
+	status := fs.String("status", "", "comma-separated statuses")
+	if _, parseErr := parse(fs, args); parseErr != nil {
+		return parseErr
+	}
+	customers, listErr := a.client.Customers.List(ctx, &paddle.ListCustomersParams{
+		Search: *search,
+		Email:  list(*email),
+		Status: enumList[paddle.Status](*status),
+	})
+	if listErr != nil {
+		return listErr
+	}
+	return render(a, customerColumns, customers)
+}
+
+func getCustomer(ctx context.Context, a *app, name string, args []string) error {
+	positional, parseErr := parse(a.flags(name), args, "<customer-id>")
+	if parseErr != nil {
+		return parseErr
+	}
+	customer, getErr := a.client.Customers.Get(ctx, positional[0])
+	if getErr != nil {
+		return getErr
+	}
+	return renderItem(a, customerColumns, customer)
+}
+
+func createCustomer(ctx context.Context, a *app, name string, args []string) error {
+	fs := a.mutatingFlags(name)
+	email := fs.String("email", "", "email address")
+	customerName := fs.String("name", "", "full name")
+	if _, parseErr := parse(fs, args); parseErr != nil {
+		return parseErr
+	}
+	if *email == "" {
+		return fmt.Errorf("%s: --email is required", name)
+	}
+	if confirmErr := a.confirm("Create customer %s?", *email); confirmErr != nil {
+		return confirmErr
+	}
+	customer, createErr := a.client.Customers.Create(ctx, &paddle.CreateCustomerParams{
+		Email: *email,
+		Name:  optional(*customerName),
+	})
+	if createErr != nil {
+		return createErr
+	}
+	return renderItem(a, customerColumns, customer)
+}
+
+func updateCustomer(ctx context.Context, a *app, name string, args []string) error {
+	fs := a.mutatingFlags(name)
+	email := fs.String("email", "", "new email address")
+	customerName := fs.String("name", "", "new full name")
+	positional, parseErr := parse(fs, args, "<customer-id>")
+	if parseErr != nil {
+		return parseErr
+	}
+	if confirmErr := a.confirm("Update customer %s?", positional[0]); confirmErr != nil {
+		return confirmErr
+	}
+	customer, updateErr := a.client.Customers.Update(ctx, positional[0], &paddle.UpdateCustomerParams{
+		Email: optional(*email),
+		Name:  optional(*customerName),
+	})
+	if updateErr != nil {
+		return updateErr
+	}
+	return renderItem(a, customerColumns, customer)
+}
+
+func listSubscriptions(ctx context.Context, a *app, name string, args []string) error {
+	fs := a.flags(name)
+	customerIds := fs.String("customer-id", "", "comma-separated customer ids")
+	priceIds := fs.String("price-id", "", "comma-separated price ids")
+	status := fs.String("status", "", "comma-separated statuses")
+	if _, parseErr := parse(fs, args); parseErr != nil {
+		return parseErr
+	}
+	subscriptions, listErr := a.client.Subscriptions.List(ctx, &paddle.ListSubscriptionsParams{
+		CustomerIds: list(*customerIds),
+		PriceIds:    list(*priceIds),
+		Status:      enumList[paddle.SubscriptionStatus](*status),
+	})
+	if listErr != nil {
+		return listErr
+	}
+	return render(a, subscriptionColumns, subscriptions)
+}
+
+func getSubscription(ctx context.Context, a *app, name string, args []string) error {
+	positional, parseErr := parse(a.flags(name), args, "<subscription-id>")
+	if parseErr != nil {
+		return parseErr
+	}
+	subscription, getErr := a.client.Subscriptions.Get(ctx, positional[0])
+	if getErr != nil {
+		return getErr
+	}
+	return renderItem(a, subscriptionColumns, subscription)
+}
+
+func cancelSubscription(ctx context.Context, a *app, name string, args []string) error {
+	fs := a.mutatingFlags(name)
+	effectiveFrom := fs.String("effective-from", string(paddle.SubscriptionEffectFromOptionNextBillingPeriod),
+		"next_billing_period or immediately")
+	positional, parseErr := parse(fs, args, "<subscription-id>")
+	if parseErr != nil {
+		return parseErr
+	}
+	if checkErr := checkTransition(ctx, a, positional[0], paddle.SubscriptionActionCancel, *effectiveFrom); checkErr != nil {
+		return checkErr
+	}
+	if confirmErr := a.confirm("Cancel subscription %s effective %s?", positional[0], *effectiveFrom); confirmErr != nil {
+		return confirmErr
+	}
+	subscription, cancelErr := a.client.Subscriptions.Cancel(ctx, positional[0], &paddle.CancelSubscriptionParams{
+		EffectiveFrom: paddle.SubscriptionEffectFromOption(*effectiveFrom),
+	})
+	if cancelErr != nil {
+		return cancelErr
+	}
+	return renderItem(a, subscriptionColumns, subscription)
+}
+
+func pauseSubscription(ctx context.Context, a *app, name string, args []string) error {
+	fs := a.mutatingFlags(name)
+	effectiveFrom := fs.String("effective-from", string(paddle.SubscriptionEffectFromOptionNextBillingPeriod),
+		"next_billing_period or immediately")
+	resumeAt := fs.String("resume-at", "", "RFC 3339 time to resume automatically")
+	positional, parseErr := parse(fs, args, "<subscription-id>")
+	if parseErr != nil {
+		return parseErr
+	}
+	params := &paddle.PauseSubscriptionParams{EffectiveFrom: paddle.SubscriptionEffectFromOption(*effectiveFrom)}
+	if *resumeAt != "" {
+		t, timeErr := time.Parse(time.RFC3339, *resumeAt)
+		if timeErr != nil {
+			return fmt.Errorf("--resume-at: %w", timeErr)
+		}
+		params.ResumeAt = &t
+	}
+	if checkErr := checkTransition(ctx, a, positional[0], paddle.SubscriptionActionPause, *effectiveFrom); checkErr != nil {
+		return checkErr
+	}
+	if confirmErr := a.confirm("Pause subscription %s effective %s?", positional[0], *effectiveFrom); confirmErr != nil {
+		return confirmErr
+	}
+	subscription, pauseErr := a.client.Subscriptions.Pause(ctx, positional[0], params)
+	if pauseErr != nil {
+		return pauseErr
+	}
+	return renderItem(a, subscriptionColumns, subscription)
+}
+
+func resumeSubscription(ctx context.Context, a *app, name string, args []string) error {
+	positional, parseErr := parse(a.mutatingFlags(name), args, "<subscription-id>")
+	if parseErr != nil {
+		return parseErr
+	}
+	if checkErr := checkTransition(ctx, a, positional[0], paddle.SubscriptionActionResume, ""); checkErr != nil {
+		return checkErr
+	}
+	if confirmErr := a.confirm("Resume subscription %s?", positional[0]); confirmErr != nil {
+		return confirmErr
+	}
+	subscription, resumeErr := a.client.Subscriptions.Resume(ctx, positional[0], &paddle.ResumeSubscriptionParams{
+		EffectiveFrom: paddle.SubscriptionEffectFromOptionImmediately,
+	})
+	if resumeErr != nil {
+		return resumeErr
+	}
+	return renderItem(a, subscriptionColumns, subscription)
+}
+
+// checkTransition fetches the subscription and fails before prompting if
+// the action does not apply to it. A typo in effectiveFrom fails here too,
+// as Transition rejects values it does not know.
+func checkTransition(ctx context.Context, a *app, id string, action paddle.SubscriptionAction, effectiveFrom string) error {
+	subscription, getErr := a.client.Subscriptions.Get(ctx, id)
+	if getErr != nil {
+		return getErr
+	}
+	_, transitionErr := subscription.Transition(action, paddle.SubscriptionEffectFromOption(effectiveFrom))
+	return transitionErr
+}
+
+func removeScheduledChange(ctx context.Context, a *app, name string, args []string) error {
+	positional, parseErr := parse(a.mutatingFlags(name), args, "<subscription-id>")
+	if parseErr != nil {
+		return parseErr
+	}
+	if checkErr := checkTransition(ctx, a, positional[0], paddle.SubscriptionActionRemoveScheduledChange, ""); checkErr != nil {
+		return checkErr
+	}
+	if confirmErr := a.confirm("Remove the scheduled change on subscription %s?", positional[0]); confirmErr != nil {
+		return confirmErr
+	}
+	subscription, removeErr := a.client.Subscriptions.RemoveScheduledCancellation(ctx, positional[0])
+	if removeErr != nil {
+		return removeErr
+	}
+	return renderItem(a, subscriptionColumns, subscription)
+}
+
+func updatePaymentMethod(ctx context.Context, a *app, name string, args []string) error {
+	positional, parseErr := parse(a.flags(name), args, "<subscription-id>")
+	if parseErr != nil {
+		return parseErr
+	}
+	transaction, getErr := a.client.Subscriptions.GetUpdatePaymentMethodTransaction(ctx, positional[0])
+	if getErr != nil {
+		return getErr
+	}
+	if a.format == formatTable && transaction.Checkout != nil && transaction.Checkout.Url != nil {
+		_, writeErr := fmt.Fprintln(a.out, *transaction.Checkout.Url)
+		return writeErr
+	}
+	return renderItem(a, transactionColumns, transaction)
+}
+
+func listProducts(ctx context.Context, a *app, name string, args []string) error {
+	fs := a.flags(name)
+	status := fs.String("status", "", "comma-separated statuses")
+	includePrices := fs.Bool("include-prices", false, "include each product's prices")
+	if _, parseErr := parse(fs, args); parseErr != nil {
+		return parseErr
+	}
+	products, listErr := a.client.Products.List(ctx, &paddle.ListProductsParams{
+		IncludePrices: *includePrices,
+		Status:        enumList[paddle.Status](*status),
+	})
+	if listErr != nil {
+		return listErr
+	}
+	return render(a, productColumns, products)
+}
+
+func getProduct(ctx context.Context, a *app, name string, args []string) error {
+	fs := a.flags(name)
+	includePrices := fs.Bool("include-prices", false, "include the product's prices")
+	positional, parseErr := parse(fs, args, "<product-id>")
+	if parseErr != nil {
+		return parseErr
+	}
+	product, getErr := a.client.Products.Get(ctx, positional[0], *includePrices)
+	if getErr != nil {
+		return getErr
+	}
+	return renderItem(a, productColumns, product)
+}
+
+func listPrices(ctx context.Context, a *app, name string, args []string) error {
+	fs := a.flags(name)
+	productIds := fs.String("product-id", "", "comma-separated product ids")
+	status := fs.String("status", "", "comma-separated statuses")
+	if _, parseErr := parse(fs, args); parseErr != nil {
+		return parseErr
+	}
+	prices, listErr := a.client.Prices.List(ctx, &paddle.ListPricesParams{
+		ProductIds: list(*productIds),
+		Status:     enumList[paddle.Status](*status),
+	})
+	if listErr != nil {
+		return listErr
+	}
+	return render(a, priceColumns, prices)
+}
+
+func getPrice(ctx context.Context, a *app, name string, args []string) error {
+	fs := a.flags(name)
+	includeProduct := fs.Bool("include-product", false, "include the price's product")
+	positional, parseErr := parse(fs, args, "<price-id>")
+	if parseErr != nil {
+		return parseErr
+	}
+	price, getErr := a.client.Prices.Get(ctx, positional[0], *includeProduct)
+	if getErr != nil {
+		return getErr
+	}
+	return renderItem(a, priceColumns, price)
+}
+
+func listTransactions(ctx context.Context, a *app, name string, args []string) error {
+	fs := a.flags(name)
+	customerIds := fs.String("customer-id", "", "comma-separated customer ids")
+	subscriptionIds := fs.String("subscription-id", "", "comma-separated subscription ids")
+	status := fs.String("status", "", "comma-separated statuses")
+	createdAfter := fs.String("created-after", "", "RFC 3339 time, inclusive")
+	createdBefore := fs.String("created-before", "", "RFC 3339 time, exclusive")
+	if _, parseErr := parse(fs, args); parseErr != nil {
+		return parseErr
+	}
+	params := &paddle.ListTransactionsParams{
+		CustomerIds:     list(*customerIds),
+		SubscriptionIds: list(*subscriptionIds),
+		Status:          enumList[paddle.TransactionStatus](*status),
+	}
+
+	var after, before time.Time
+	for _, f := range []struct {
+		value string
+		t     *time.Time
+	}{{*createdAfter, &after}, {*createdBefore, &before}} {
+		if f.value == "" {
+			continue
+		}
+		t, timeErr := time.Parse(time.RFC3339, f.value)
+		if timeErr != nil {
+			return fmt.Errorf("%s: %w", name, timeErr)
+		}
+		*f.t = t
+	}
+	switch {
+	case !after.IsZero() && !before.IsZero():
+		params.CreatedAt = paddle.Between(after, before)
+	case !after.IsZero():
+		params.CreatedAt = paddle.OnOrAfter(after)
+	case !before.IsZero():
+		params.CreatedAt = paddle.Before(before)
+	}
+
+	transactions, listErr := a.client.Transactions.List(ctx, params)
+	if listErr != nil {
+		return listErr
+	}
+	return render(a, transactionColumns, transactions)
+}
+
+func getTransaction(ctx context.Context, a *app, name string, args []string) error {
+	positional, parseErr := parse(a.flags(name), args, "<transaction-id>")
+	if parseErr != nil {
+		return parseErr
+	}
+	transaction, getErr := a.client.Transactions.Get(ctx, positional[0], &paddle.TransactionIncludeParam{
+		Customer: true,
+		Address:  true,
+		Business: true,
+		Discount: true,
+	})
+	if getErr != nil {
+		return getErr
+	}
+	return renderItem(a, transactionColumns, transaction)
+}
+
+func listAddresses(ctx context.Context, a *app, name string, args []string) error {
+	fs := a.flags(name)
+	search := fs.String("search", "", "search text")
+	status := fs.String("status", "", "comma-separated statuses")
+	positional, parseErr := parse(fs, args, "<customer-id>")
+	if parseErr != nil {
+		return parseErr
+	}
+	addresses, listErr := a.client.Addresses.List(ctx, positional[0], &paddle.ListAddressesParams{
+		Search: *search,
+		Status: enumList[paddle.Status](*status),
+	})
+	if listErr != nil {
+		return listErr
+	}
+	return render(a, addressColumns, addresses)
+}
+
+func getAddress(ctx context.Context, a *app, name string, args []string) error {
+	positional, parseErr := parse(a.flags(name), args, "<customer-id>", "<address-id>")
+	if parseErr != nil {
+		return parseErr
+	}
+	address, getErr := a.client.Addresses.Get(ctx, positional[0], positional[1])
+	if getErr != nil {
+		return getErr
+	}
+	return renderItem(a, addressColumns, address)
+}
+
+func listBusinesses(ctx context.Context, a *app, name string, args []string) error {
+	fs := a.flags(name)
+	search := fs.String("search", "", "search text")
+	status := fs.String("status", "", "comma-separated statuses")
+	positional, parseErr := parse(fs, args, "<customer-id>")
+	if parseErr != nil {
+		return parseErr
+	}
+	businesses, listErr := a.client.Businesses.List(ctx, positional[0], &paddle.ListBusinessesParams{
+		Search: *search,
+		Status: enumList[paddle.Status](*status),
+	})
+	if listErr != nil {
+		return listErr
+	}
+	return render(a, businessColumns, businesses)
+}
+
+func getBusiness(ctx context.Context, a *app, name string, args []string) error {
+	positional, parseErr := parse(a.flags(name), args, "<customer-id>", "<business-id>")
+	if parseErr != nil {
+		return parseErr
+	}
+	business, getErr := a.client.Businesses.Get(ctx, positional[0], positional[1])
+	if getErr != nil {
+		return getErr
+	}
+	return renderItem(a, businessColumns, business)
+}
+
+func listDiscounts(ctx context.Context, a *app, name string, args []string) error {
+	fs := a.flags(name)
+	codes := fs.String("code", "", "comma-separated discount codes")
+	status := fs.String("status", "", "comma-separated statuses")
+	if _, parseErr := parse(fs, args); parseErr != nil {
+		return parseErr
+	}
+	discounts, listErr := a.client.Discounts.List(ctx, &paddle.ListDiscountsParams{
+		Codes:  list(*codes),
+		Status: enumList[paddle.DiscountStatus](*status),
+	})
+	if listErr != nil {
+		return listErr
+	}
+	return render(a, discountColumns, discounts)
+}
+
+func getDiscount(ctx context.Context, a *app, name string, args []string) error {
+	positional, parseErr := parse(a.flags(name), args, "<discount-id>")
+	if parseErr != nil {
+		return parseErr
+	}
+	discount, getErr := a.client.Discounts.Get(ctx, positional[0])
+	if getErr != nil {
+		return getErr
+	}
+	return renderItem(a, discountColumns, discount)
+}
+
+func listAdjustments(ctx context.Context, a *app, name string, args []string) error {
+	fs := a.flags(name)
+	transactionIds := fs.String("transaction-id", "", "comma-separated transaction ids")
+	customerIds := fs.String("customer-id", "", "comma-separated customer ids")
+	status := fs.String("status", "", "comma-separated statuses")
+	if _, parseErr := parse(fs, args); parseErr != nil {
+		return parseErr
+	}
+	adjustments, listErr := a.client.Adjustments.List(ctx, &paddle.ListAdjustmentsParams{
+		TransactionIds: list(*transactionIds),
+		CustomerIds:    list(*customerIds),
+		Status:         enumList[paddle.AdjustmentStatus](*status),
+	})
+	if listErr != nil {
+		return listErr
+	}
+	return render(a, adjustmentColumns, adjustments)
+}
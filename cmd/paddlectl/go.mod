@@ -0,0 +1,14 @@
+module github.com/texm/go-paddle/cmd/paddlectl
+
+go 1.21
+
+require (
+	github.com/texm/go-paddle v0.1.0
+	github.com/texm/go-paddle/analytics v0.1.0
+	github.com/texm/go-paddle/catalog v0.1.0
+)
+
+require (
+	github.com/texm/go-paddle/mirror v0.1.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
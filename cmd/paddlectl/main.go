@@ -0,0 +1,158 @@
+// Command paddlectl inspects and operates a Paddle account from the shell.
+//
+//	PADDLE_API_KEY=... paddlectl --sandbox customers list --search jo@example.com
+//	paddlectl subscriptions get sub_01h... -o json
+//	paddlectl subscriptions cancel sub_01h... --effective-from immediately
+//	paddlectl transactions list --status billed,paid -o csv
+//
+// Commands which modify the account ask for confirmation unless --yes is
+// given.
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/texm/go-paddle"
+)
+
+const usageHeader = `usage: paddlectl [--sandbox] [--base-url url] [--allow-unknown-enums] <resource> <command> [flags] [args]
+
+The API key is read from PADDLE_API_KEY. Every command accepts
+-o table|json|csv, and commands which modify the account accept --yes.
+Values such as statuses must be known to this version of paddlectl
+unless --allow-unknown-enums is given.
+
+`
+
+type app struct {
+	client *paddle.Client
+	out    io.Writer
+	in     *bufio.Reader
+	prompt io.Writer
+	format string
+	yes    bool
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "paddlectl:", err)
+		stop()
+		os.Exit(1)
+	}
+}
+
+// run runs the command in args, asking for confirmation on stderr.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	global := flag.NewFlagSet("paddlectl", flag.ContinueOnError)
+	sandbox := global.Bool("sandbox", false, "use the sandbox environment")
+	baseURL := global.String("base-url", "", "override the API base URL")
+	allowUnknown := global.Bool("allow-unknown-enums", false, "send values added in newer API versions")
+	global.Usage = func() { printUsage(global.Output()) }
+	if parseErr := global.Parse(args); parseErr != nil {
+		return parseErr
+	}
+
+	args = global.Args()
+	if len(args) < 2 {
+		printUsage(global.Output())
+		return errors.New("missing resource or command")
+	}
+	cmd, findErr := findCommand(args[0], args[1])
+	if findErr != nil {
+		printUsage(global.Output())
+		return findErr
+	}
+
+	apiKey := os.Getenv("PADDLE_API_KEY")
+	if apiKey == "" {
+		return errors.New("PADDLE_API_KEY is not set")
+	}
+
+	a := &app{
+		client: paddle.NewClient(&paddle.Config{
+			Sandbox:   *sandbox,
+			BaseURL:   *baseURL,
+			APIKey:    apiKey,
+			UserAgent: "paddlectl",
+		}),
+		out:    stdout,
+		in:     bufio.NewReader(stdin),
+		prompt: stderr,
+		format: formatTable,
+	}
+	if *allowUnknown {
+		ctx = paddle.WithUnknownEnums(ctx)
+	}
+	return cmd.run(ctx, a, args[0]+" "+args[1], args[2:])
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprint(w, usageHeader)
+	for _, g := range groups {
+		for _, c := range g.commands {
+			fmt.Fprintf(w, "  %s %s %s\n", g.name, c.name, c.usage)
+		}
+	}
+}
+
+// flags returns a flag set with the options shared by every command.
+func (a *app) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.StringVar(&a.format, "o", formatTable, "output format: table, json or csv")
+	return fs
+}
+
+// mutatingFlags adds --yes to skip the confirmation prompt.
+func (a *app) mutatingFlags(name string) *flag.FlagSet {
+	fs := a.flags(name)
+	fs.BoolVar(&a.yes, "yes", false, "do not ask for confirmation")
+	return fs
+}
+
+// parse parses flags which may appear before or after positional
+// arguments and checks the number of positional arguments.
+func parse(fs *flag.FlagSet, args []string, positional ...string) ([]string, error) {
+	var rest []string
+	for {
+		if parseErr := fs.Parse(args); parseErr != nil {
+			return nil, parseErr
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		rest = append(rest, args[0])
+		args = args[1:]
+	}
+	if len(rest) != len(positional) {
+		return nil, fmt.Errorf("%s expects arguments: %s", fs.Name(), strings.Join(positional, " "))
+	}
+	return rest, nil
+}
+
+func (a *app) confirm(format string, args ...any) error {
+	if a.yes {
+		return nil
+	}
+	fmt.Fprintf(a.prompt, format+" [y/N] ", args...)
+	answer, readErr := a.in.ReadString('\n')
+	if readErr != nil && !errors.Is(readErr, io.EOF) {
+		return readErr
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	}
+	return errors.New("aborted")
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/texm/go-paddle"
+	"github.com/texm/go-paddle/paddletest"
+)
+
+// runCtl runs paddlectl against srv with stdin as the answer to prompts,
+// returning its output and prompts.
+func runCtl(t *testing.T, srv *paddletest.Server, stdin string, args ...string) (string, string, error) {
+	t.Helper()
+	t.Setenv("PADDLE_API_KEY", srv.APIKey)
+	var out, prompt bytes.Buffer
+	err := run(context.Background(), append([]string{"--base-url", srv.URL}, args...), strings.NewReader(stdin), &out, &prompt)
+	return out.String(), prompt.String(), err
+}
+
+func TestUnknownEnums(t *testing.T) {
+	srv := paddletest.NewServer()
+	t.Cleanup(srv.Close)
+	sub := srv.AddSubscription(paddle.Subscription{})
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr error
+	}{
+		{"list with a typo", []string{"subscriptions", "list", "--status", "actve"}, paddle.ErrInvalidParams},
+		{"list with a known status", []string{"subscriptions", "list", "--status", "active"}, nil},
+		{"list allowing unknown values", []string{"--allow-unknown-enums", "subscriptions", "list", "--status", "actve"}, nil},
+		{"cancel with a typo", []string{"subscriptions", "cancel", sub.Id, "--effective-from", "immediatly", "--yes"}, paddle.ErrInvalidTransition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := runCtl(t, srv, "", tt.args...)
+			if tt.wantErr == nil && err != nil || tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("run(%v) = %v, want %v", tt.args, err, tt.wantErr)
+			}
+		})
+	}
+	if got, _ := srv.PaddleClient(nil).Subscriptions.Get(context.Background(), sub.Id); got.Status != paddle.SubscriptionStatusActive {
+		t.Errorf("subscription status = %s after a rejected cancel, want active", got.Status)
+	}
+}
+
+func TestConfirmation(t *testing.T) {
+	tests := []struct {
+		name       string
+		stdin      string
+		yes        bool
+		wantPrompt bool
+		wantSent   bool
+	}{
+		{name: "y", stdin: "y\n", wantPrompt: true, wantSent: true},
+		{name: "yes in capitals", stdin: "YES\n", wantPrompt: true, wantSent: true},
+		{name: "n", stdin: "n\n", wantPrompt: true},
+		{name: "empty answer", stdin: "\n", wantPrompt: true},
+		{name: "other answer", stdin: "sure\n", wantPrompt: true},
+		{name: "end of input", stdin: "", wantPrompt: true},
+		{name: "--yes", yes: true, wantSent: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := paddletest.NewServer()
+			t.Cleanup(srv.Close)
+			sub := srv.AddSubscription(paddle.Subscription{})
+
+			args := []string{"subscriptions", "cancel", sub.Id, "--effective-from", "immediately"}
+			if tt.yes {
+				args = append(args, "--yes")
+			}
+			out, prompt, err := runCtl(t, srv, tt.stdin, args...)
+			if tt.wantSent && err != nil {
+				t.Fatalf("run() = %v", err)
+			}
+			if !tt.wantSent && (err == nil || err.Error() != "aborted") {
+				t.Fatalf("run() = %v, want aborted", err)
+			}
+			if wantPrompt := "Cancel subscription " + sub.Id + " effective immediately? [y/N] "; (prompt == wantPrompt) != tt.wantPrompt {
+				t.Errorf("prompt = %q, want prompted %v", prompt, tt.wantPrompt)
+			}
+
+			got, getErr := srv.PaddleClient(nil).Subscriptions.Get(context.Background(), sub.Id)
+			if getErr != nil {
+				t.Fatal(getErr)
+			}
+			wantStatus := paddle.SubscriptionStatusActive
+			if tt.wantSent {
+				wantStatus = paddle.SubscriptionStatusCanceled
+			}
+			if got.Status != wantStatus {
+				t.Errorf("subscription status = %s, want %s", got.Status, wantStatus)
+			}
+			if sent := strings.Contains(out, sub.Id); sent != tt.wantSent {
+				t.Errorf("output = %q, want the canceled subscription %v", out, tt.wantSent)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/texm/go-paddle"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+)
+
+type column[T any] struct {
+	name  string
+	value func(*T) string
+}
+
+// render writes a list of items in the selected format. In JSON a list is
+// always an array, even when it is empty or holds a single item.
+func render[T any](a *app, columns []column[T], items []*T) error {
+	if items == nil {
+		items = []*T{}
+	}
+	return write(a, columns, items, items)
+}
+
+// renderItem writes a single item, such as one fetched by id. In JSON it is
+// an object.
+func renderItem[T any](a *app, columns []column[T], item *T) error {
+	return write(a, columns, []*T{item}, item)
+}
+
+func write[T any](a *app, columns []column[T], items []*T, v any) error {
+	switch a.format {
+	case formatJSON:
+		enc := json.NewEncoder(a.out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+
+	case formatCSV:
+		w := csv.NewWriter(a.out)
+		header := make([]string, len(columns))
+		for i, col := range columns {
+			header[i] = col.name
+		}
+		_ = w.Write(header)
+		for _, item := range items {
+			_ = w.Write(row(columns, item))
+		}
+		w.Flush()
+		return w.Error()
+
+	case formatTable:
+		w := tabwriter.NewWriter(a.out, 0, 4, 2, ' ', 0)
+		header := make([]string, len(columns))
+		for i, col := range columns {
+			header[i] = strings.ToUpper(col.name)
+		}
+		fmt.Fprintln(w, strings.Join(header, "\t"))
+		for _, item := range items {
+			fmt.Fprintln(w, strings.Join(row(columns, item), "\t"))
+		}
+		return w.Flush()
+	}
+	return fmt.Errorf("unknown output format %q", a.format)
+}
+
+func row[T any](columns []column[T], item *T) []string {
+	values := make([]string, len(columns))
+	for i, col := range columns {
+		values[i] = col.value(item)
+	}
+	return values
+}
+
+func str(s *string) string {
+	if s == nil {
+		return "-"
+	}
+	return *s
+}
+
+func timestamp(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func money(amount paddle.Amount, currency paddle.CurrencyCode) string {
+	return paddle.NewMoney(amount, currency).String()
+}
+
+var customerColumns = []column[paddle.Customer]{
+	{"id", func(c *paddle.Customer) string { return c.Id }},
+	{"email", func(c *paddle.Customer) string { return c.Email }},
+	{"name", func(c *paddle.Customer) string { return str(c.Name) }},
+	{"status", func(c *paddle.Customer) string { return string(c.Status) }},
+	{"created_at", func(c *paddle.Customer) string { return timestamp(&c.CreatedAt) }},
+}
+
+var subscriptionColumns = []column[paddle.Subscription]{
+	{"id", func(s *paddle.Subscription) string { return s.Id }},
+	{"customer_id", func(s *paddle.Subscription) string { return s.CustomerId }},
+	{"status", func(s *paddle.Subscription) string { return string(s.Status) }},
+	{"items", func(s *paddle.Subscription) string {
+		items := make([]string, len(s.Items))
+		for i, item := range s.Items {
+			items[i] = fmt.Sprintf("%s x%d", item.Price.Id, item.Quantity)
+		}
+		return strings.Join(items, ", ")
+	}},
+	{"next_billed_at", func(s *paddle.Subscription) string { return timestamp(s.NextBilledAt) }},
+	{"scheduled_change", func(s *paddle.Subscription) string {
+		if s.ScheduledChange == nil {
+			return "-"
+		}
+		return string(s.ScheduledChange.Action) + " at " + timestamp(&s.ScheduledChange.EffectiveAt)
+	}},
+	{"allowed_actions", func(s *paddle.Subscription) string {
+		actions := s.AllowedActions()
+		if len(actions) == 0 {
+			return "-"
+		}
+		names := make([]string, len(actions))
+		for i, action := range actions {
+			names[i] = string(action)
+		}
+		return strings.Join(names, ",")
+	}},
+}
+
+var productColumns = []column[paddle.Product]{
+	{"id", func(p *paddle.Product) string { return p.Id }},
+	{"name", func(p *paddle.Product) string { return p.Name }},
+	{"tax_category", func(p *paddle.Product) string { return string(p.TaxCategory) }},
+	{"status", func(p *paddle.Product) string { return string(p.Status) }},
+	{"prices", func(p *paddle.Product) string {
+		if p.Prices == nil {
+			return "-"
+		}
+		return fmt.Sprint(len(*p.Prices))
+	}},
+}
+
+var priceColumns = []column[paddle.Price]{
+	{"id", func(p *paddle.Price) string { return p.Id }},
+	{"product_id", func(p *paddle.Price) string { return p.ProductId }},
+	{"description", func(p *paddle.Price) string { return p.Description }},
+	{"unit_price", func(p *paddle.Price) string { return p.UnitPrice.String() }},
+	{"billing_cycle", func(p *paddle.Price) string {
+		if p.BillingCycle == nil {
+			return "one-time"
+		}
+		return fmt.Sprintf("every %d %s", p.BillingCycle.Frequency, p.BillingCycle.Interval)
+	}},
+	{"status", func(p *paddle.Price) string { return string(p.Status) }},
+}
+
+var transactionColumns = []column[paddle.Transaction]{
+	{"id", func(t *paddle.Transaction) string { return t.Id }},
+	{"status", func(t *paddle.Transaction) string { return string(t.Status) }},
+	{"customer_id", func(t *paddle.Transaction) string { return str(t.CustomerId) }},
+	{"subscription_id", func(t *paddle.Transaction) string { return str(t.SubscriptionId) }},
+	{"invoice_number", func(t *paddle.Transaction) string { return str(t.InvoiceNumber) }},
+	{"total", func(t *paddle.Transaction) string {
+		return money(t.Details.Totals.GrandTotal, t.CurrencyCode)
+	}},
+	{"billed_at", func(t *paddle.Transaction) string { return timestamp(t.BilledAt) }},
+}
+
+var addressColumns = []column[paddle.Address]{
+	{"id", func(a *paddle.Address) string { return a.Id }},
+	{"customer_id", func(a *paddle.Address) string { return a.CustomerId }},
+	{"first_line", func(a *paddle.Address) string { return str(a.FirstLine) }},
+	{"city", func(a *paddle.Address) string { return str(a.City) }},
+	{"postal_code", func(a *paddle.Address) string { return str(a.PostalCode) }},
+	{"country_code", func(a *paddle.Address) string { return a.CountryCode }},
+	{"status", func(a *paddle.Address) string { return string(a.Status) }},
+}
+
+var businessColumns = []column[paddle.Business]{
+	{"id", func(b *paddle.Business) string { return b.Id }},
+	{"customer_id", func(b *paddle.Business) string { return b.CustomerId }},
+	{"name", func(b *paddle.Business) string { return b.Name }},
+	{"tax_identifier", func(b *paddle.Business) string { return str(b.TaxIdentifier) }},
+	{"status", func(b *paddle.Business) string { return string(b.Status) }},
+}
+
+var discountColumns = []column[paddle.Discount]{
+	{"id", func(d *paddle.Discount) string { return d.Id }},
+	{"code", func(d *paddle.Discount) string { return str(d.Code) }},
+	{"type", func(d *paddle.Discount) string { return string(d.Type) }},
+	{"amount", func(d *paddle.Discount) string { return d.Amount.String() }},
+	{"status", func(d *paddle.Discount) string { return string(d.Status) }},
+	{"times_used", func(d *paddle.Discount) string { return fmt.Sprint(d.TimesUsed) }},
+	{"expires_at", func(d *paddle.Discount) string { return timestamp(d.ExpiresAt) }},
+}
+
+var adjustmentColumns = []column[paddle.Adjustment]{
+	{"id", func(a *paddle.Adjustment) string { return a.Id }},
+	{"action", func(a *paddle.Adjustment) string { return string(a.Action) }},
+	{"transaction_id", func(a *paddle.Adjustment) string { return a.TransactionId }},
+	{"status", func(a *paddle.Adjustment) string { return string(a.Status) }},
+	{"total", func(a *paddle.Adjustment) string { return money(a.Totals.Total, a.CurrencyCode) }},
+	{"reason", func(a *paddle.Adjustment) string { return a.Reason }},
+}
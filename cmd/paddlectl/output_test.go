@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/texm/go-paddle"
+)
+
+func TestRenderJSON(t *testing.T) {
+	ada := &paddle.Customer{Id: "ctm_1", Email: "ada@example.com"}
+	tests := []struct {
+		name   string
+		render func(a *app) error
+		want   string
+	}{
+		{"nil list", func(a *app) error { return render(a, customerColumns, nil) }, "[]"},
+		{"empty list", func(a *app) error { return render(a, customerColumns, []*paddle.Customer{}) }, "[]"},
+		{"single item list", func(a *app) error { return render(a, customerColumns, []*paddle.Customer{ada}) }, "["},
+		{"item", func(a *app) error { return renderItem(a, customerColumns, ada) }, "{"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := tt.render(&app{out: &out, format: formatJSON}); err != nil {
+				t.Fatal(err)
+			}
+			if !json.Valid(out.Bytes()) || !bytes.HasPrefix(out.Bytes(), []byte(tt.want)) {
+				t.Errorf("output = %s, want JSON starting with %s", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderTable(t *testing.T) {
+	name := "Ada Lovelace"
+	customers := []*paddle.Customer{
+		{Id: "ctm_1", Email: "ada@example.com", Name: &name, Status: paddle.StatusActive, CreatedAt: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)},
+		{Id: "ctm_22", Email: "bo@example.com", Status: paddle.StatusArchived},
+	}
+	tests := []struct {
+		name   string
+		render func(a *app) error
+		want   string
+	}{
+		{"list", func(a *app) error { return render(a, customerColumns, customers) },
+			"ID      EMAIL            NAME          STATUS    CREATED_AT\n" +
+				"ctm_1   ada@example.com  Ada Lovelace  active    2024-03-01T10:00:00Z\n" +
+				"ctm_22  bo@example.com   -             archived  -\n"},
+		{"empty list", func(a *app) error { return render(a, customerColumns, nil) },
+			"ID  EMAIL  NAME  STATUS  CREATED_AT\n"},
+		{"item", func(a *app) error { return renderItem(a, customerColumns, customers[1]) },
+			"ID      EMAIL           NAME  STATUS    CREATED_AT\n" +
+				"ctm_22  bo@example.com  -     archived  -\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := tt.render(&app{out: &out, format: formatTable}); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("output =\n%s\nwant\n%s", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderCSV(t *testing.T) {
+	price := &paddle.Price{
+		Id:          "pri_1",
+		ProductId:   "pro_1",
+		Description: `Seats, billed "monthly"`,
+		UnitPrice:   paddle.NewMoney(paddle.NewAmount(1050), paddle.CurrencyCodeUSD),
+		Status:      paddle.StatusActive,
+	}
+	tests := []struct {
+		name   string
+		render func(a *app) error
+		want   string
+	}{
+		{"list", func(a *app) error { return render(a, priceColumns, []*paddle.Price{price}) },
+			"id,product_id,description,unit_price,billing_cycle,status\n" +
+				`pri_1,pro_1,"Seats, billed ""monthly""",10.50 USD,one-time,active` + "\n"},
+		{"empty list", func(a *app) error { return render(a, priceColumns, []*paddle.Price{}) },
+			"id,product_id,description,unit_price,billing_cycle,status\n"},
+		{"item", func(a *app) error { return renderItem(a, priceColumns, price) },
+			"id,product_id,description,unit_price,billing_cycle,status\n" +
+				`pri_1,pro_1,"Seats, billed ""monthly""",10.50 USD,one-time,active` + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := tt.render(&app{out: &out, format: formatCSV}); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("output =\n%s\nwant\n%s", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderUnknownFormat(t *testing.T) {
+	var out bytes.Buffer
+	if err := render(&app{out: &out, format: "yaml"}, customerColumns, nil); err == nil {
+		t.Error("render() with format yaml = nil, want an error")
+	}
+}
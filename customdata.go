@@ -0,0 +1,165 @@
+package paddle
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// CustomData holds the structured key-value data Paddle stores against a
+// resource. Use NewCustomData and CustomDataAs to convert to and from
+// application-defined structs.
+type CustomData map[string]any
+
+// CustomDataValidator may be implemented by custom data structs to have them
+// validated whenever they are encoded or decoded.
+type CustomDataValidator interface {
+	Validate() error
+}
+
+type CustomDataHolder interface {
+	GetCustomData() CustomData
+}
+
+func (c *Customer) GetCustomData() CustomData     { return c.CustomData }
+func (p *Product) GetCustomData() CustomData      { return p.CustomData }
+func (p *Price) GetCustomData() CustomData        { return p.CustomData }
+func (s *Subscription) GetCustomData() CustomData { return s.CustomData }
+func (t *Transaction) GetCustomData() CustomData  { return t.CustomData }
+
+// NewCustomData encodes v, typically a struct, as custom data. v must encode
+// to a JSON object.
+func NewCustomData(v any) (CustomData, error) {
+	if validator, ok := v.(CustomDataValidator); ok {
+		if validErr := validator.Validate(); validErr != nil {
+			return nil, validErr
+		}
+	}
+	data, jsonErr := json.Marshal(v)
+	if jsonErr != nil {
+		return nil, fmt.Errorf("failed to encode custom data: %w", jsonErr)
+	}
+	if bytes.Equal(data, []byte("null")) {
+		return nil, nil
+	}
+	var cd CustomData
+	if decodeErr := json.Unmarshal(data, &cd); decodeErr != nil {
+		return nil, fmt.Errorf("custom data must be a JSON object: %w", decodeErr)
+	}
+	if validErr := cd.Validate(); validErr != nil {
+		return nil, validErr
+	}
+	return cd, nil
+}
+
+// Decode decodes the custom data into v.
+func (c CustomData) Decode(v any) error {
+	return decodeCustomData(c, v)
+}
+
+// DecodeKey decodes the value stored under key into v, reporting whether
+// the key is present.
+func (c CustomData) DecodeKey(key string, v any) (bool, error) {
+	value, ok := c[key]
+	if !ok {
+		return false, nil
+	}
+	if decodeErr := decodeCustomData(value, v); decodeErr != nil {
+		return true, fmt.Errorf("%s: %w", key, decodeErr)
+	}
+	return true, nil
+}
+
+func decodeCustomData(data any, v any) error {
+	encoded, jsonErr := json.Marshal(data)
+	if jsonErr != nil {
+		return jsonErr
+	}
+	if decodeErr := json.Unmarshal(encoded, v); decodeErr != nil {
+		return fmt.Errorf("failed to decode custom data: %w", decodeErr)
+	}
+	if validator, ok := v.(CustomDataValidator); ok {
+		return validator.Validate()
+	}
+	return nil
+}
+
+// Validate checks that the custom data can be sent to Paddle: every key,
+// including those of nested objects, must be non-blank and every value must
+// encode to JSON, so numbers must be finite.
+func (c CustomData) Validate() error {
+	for key, value := range c {
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("custom data keys must not be blank, got %q", key)
+		}
+		data, jsonErr := json.Marshal(value)
+		if jsonErr != nil {
+			return fmt.Errorf("custom data %q is not valid JSON: %w", key, jsonErr)
+		}
+		var decoded any
+		if decodeErr := json.Unmarshal(data, &decoded); decodeErr != nil {
+			return fmt.Errorf("custom data %q is not valid JSON: %w", key, decodeErr)
+		}
+		if keyErr := checkCustomDataKeys(key, decoded); keyErr != nil {
+			return keyErr
+		}
+	}
+	return nil
+}
+
+// checkCustomDataKeys checks the keys of objects nested within a decoded
+// custom data value.
+func checkCustomDataKeys(path string, v any) error {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if strings.TrimSpace(key) == "" {
+				return fmt.Errorf("custom data keys must not be blank, got %q in %s", key, path)
+			}
+			if err := checkCustomDataKeys(path+"."+key, value); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for i, value := range v {
+			if err := checkCustomDataKeys(fmt.Sprintf("%s[%d]", path, i), value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// CustomDataAs decodes the custom data of a resource into a new T, e.g.
+// CustomDataAs[TenantData](subscription). It returns nil if the resource has
+// no custom data, and an error if the resource is nil.
+func CustomDataAs[T any](r CustomDataHolder) (*T, error) {
+	if r == nil {
+		return nil, errors.New("custom data of a nil resource")
+	}
+	if v := reflect.ValueOf(r); v.Kind() == reflect.Pointer && v.IsNil() {
+		return nil, fmt.Errorf("custom data of a nil %T", r)
+	}
+	cd := r.GetCustomData()
+	if cd == nil {
+		return nil, nil
+	}
+	var v T
+	if decodeErr := cd.Decode(&v); decodeErr != nil {
+		return nil, decodeErr
+	}
+	return &v, nil
+}
+
+func validateCustomData(cd *CustomData) error {
+	if cd == nil {
+		return nil
+	}
+	if validErr := cd.Validate(); validErr != nil {
+		return fmt.Errorf("%w: custom_data: %w", ErrInvalidParams, validErr)
+	}
+	return nil
+}
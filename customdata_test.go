@@ -0,0 +1,120 @@
+package paddle
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+type tenantData struct {
+	TenantId string `json:"tenant_id"`
+	Seats    int    `json:"seats,omitempty"`
+}
+
+func (d *tenantData) Validate() error {
+	if d.TenantId == "" {
+		return errors.New("tenant_id is required")
+	}
+	return nil
+}
+
+func TestCustomDataValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    CustomData
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"flat", CustomData{"tenant_id": "t_1", "seats": 5, "trial": true, "note": nil}, false},
+		{"nested", CustomData{"limits": map[string]any{"seats": 5, "features": []any{"sso", map[string]any{"name": "audit"}}}}, false},
+		{"struct value", CustomData{"tenant": tenantData{TenantId: "t_1"}}, false},
+		{"empty key", CustomData{"": "x"}, true},
+		{"blank key", CustomData{"  ": "x"}, true},
+		{"blank nested key", CustomData{"limits": map[string]any{" ": 5}}, true},
+		{"blank key in array", CustomData{"items": []any{map[string]any{"": 1}}}, true},
+		{"blank key in struct value", CustomData{"tags": map[string]string{"": "x"}}, true},
+		{"nan", CustomData{"ratio": math.NaN()}, true},
+		{"infinite nested", CustomData{"limits": map[string]any{"max": math.Inf(1)}}, true},
+		{"func", CustomData{"callback": func() {}}, true},
+		{"channel", CustomData{"events": make(chan int)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.data.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	bad := CustomData{"": "x"}
+	if err := (&UpdateCustomerParams{CustomData: &bad}).validate(false); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("validate() with invalid custom data = %v, want %v", err, ErrInvalidParams)
+	}
+}
+
+func TestNewCustomData(t *testing.T) {
+	cd, err := NewCustomData(&tenantData{TenantId: "t_1", Seats: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cd["tenant_id"] != "t_1" || cd["seats"] != float64(3) {
+		t.Errorf("NewCustomData() = %v, want tenant_id and seats", cd)
+	}
+	if cd, err := NewCustomData(nil); cd != nil || err != nil {
+		t.Errorf("NewCustomData(nil) = %v, %v, want nil", cd, err)
+	}
+	for _, v := range []any{&tenantData{}, []string{"a"}, "text", map[string]any{"": 1}} {
+		if _, err := NewCustomData(v); err == nil {
+			t.Errorf("NewCustomData(%#v) succeeded, want an error", v)
+		}
+	}
+}
+
+func TestCustomDataAs(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource CustomDataHolder
+		want     *tenantData
+		wantErr  bool
+	}{
+		{"decoded", &Subscription{CustomData: CustomData{"tenant_id": "t_1", "seats": 2}}, &tenantData{TenantId: "t_1", Seats: 2}, false},
+		{"no custom data", &Customer{}, nil, false},
+		{"fails validation", &Price{CustomData: CustomData{"seats": 2}}, nil, true},
+		{"wrong type", &Product{CustomData: CustomData{"tenant_id": 5}}, nil, true},
+		{"nil resource", (*Transaction)(nil), nil, true},
+		{"nil interface", nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CustomDataAs[tenantData](tt.resource)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CustomDataAs() = %v, want error %v", err, tt.wantErr)
+			}
+			if (got == nil) != (tt.want == nil) || got != nil && *got != *tt.want {
+				t.Errorf("CustomDataAs() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCustomDataDecodeKey(t *testing.T) {
+	cd := CustomData{"tenant": map[string]any{"tenant_id": "t_1", "seats": 4}, "empty": map[string]any{}, "count": 3}
+	tests := []struct {
+		key     string
+		want    tenantData
+		wantOk  bool
+		wantErr bool
+	}{
+		{"tenant", tenantData{TenantId: "t_1", Seats: 4}, true, false},
+		{"missing", tenantData{}, false, false},
+		{"empty", tenantData{}, true, true},
+		{"count", tenantData{}, true, true},
+	}
+	for _, tt := range tests {
+		var got tenantData
+		ok, err := cd.DecodeKey(tt.key, &got)
+		if ok != tt.wantOk || (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("DecodeKey(%q) = %+v, %v, %v, want %+v, %v, error %v", tt.key, got, ok, err, tt.want, tt.wantOk, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+// Package entitlements derives which features a customer may use from their
+// subscriptions.
+//
+// Rules map prices and products to features, optionally with seats counted
+// from item quantities. Compute applies them to a customer's subscriptions,
+// taking account of trials, scheduled cancellations and pauses, and the
+// configured grace periods for past due and paused subscriptions.
+package entitlements
+
+import (
+	"sort"
+	"time"
+
+	"github.com/texm/go-paddle"
+)
+
+// Grant is what a price or product entitles a subscriber to.
+type Grant struct {
+	Features []string `json:"features"`
+	// Seats is the number of seats of each feature granted per unit of the
+	// item's quantity. Zero grants the features without seats.
+	Seats int `json:"seats,omitempty"`
+}
+
+type Grace struct {
+	// PastDue is how long a past due subscription keeps its entitlements,
+	// measured from the start of the billing period it failed to pay for.
+	PastDue time.Duration
+	// Paused is how long a paused subscription keeps its entitlements,
+	// measured from when the pause took effect. Zero removes them as soon as
+	// it is paused.
+	Paused time.Duration
+}
+
+type Rules struct {
+	Prices   map[string]Grant
+	Products map[string]Grant
+	// CustomDataKey, when set, also reads a Grant from this key of the
+	// custom data of each item's price and of the price's product, if it
+	// was included. For example, with the key "entitlements":
+	//
+	//	{"entitlements": {"features": ["sso", "audit_log"], "seats": 1}}
+	CustomDataKey string
+	Grace         Grace
+}
+
+type Entitlement struct {
+	Feature string `json:"feature"`
+	Seats   int    `json:"seats"`
+	// SubscriptionIds are the subscriptions granting the feature.
+	SubscriptionIds []string `json:"subscription_ids"`
+	// ExpiresAt is when the feature lapses unless a subscription changes,
+	// such as when a scheduled cancellation takes effect or a past due
+	// grace period ends. It is nil while the feature is renewing.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// InGrace is set when the feature is only granted by past due or paused
+	// subscriptions within their grace.
+	InGrace bool `json:"in_grace"`
+}
+
+type Entitlements struct {
+	CustomerId string                  `json:"customer_id"`
+	Features   map[string]*Entitlement `json:"features"`
+	ComputedAt time.Time               `json:"computed_at"`
+}
+
+func (e *Entitlements) Has(feature string) bool {
+	_, ok := e.Features[feature]
+	return ok
+}
+
+func (e *Entitlements) Seats(feature string) int {
+	if ent, ok := e.Features[feature]; ok {
+		return ent.Seats
+	}
+	return 0
+}
+
+// List returns the entitlements sorted by feature.
+func (e *Entitlements) List() []*Entitlement {
+	list := make([]*Entitlement, 0, len(e.Features))
+	for _, ent := range e.Features {
+		list = append(list, ent)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Feature < list[j].Feature })
+	return list
+}
+
+// Equal reports whether both grant the same features and seats.
+func (e *Entitlements) Equal(other *Entitlements) bool {
+	if len(e.Features) != len(other.Features) {
+		return false
+	}
+	for feature, ent := range e.Features {
+		if o, ok := other.Features[feature]; !ok || o.Seats != ent.Seats {
+			return false
+		}
+	}
+	return true
+}
+
+// Compute returns the customer's entitlements at now from their
+// subscriptions. Subscriptions belonging to other customers are ignored.
+func (r *Rules) Compute(customerId string, subscriptions []*paddle.Subscription, now time.Time) *Entitlements {
+	result := &Entitlements{CustomerId: customerId, Features: map[string]*Entitlement{}, ComputedAt: now}
+	for _, sub := range subscriptions {
+		if sub.CustomerId != customerId {
+			continue
+		}
+		granted, expiresAt, inGrace := r.standing(sub, now)
+		if !granted {
+			continue
+		}
+		for _, item := range sub.Items {
+			if item.Status == paddle.SubscriptionItemStatusInactive {
+				continue
+			}
+			for _, grant := range r.grants(&item.Price) {
+				for _, feature := range grant.Features {
+					ent, exists := result.Features[feature]
+					if !exists {
+						ent = &Entitlement{Feature: feature, ExpiresAt: expiresAt, InGrace: inGrace}
+						result.Features[feature] = ent
+					} else {
+						ent.ExpiresAt = later(ent.ExpiresAt, expiresAt)
+						ent.InGrace = ent.InGrace && inGrace
+					}
+					ent.Seats += grant.Seats * item.Quantity
+					if !contains(ent.SubscriptionIds, sub.Id) {
+						ent.SubscriptionIds = append(ent.SubscriptionIds, sub.Id)
+					}
+				}
+			}
+		}
+	}
+	return result
+}
+
+// standing reports whether the subscription grants anything at now, when
+// that ends, and whether it is only granted through a grace period.
+func (r *Rules) standing(sub *paddle.Subscription, now time.Time) (bool, *time.Time, bool) {
+	var expiresAt *time.Time
+	inGrace := false
+
+	switch sub.Status {
+	case paddle.SubscriptionStatusActive, paddle.SubscriptionStatusTrialing:
+	case paddle.SubscriptionStatusPastDue:
+		start := sub.UpdatedAt
+		if sub.CurrentBillingPeriod != nil {
+			start = sub.CurrentBillingPeriod.StartsAt
+		}
+		deadline := start.Add(r.Grace.PastDue)
+		if !now.Before(deadline) {
+			return false, nil, false
+		}
+		expiresAt, inGrace = &deadline, true
+	case paddle.SubscriptionStatusPaused:
+		start := sub.UpdatedAt
+		if sub.PausedAt != nil {
+			start = *sub.PausedAt
+		}
+		deadline := start.Add(r.Grace.Paused)
+		if !now.Before(deadline) {
+			return false, nil, false
+		}
+		expiresAt, inGrace = &deadline, true
+	default:
+		return false, nil, false
+	}
+
+	// Webhooks for a scheduled change can arrive after it takes effect, so
+	// honour its effective time rather than waiting for the new status.
+	if change := sub.ScheduledChange; change != nil {
+		var ends time.Time
+		switch change.Action {
+		case paddle.SubscriptionScheduledChangeActionCancel:
+			ends = change.EffectiveAt
+		case paddle.SubscriptionScheduledChangeActionPause:
+			ends = change.EffectiveAt.Add(r.Grace.Paused)
+		}
+		if !ends.IsZero() {
+			if !now.Before(ends) {
+				return false, nil, false
+			}
+			if !now.Before(change.EffectiveAt) {
+				inGrace = true
+			}
+			if expiresAt == nil || ends.Before(*expiresAt) {
+				expiresAt = &ends
+			}
+		}
+	}
+	return true, expiresAt, inGrace
+}
+
+func (r *Rules) grants(price *paddle.Price) []Grant {
+	var grants []Grant
+	if g, ok := r.Prices[price.Id]; ok {
+		grants = append(grants, g)
+	}
+	if g, ok := r.Products[price.ProductId]; ok {
+		grants = append(grants, g)
+	}
+	if r.CustomDataKey != "" {
+		if g, ok := customDataGrant(price.CustomData, r.CustomDataKey); ok {
+			grants = append(grants, g)
+		}
+		if price.Product != nil {
+			if g, ok := customDataGrant(price.Product.CustomData, r.CustomDataKey); ok {
+				grants = append(grants, g)
+			}
+		}
+	}
+	return grants
+}
+
+func customDataGrant(data paddle.CustomData, key string) (Grant, bool) {
+	var g Grant
+	if ok, decodeErr := data.DecodeKey(key, &g); !ok || decodeErr != nil {
+		return Grant{}, false
+	}
+	return g, true
+}
+
+// later returns the later expiry, where nil never expires.
+func later(a *time.Time, b *time.Time) *time.Time {
+	if a == nil || b == nil {
+		return nil
+	}
+	if a.After(*b) {
+		return a
+	}
+	return b
+}
+
+func contains(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,207 @@
+package entitlements
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/texm/go-paddle"
+)
+
+var (
+	testNow = time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	day     = 24 * time.Hour
+)
+
+func at(d time.Duration) *time.Time {
+	t := testNow.Add(d)
+	return &t
+}
+
+func newRules(grace Grace) *Rules {
+	return &Rules{
+		Prices:   map[string]Grant{"pri_team": {Features: []string{"sso"}, Seats: 1}},
+		Products: map[string]Grant{"pro_team": {Features: []string{"reports"}}},
+		Grace:    grace,
+	}
+}
+
+func newSubscription(id string, status paddle.SubscriptionStatus, quantity int) *paddle.Subscription {
+	return &paddle.Subscription{
+		Id:                   id,
+		CustomerId:           "ctm_1",
+		Status:               status,
+		UpdatedAt:            testNow.Add(-2 * day),
+		CurrentBillingPeriod: &paddle.TimePeriod{StartsAt: testNow.Add(-3 * day), EndsAt: testNow.Add(27 * day)},
+		Items: []paddle.SubscriptionItem{{
+			Status:   paddle.SubscriptionItemStatusActive,
+			Quantity: quantity,
+			Price:    paddle.Price{Id: "pri_team", ProductId: "pro_team"},
+		}},
+	}
+}
+
+func TestCompute(t *testing.T) {
+	grace := Grace{PastDue: 7 * day, Paused: 3 * day}
+	scheduled := func(action paddle.SubscriptionScheduledChangeAction, in time.Duration) func(*paddle.Subscription) {
+		return func(s *paddle.Subscription) {
+			s.ScheduledChange = &paddle.SubscriptionScheduledChange{Action: action, EffectiveAt: *at(in)}
+		}
+	}
+	tests := []struct {
+		name   string
+		status paddle.SubscriptionStatus
+		grace  Grace
+		modify func(*paddle.Subscription)
+		// want is the sso entitlement, or nil when nothing is granted.
+		want *Entitlement
+	}{
+		{name: "active", status: paddle.SubscriptionStatusActive, grace: grace,
+			want: &Entitlement{}},
+		{name: "trialing", status: paddle.SubscriptionStatusTrialing, grace: grace,
+			want: &Entitlement{}},
+		{name: "canceled", status: paddle.SubscriptionStatusCanceled, grace: grace},
+		{name: "unknown status", status: "suspended", grace: grace},
+		{name: "another customer's", status: paddle.SubscriptionStatusActive, grace: grace,
+			modify: func(s *paddle.Subscription) { s.CustomerId = "ctm_2" }},
+		{name: "inactive item", status: paddle.SubscriptionStatusActive, grace: grace,
+			modify: func(s *paddle.Subscription) { s.Items[0].Status = paddle.SubscriptionItemStatusInactive }},
+
+		{name: "past due inside grace", status: paddle.SubscriptionStatusPastDue, grace: grace,
+			want: &Entitlement{ExpiresAt: at(4 * day), InGrace: true}},
+		{name: "past due outside grace", status: paddle.SubscriptionStatusPastDue, grace: grace,
+			modify: func(s *paddle.Subscription) { s.CurrentBillingPeriod.StartsAt = testNow.Add(-7 * day) }},
+		{name: "past due without billing period uses updated_at", status: paddle.SubscriptionStatusPastDue, grace: grace,
+			modify: func(s *paddle.Subscription) { s.CurrentBillingPeriod = nil },
+			want:   &Entitlement{ExpiresAt: at(5 * day), InGrace: true}},
+		{name: "past due without grace", status: paddle.SubscriptionStatusPastDue},
+		{name: "past due with an earlier scheduled cancel", status: paddle.SubscriptionStatusPastDue, grace: grace,
+			modify: scheduled(paddle.SubscriptionScheduledChangeActionCancel, day),
+			want:   &Entitlement{ExpiresAt: at(day), InGrace: true}},
+
+		{name: "paused inside grace", status: paddle.SubscriptionStatusPaused, grace: grace,
+			modify: func(s *paddle.Subscription) { s.PausedAt = at(-day) },
+			want:   &Entitlement{ExpiresAt: at(2 * day), InGrace: true}},
+		{name: "paused outside grace", status: paddle.SubscriptionStatusPaused, grace: grace,
+			modify: func(s *paddle.Subscription) { s.PausedAt = at(-3 * day) }},
+		{name: "paused without paused_at uses updated_at", status: paddle.SubscriptionStatusPaused, grace: grace,
+			want: &Entitlement{ExpiresAt: at(day), InGrace: true}},
+		{name: "paused without grace", status: paddle.SubscriptionStatusPaused,
+			modify: func(s *paddle.Subscription) { s.PausedAt = at(-time.Minute) }},
+		{name: "paused with a scheduled resume", status: paddle.SubscriptionStatusPaused, grace: grace,
+			modify: func(s *paddle.Subscription) {
+				s.PausedAt = at(-day)
+				scheduled(paddle.SubscriptionScheduledChangeActionResume, day)(s)
+			},
+			want: &Entitlement{ExpiresAt: at(2 * day), InGrace: true}},
+
+		{name: "scheduled cancel before effect", status: paddle.SubscriptionStatusActive, grace: grace,
+			modify: scheduled(paddle.SubscriptionScheduledChangeActionCancel, day),
+			want:   &Entitlement{ExpiresAt: at(day)}},
+		{name: "scheduled cancel after effect", status: paddle.SubscriptionStatusActive, grace: grace,
+			modify: scheduled(paddle.SubscriptionScheduledChangeActionCancel, -time.Second)},
+		{name: "scheduled cancel at effect", status: paddle.SubscriptionStatusActive, grace: grace,
+			modify: scheduled(paddle.SubscriptionScheduledChangeActionCancel, 0)},
+		{name: "scheduled cancel on a trial", status: paddle.SubscriptionStatusTrialing, grace: grace,
+			modify: scheduled(paddle.SubscriptionScheduledChangeActionCancel, day),
+			want:   &Entitlement{ExpiresAt: at(day)}},
+
+		{name: "scheduled pause before effect", status: paddle.SubscriptionStatusActive, grace: grace,
+			modify: scheduled(paddle.SubscriptionScheduledChangeActionPause, day),
+			want:   &Entitlement{ExpiresAt: at(4 * day)}},
+		{name: "scheduled pause after effect inside grace", status: paddle.SubscriptionStatusActive, grace: grace,
+			modify: scheduled(paddle.SubscriptionScheduledChangeActionPause, -day),
+			want:   &Entitlement{ExpiresAt: at(2 * day), InGrace: true}},
+		{name: "scheduled pause after effect outside grace", status: paddle.SubscriptionStatusActive, grace: grace,
+			modify: scheduled(paddle.SubscriptionScheduledChangeActionPause, -3*day)},
+		{name: "scheduled pause before effect without grace", status: paddle.SubscriptionStatusActive,
+			modify: scheduled(paddle.SubscriptionScheduledChangeActionPause, day),
+			want:   &Entitlement{ExpiresAt: at(day)}},
+		{name: "scheduled pause after effect without grace", status: paddle.SubscriptionStatusActive,
+			modify: scheduled(paddle.SubscriptionScheduledChangeActionPause, -time.Second)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := newSubscription("sub_1", tt.status, 5)
+			if tt.modify != nil {
+				tt.modify(sub)
+			}
+			got := newRules(tt.grace).Compute("ctm_1", []*paddle.Subscription{sub}, testNow)
+			if got.CustomerId != "ctm_1" || !got.ComputedAt.Equal(testNow) {
+				t.Errorf("Compute() = %s at %v", got.CustomerId, got.ComputedAt)
+			}
+			if tt.want == nil {
+				if len(got.Features) != 0 {
+					t.Errorf("Compute() granted %v, want nothing", got.List())
+				}
+				return
+			}
+			want := &Entitlement{Feature: "sso", Seats: 5, SubscriptionIds: []string{"sub_1"}, ExpiresAt: tt.want.ExpiresAt, InGrace: tt.want.InGrace}
+			if ent := got.Features["sso"]; !reflect.DeepEqual(ent, want) {
+				t.Errorf("sso = %+v, want %+v", ent, want)
+			}
+			if !got.Has("reports") || got.Seats("reports") != 0 {
+				t.Errorf("reports granted = %v with %d seats, want granted without seats", got.Has("reports"), got.Seats("reports"))
+			}
+		})
+	}
+}
+
+func TestComputeCombinesSubscriptions(t *testing.T) {
+	rules := newRules(Grace{PastDue: 7 * day})
+	rules.CustomDataKey = "entitlements"
+
+	active := newSubscription("sub_1", paddle.SubscriptionStatusActive, 5)
+	trialing := newSubscription("sub_2", paddle.SubscriptionStatusTrialing, 2)
+	trialing.ScheduledChange = &paddle.SubscriptionScheduledChange{Action: paddle.SubscriptionScheduledChangeActionCancel, EffectiveAt: *at(day)}
+	pastDue := newSubscription("sub_3", paddle.SubscriptionStatusPastDue, 1)
+	pastDue.Items[0].Price = paddle.Price{
+		Id:         "pri_addon",
+		CustomData: paddle.CustomData{"entitlements": map[string]any{"features": []any{"sso", "audit_log"}, "seats": 2}},
+		Product:    &paddle.Product{CustomData: paddle.CustomData{"entitlements": map[string]any{"features": []any{"exports"}}}},
+	}
+	canceled := newSubscription("sub_4", paddle.SubscriptionStatusCanceled, 10)
+
+	got := rules.Compute("ctm_1", []*paddle.Subscription{active, trialing, pastDue, canceled}, testNow)
+	want := []*Entitlement{
+		{Feature: "audit_log", Seats: 2, SubscriptionIds: []string{"sub_3"}, ExpiresAt: at(4 * day), InGrace: true},
+		{Feature: "exports", SubscriptionIds: []string{"sub_3"}, ExpiresAt: at(4 * day), InGrace: true},
+		{Feature: "reports", SubscriptionIds: []string{"sub_1", "sub_2"}},
+		{Feature: "sso", Seats: 5 + 2 + 2, SubscriptionIds: []string{"sub_1", "sub_2", "sub_3"}},
+	}
+	list := got.List()
+	if len(list) != len(want) {
+		t.Fatalf("Compute() granted %d features, want %d", len(list), len(want))
+	}
+	for i, ent := range list {
+		if !reflect.DeepEqual(ent, want[i]) {
+			t.Errorf("%s = %+v, want %+v", want[i].Feature, ent, want[i])
+		}
+	}
+
+	// Only the subscriptions which expire remain.
+	got = rules.Compute("ctm_1", []*paddle.Subscription{trialing, pastDue}, testNow)
+	if ent := got.Features["sso"]; ent.ExpiresAt == nil || !ent.ExpiresAt.Equal(*at(4 * day)) || ent.InGrace {
+		t.Errorf("sso = %+v, want the later expiry and not in grace", ent)
+	}
+}
+
+func TestEntitlementsEqual(t *testing.T) {
+	a := &Entitlements{Features: map[string]*Entitlement{"sso": {Feature: "sso", Seats: 2}}}
+	tests := []struct {
+		name string
+		b    map[string]*Entitlement
+		want bool
+	}{
+		{"same", map[string]*Entitlement{"sso": {Feature: "sso", Seats: 2, InGrace: true}}, true},
+		{"different seats", map[string]*Entitlement{"sso": {Feature: "sso", Seats: 3}}, false},
+		{"different feature", map[string]*Entitlement{"exports": {Feature: "exports", Seats: 2}}, false},
+		{"extra feature", map[string]*Entitlement{"sso": {Feature: "sso", Seats: 2}, "exports": {Feature: "exports"}}, false},
+		{"none", map[string]*Entitlement{}, false},
+	}
+	for _, tt := range tests {
+		if got := a.Equal(&Entitlements{Features: tt.b}); got != tt.want {
+			t.Errorf("%s: Equal() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+module github.com/texm/go-paddle/entitlements
+
+go 1.21
+
+require (
+	github.com/texm/go-paddle v0.1.0
+	github.com/texm/go-paddle/mirror v0.1.0
+)
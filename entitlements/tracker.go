@@ -0,0 +1,192 @@
+package entitlements
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/texm/go-paddle"
+	"github.com/texm/go-paddle/mirror"
+)
+
+// Source loads a customer's subscriptions.
+type Source interface {
+	CustomerSubscriptions(ctx context.Context, customerId string) ([]*paddle.Subscription, error)
+}
+
+type apiSource struct {
+	api paddle.API
+}
+
+// FromAPI loads subscriptions from the Paddle API.
+func FromAPI(api paddle.API) Source {
+	return &apiSource{api: api}
+}
+
+func (s *apiSource) CustomerSubscriptions(ctx context.Context, customerId string) ([]*paddle.Subscription, error) {
+	return s.api.SubscriptionsAPI().List(ctx, &paddle.ListSubscriptionsParams{CustomerIds: []string{customerId}})
+}
+
+type mirrorSource struct {
+	m *mirror.Mirror
+}
+
+// FromMirror loads subscriptions from a local mirror.
+func FromMirror(m *mirror.Mirror) Source {
+	return &mirrorSource{m: m}
+}
+
+func (s *mirrorSource) CustomerSubscriptions(ctx context.Context, customerId string) ([]*paddle.Subscription, error) {
+	return s.m.Subscriptions(ctx, mirror.SubscriptionQuery{CustomerId: customerId})
+}
+
+// Tracker keeps each customer's subscriptions up to date from webhook
+// events and computes entitlements from them. A customer's subscriptions
+// are loaded from the Source the first time they are needed.
+//
+// Grace periods and scheduled changes lapse without an event, so
+// entitlements are computed when they are read rather than cached.
+type Tracker struct {
+	Rules  *Rules
+	Source Source
+	Now    func() time.Time
+	// OnChange, if set, is called after a webhook event changes a customer's
+	// features or seats.
+	OnChange func(ctx context.Context, before *Entitlements, after *Entitlements)
+
+	mu        sync.Mutex
+	customers map[string]map[string]*paddle.Subscription
+	loaded    map[string]bool
+	// events serializes HandleEvent per customer, so that OnChange sees
+	// each change once and in order.
+	events map[string]*sync.Mutex
+}
+
+func NewTracker(rules *Rules, source Source) *Tracker {
+	return &Tracker{
+		Rules:     rules,
+		Source:    source,
+		Now:       time.Now,
+		customers: map[string]map[string]*paddle.Subscription{},
+		loaded:    map[string]bool{},
+		events:    map[string]*sync.Mutex{},
+	}
+}
+
+func (t *Tracker) Entitlements(ctx context.Context, customerId string) (*Entitlements, error) {
+	subs, loadErr := t.subscriptions(ctx, customerId)
+	if loadErr != nil {
+		return nil, loadErr
+	}
+	return t.Rules.Compute(customerId, subs, t.Now()), nil
+}
+
+// Has reports whether the customer is entitled to the feature.
+func (t *Tracker) Has(ctx context.Context, customerId string, feature string) (bool, error) {
+	e, entErr := t.Entitlements(ctx, customerId)
+	if entErr != nil {
+		return false, entErr
+	}
+	return e.Has(feature), nil
+}
+
+// Put records a subscription, unless a newer copy is already held.
+func (t *Tracker) Put(sub *paddle.Subscription) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.put(sub)
+}
+
+func (t *Tracker) put(sub *paddle.Subscription) {
+	subs, ok := t.customers[sub.CustomerId]
+	if !ok {
+		subs = map[string]*paddle.Subscription{}
+		t.customers[sub.CustomerId] = subs
+	}
+	if existing, ok := subs[sub.Id]; ok && existing.UpdatedAt.After(sub.UpdatedAt) {
+		return
+	}
+	subs[sub.Id] = sub
+}
+
+// Forget drops a customer's subscriptions so they are reloaded when next
+// needed.
+func (t *Tracker) Forget(customerId string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.customers, customerId)
+	delete(t.loaded, customerId)
+}
+
+// HandleEvent applies a subscription webhook event. Other events are
+// ignored.
+func (t *Tracker) HandleEvent(ctx context.Context, event *paddle.WebhookEvent) error {
+	if !strings.HasPrefix(event.Type, "subscription.") {
+		return nil
+	}
+	var sub paddle.Subscription
+	if jsonErr := json.Unmarshal(event.Data, &sub); jsonErr != nil {
+		return fmt.Errorf("failed to decode %s data: %w", event.Type, jsonErr)
+	}
+
+	lock := t.eventLock(sub.CustomerId)
+	lock.Lock()
+	defer lock.Unlock()
+
+	before, beforeErr := t.Entitlements(ctx, sub.CustomerId)
+	if beforeErr != nil {
+		return beforeErr
+	}
+	t.Put(&sub)
+	after, afterErr := t.Entitlements(ctx, sub.CustomerId)
+	if afterErr != nil {
+		return afterErr
+	}
+	if t.OnChange != nil && !before.Equal(after) {
+		t.OnChange(ctx, before, after)
+	}
+	return nil
+}
+
+// eventLock returns the lock serializing events for the customer. Locks are
+// kept for the tracker's lifetime, including after Forget.
+func (t *Tracker) eventLock(customerId string) *sync.Mutex {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	lock, ok := t.events[customerId]
+	if !ok {
+		lock = &sync.Mutex{}
+		t.events[customerId] = lock
+	}
+	return lock
+}
+
+func (t *Tracker) subscriptions(ctx context.Context, customerId string) ([]*paddle.Subscription, error) {
+	t.mu.Lock()
+	loaded := t.loaded[customerId]
+	t.mu.Unlock()
+
+	if !loaded && t.Source != nil {
+		fetched, fetchErr := t.Source.CustomerSubscriptions(ctx, customerId)
+		if fetchErr != nil {
+			return nil, fmt.Errorf("failed to load subscriptions for %s: %w", customerId, fetchErr)
+		}
+		t.mu.Lock()
+		for _, sub := range fetched {
+			t.put(sub)
+		}
+		t.loaded[customerId] = true
+		t.mu.Unlock()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	list := make([]*paddle.Subscription, 0, len(t.customers[customerId]))
+	for _, sub := range t.customers[customerId] {
+		list = append(list, sub)
+	}
+	return list, nil
+}
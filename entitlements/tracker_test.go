@@ -0,0 +1,223 @@
+package entitlements
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/texm/go-paddle"
+)
+
+type fakeSource struct {
+	subscriptions map[string][]*paddle.Subscription
+	err           error
+	loads         int
+}
+
+func (s *fakeSource) CustomerSubscriptions(_ context.Context, customerId string) ([]*paddle.Subscription, error) {
+	s.loads++
+	return s.subscriptions[customerId], s.err
+}
+
+type change struct {
+	before, after int
+}
+
+func newTestTracker(source Source) (*Tracker, *[]change) {
+	tracker := NewTracker(newRules(Grace{PastDue: 7 * day}), source)
+	tracker.Now = func() time.Time { return testNow }
+	var changes []change
+	tracker.OnChange = func(_ context.Context, before *Entitlements, after *Entitlements) {
+		changes = append(changes, change{before.Seats("sso"), after.Seats("sso")})
+	}
+	return tracker, &changes
+}
+
+func subscriptionEvent(t *testing.T, id string, sub *paddle.Subscription) *paddle.WebhookEvent {
+	t.Helper()
+	data, jsonErr := json.Marshal(sub)
+	if jsonErr != nil {
+		t.Fatal(jsonErr)
+	}
+	return &paddle.WebhookEvent{Id: id, Type: "subscription.updated", OccurredAt: sub.UpdatedAt, Data: data}
+}
+
+// version returns a copy of the subscription with the status, quantity and
+// updated_at given.
+func version(status paddle.SubscriptionStatus, quantity int, updatedAt time.Duration) *paddle.Subscription {
+	sub := newSubscription("sub_1", status, quantity)
+	sub.UpdatedAt = testNow.Add(updatedAt)
+	return sub
+}
+
+func TestTrackerHandleEvent(t *testing.T) {
+	tests := []struct {
+		name   string
+		source []*paddle.Subscription
+		events []*paddle.Subscription
+		// wantSeats is the sso seats after the events and wantChanges the
+		// seats before and after each OnChange call.
+		wantSeats   int
+		wantChanges []change
+	}{
+		{
+			name:        "in order",
+			events:      []*paddle.Subscription{version(paddle.SubscriptionStatusActive, 2, -2*time.Hour), version(paddle.SubscriptionStatusActive, 5, -time.Hour)},
+			wantSeats:   5,
+			wantChanges: []change{{0, 2}, {2, 5}},
+		},
+		{
+			name:        "older event after a newer one",
+			events:      []*paddle.Subscription{version(paddle.SubscriptionStatusActive, 5, -time.Hour), version(paddle.SubscriptionStatusActive, 2, -2*time.Hour)},
+			wantSeats:   5,
+			wantChanges: []change{{0, 5}},
+		},
+		{
+			name: "canceled then a late activation",
+			events: []*paddle.Subscription{
+				version(paddle.SubscriptionStatusActive, 3, -3*time.Hour),
+				version(paddle.SubscriptionStatusCanceled, 3, -time.Hour),
+				version(paddle.SubscriptionStatusActive, 3, -2*time.Hour),
+			},
+			wantSeats:   0,
+			wantChanges: []change{{0, 3}, {3, 0}},
+		},
+		{
+			name:        "redelivered event",
+			events:      []*paddle.Subscription{version(paddle.SubscriptionStatusActive, 2, -time.Hour), version(paddle.SubscriptionStatusActive, 2, -time.Hour)},
+			wantSeats:   2,
+			wantChanges: []change{{0, 2}},
+		},
+		{
+			name:        "change that keeps seats",
+			events:      []*paddle.Subscription{version(paddle.SubscriptionStatusActive, 2, -2*time.Hour), version(paddle.SubscriptionStatusPastDue, 2, -time.Hour)},
+			wantSeats:   2,
+			wantChanges: []change{{0, 2}},
+		},
+		{
+			name:        "newer than the source",
+			source:      []*paddle.Subscription{version(paddle.SubscriptionStatusActive, 2, -2*time.Hour)},
+			events:      []*paddle.Subscription{version(paddle.SubscriptionStatusActive, 4, -time.Hour)},
+			wantSeats:   4,
+			wantChanges: []change{{2, 4}},
+		},
+		{
+			name:      "older than the source",
+			source:    []*paddle.Subscription{version(paddle.SubscriptionStatusActive, 4, -time.Hour)},
+			events:    []*paddle.Subscription{version(paddle.SubscriptionStatusActive, 2, -2*time.Hour)},
+			wantSeats: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &fakeSource{subscriptions: map[string][]*paddle.Subscription{"ctm_1": tt.source}}
+			tracker, changes := newTestTracker(source)
+			ctx := context.Background()
+			for _, sub := range tt.events {
+				if err := tracker.HandleEvent(ctx, subscriptionEvent(t, "evt_1", sub)); err != nil {
+					t.Fatalf("HandleEvent() = %v", err)
+				}
+			}
+			got, entErr := tracker.Entitlements(ctx, "ctm_1")
+			if entErr != nil {
+				t.Fatal(entErr)
+			}
+			if seats := got.Seats("sso"); seats != tt.wantSeats {
+				t.Errorf("sso seats = %d, want %d", seats, tt.wantSeats)
+			}
+			if len(*changes) != len(tt.wantChanges) {
+				t.Fatalf("OnChange called with %v, want %v", *changes, tt.wantChanges)
+			}
+			for i, c := range *changes {
+				if c != tt.wantChanges[i] {
+					t.Errorf("OnChange %d = %v, want %v", i, c, tt.wantChanges[i])
+				}
+			}
+			if source.loads != 1 {
+				t.Errorf("source loaded %d times, want 1", source.loads)
+			}
+		})
+	}
+}
+
+func TestTrackerHandleEventConcurrently(t *testing.T) {
+	tracker, changes := newTestTracker(&fakeSource{})
+	const events = 20
+	var wg sync.WaitGroup
+	for i := 1; i <= events; i++ {
+		event := subscriptionEvent(t, fmt.Sprintf("evt_%d", i), version(paddle.SubscriptionStatusActive, i, time.Duration(i-events)*time.Minute))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := tracker.HandleEvent(context.Background(), event); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Each change starts where the previous one ended, whatever order the
+	// events were applied in.
+	seats := 0
+	for _, c := range *changes {
+		if c.before != seats || c.after <= c.before {
+			t.Fatalf("changes = %v, want each to start at the previous seats and increase", *changes)
+		}
+		seats = c.after
+	}
+	if seats != events {
+		t.Errorf("changes = %v, want them to end at %d seats", *changes, events)
+	}
+}
+
+func TestTrackerIgnoresOtherEvents(t *testing.T) {
+	source := &fakeSource{}
+	tracker, changes := newTestTracker(source)
+	event := &paddle.WebhookEvent{Id: "evt_1", Type: "transaction.completed", Data: json.RawMessage(`{"id":"txn_1"}`)}
+	if err := tracker.HandleEvent(context.Background(), event); err != nil {
+		t.Fatalf("HandleEvent() = %v", err)
+	}
+	if source.loads != 0 || len(*changes) != 0 {
+		t.Errorf("transaction event loaded %d times and changed %v", source.loads, *changes)
+	}
+
+	event = &paddle.WebhookEvent{Id: "evt_2", Type: "subscription.updated", Data: json.RawMessage(`[]`)}
+	if err := tracker.HandleEvent(context.Background(), event); err == nil {
+		t.Error("HandleEvent() with invalid data = nil")
+	}
+}
+
+func TestTrackerSource(t *testing.T) {
+	ctx := context.Background()
+	source := &fakeSource{subscriptions: map[string][]*paddle.Subscription{"ctm_1": {version(paddle.SubscriptionStatusActive, 2, -time.Hour)}}}
+	tracker, _ := newTestTracker(source)
+
+	for i := 0; i < 2; i++ {
+		if has, err := tracker.Has(ctx, "ctm_1", "sso"); err != nil || !has {
+			t.Fatalf("Has() = %v, %v", has, err)
+		}
+	}
+	if source.loads != 1 {
+		t.Errorf("source loaded %d times, want 1", source.loads)
+	}
+
+	source.subscriptions["ctm_1"] = []*paddle.Subscription{version(paddle.SubscriptionStatusCanceled, 2, -time.Minute)}
+	tracker.Forget("ctm_1")
+	if has, err := tracker.Has(ctx, "ctm_1", "sso"); err != nil || has {
+		t.Errorf("Has() after Forget = %v, %v, want the reloaded cancellation", has, err)
+	}
+
+	source.err = errors.New("unavailable")
+	event := subscriptionEvent(t, "evt_1", func() *paddle.Subscription {
+		sub := version(paddle.SubscriptionStatusActive, 1, 0)
+		sub.CustomerId = "ctm_2"
+		return sub
+	}())
+	if err := tracker.HandleEvent(ctx, event); !errors.Is(err, source.err) {
+		t.Errorf("HandleEvent() = %v, want the source's error", err)
+	}
+}
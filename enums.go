@@ -0,0 +1,157 @@
+package paddle
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Enum is implemented by every enumerated string type in this package.
+//
+// IsValid reports whether the value is well-formed, which may be true of
+// values added in newer API versions. IsKnown reports whether the value is
+// one of the constants defined by this version of the library.
+type Enum interface {
+	IsValid() bool
+	IsKnown() bool
+}
+
+func isValidEnum[T ~string](v T) bool {
+	if len(v) == 0 {
+		return false
+	}
+	for _, r := range v {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+func isKnownEnum[T ~string](v T, known ...T) bool {
+	for _, k := range known {
+		if v == k {
+			return true
+		}
+	}
+	return false
+}
+
+func (v SubscriptionAction) IsValid() bool { return isValidEnum(v) }
+func (v SubscriptionAction) IsKnown() bool {
+	return isKnownEnum(v, subscriptionActions...)
+}
+
+func (v ErrorType) IsValid() bool { return isValidEnum(v) }
+func (v ErrorType) IsKnown() bool {
+	return isKnownEnum(v, ErrorTypeRequest, ErrorTypeApi)
+}
+
+func (v CurrencyCode) IsValid() bool {
+	return len(v) == 3 && isValidEnum(v)
+}
+
+func (v CurrencyCode) IsKnown() bool {
+	return isKnownEnum(v, CurrencyCodeUSD, CurrencyCodeEUR, CurrencyCodeGBP, CurrencyCodeJPY, CurrencyCodeAUD,
+		CurrencyCodeCAD, CurrencyCodeCHF, CurrencyCodeHKD, CurrencyCodeSGD, CurrencyCodeSEK, CurrencyCodeARS,
+		CurrencyCodeBRL, CurrencyCodeCNY, CurrencyCodeCOP, CurrencyCodeCZK, CurrencyCodeDKK, CurrencyCodeHUF,
+		CurrencyCodeILS, CurrencyCodeINR, CurrencyCodeKRW, CurrencyCodeMXN, CurrencyCodeNOK, CurrencyCodeNZD,
+		CurrencyCodePLN, CurrencyCodeRUB, CurrencyCodeTHB, CurrencyCodeTRY, CurrencyCodeTWD, CurrencyCodeUAH,
+		CurrencyCodeVND, CurrencyCodeZAR)
+}
+
+type unknownEnumsKey struct{}
+
+// WithUnknownEnums lets requests made with the returned context send
+// enumerated values unknown to this version of the library, e.g. ones added
+// in a newer API version. Values must still be well-formed. Without it such
+// values fail with ErrInvalidParams, so that typos are not sent to Paddle.
+func WithUnknownEnums(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unknownEnumsKey{}, true)
+}
+
+func unknownEnumsAllowed(ctx context.Context) bool {
+	allowed, _ := ctx.Value(unknownEnumsKey{}).(bool)
+	return allowed
+}
+
+// checkEnum returns an error if e is malformed or, unless allowUnknown is
+// set, not one of the values known to this version of the library.
+func checkEnum(field string, e Enum, allowUnknown bool) error {
+	switch {
+	case !e.IsValid():
+		return fmt.Errorf("%w: %s: invalid value %q", ErrInvalidParams, field, e)
+	case !allowUnknown && !e.IsKnown():
+		return fmt.Errorf("%w: %s: unknown value %q", ErrInvalidParams, field, e)
+	}
+	return nil
+}
+
+// validateEnum checks each value with checkEnum before it is sent to Paddle.
+// Empty values are ignored.
+func validateEnum[T interface {
+	~string
+	Enum
+}](allowUnknown bool, field string, values ...T) error {
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+		if err := checkEnum(field, v, allowUnknown); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UnknownEnumValue is an enumerated value received from Paddle which this
+// version of the library does not know about.
+type UnknownEnumValue struct {
+	Path  string
+	Type  string
+	Value string
+}
+
+var enumType = reflect.TypeOf((*Enum)(nil)).Elem()
+
+// UnknownEnumValues returns every enumerated value within v which is not
+// known to this version of the library. Such values are kept as received,
+// but may indicate the API has added states the caller does not handle.
+func UnknownEnumValues(v any) []UnknownEnumValue {
+	var unknown []UnknownEnumValue
+	walkEnums(reflect.ValueOf(v), "", &unknown)
+	return unknown
+}
+
+func walkEnums(v reflect.Value, path string, unknown *[]UnknownEnumValue) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			walkEnums(v.Elem(), path, unknown)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).IsExported() {
+				walkEnums(v.Field(i), path+"."+t.Field(i).Name, unknown)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			walkEnums(v.Index(i), path+"["+strconv.Itoa(i)+"]", unknown)
+		}
+	case reflect.String:
+		if !v.Type().Implements(enumType) || v.Len() == 0 {
+			return
+		}
+		if e := v.Interface().(Enum); !e.IsKnown() {
+			*unknown = append(*unknown, UnknownEnumValue{
+				Path:  strings.TrimPrefix(path, "."),
+				Type:  v.Type().Name(),
+				Value: v.String(),
+			})
+		}
+	}
+}
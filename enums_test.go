@@ -0,0 +1,76 @@
+package paddle
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestValidateParamsEnums(t *testing.T) {
+	immediatly := SubscriptionEffectFromOption("immediatly")
+	saasIsh := TaxCategory("saas-ish")
+	tests := []struct {
+		name        string
+		params      paramsValidator
+		wantStrict  bool
+		wantAllowed bool
+	}{
+		{"known", &UpdateSubscriptionParams{ProrationBillingMode: ProrationBillingModeProratedImmediately}, true, true},
+		{"typo", &UpdateSubscriptionParams{ProrationBillingMode: "prorated_immediatly"}, false, true},
+		{"typo in pointer", &UpdateProductParams{TaxCategory: &saasIsh}, false, true},
+		{"typo in nested", &UpdateSubscriptionParams{Discount: &UpdateSubscriptionDiscount{EffectiveFrom: immediatly}}, false, true},
+		{"cancel typo", &CancelSubscriptionParams{EffectiveFrom: immediatly}, false, true},
+		{"currency typo", &CreatePriceParams{UnitPrice: Money{CurrencyCode: "USS"}}, false, true},
+		{"malformed", &PauseSubscriptionParams{EffectiveFrom: "next billing period"}, false, false},
+		{"empty", &ResumeSubscriptionParams{}, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, allowUnknown := range []bool{false, true} {
+				want := tt.wantStrict
+				if allowUnknown {
+					want = tt.wantAllowed
+				}
+				err := tt.params.validate(allowUnknown)
+				if (err == nil) != want {
+					t.Errorf("validate(%v) = %v, want valid %v", allowUnknown, err, want)
+				}
+				if err != nil && !errors.Is(err, ErrInvalidParams) {
+					t.Errorf("validate(%v) = %v, want %v", allowUnknown, err, ErrInvalidParams)
+				}
+			}
+		})
+	}
+}
+
+func TestWithUnknownEnums(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"id":"sub_1","status":"canceled"},"meta":{"request_id":"req_1"}}`))
+	}))
+	t.Cleanup(srv.Close)
+	client := NewClient(&Config{BaseURL: srv.URL, APIKey: "key", HttpClient: srv.Client()})
+
+	params := &CancelSubscriptionParams{EffectiveFrom: "immediatly"}
+	if _, err := client.Subscriptions.Cancel(context.Background(), "sub_1", params); !errors.Is(err, ErrInvalidParams) {
+		t.Fatalf("Cancel() = %v, want %v", err, ErrInvalidParams)
+	}
+	if _, err := client.Subscriptions.List(context.Background(), &ListSubscriptionsParams{Status: []SubscriptionStatus{"actve"}}); !errors.Is(err, ErrInvalidParams) {
+		t.Fatalf("List() = %v, want %v", err, ErrInvalidParams)
+	}
+	if n := requests.Load(); n != 0 {
+		t.Fatalf("sent %d requests with unknown enum values, want 0", n)
+	}
+
+	if _, err := client.Subscriptions.Cancel(WithUnknownEnums(context.Background()), "sub_1", params); err != nil {
+		t.Fatalf("Cancel() with WithUnknownEnums = %v", err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("sent %d requests with WithUnknownEnums, want 1", n)
+	}
+}
@@ -0,0 +1,164 @@
+package paddle
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidParams is returned when request parameters fail client-side
+// validation, before any request is sent.
+var ErrInvalidParams = errors.New("invalid params")
+
+type ErrorType string
+
+const (
+	ErrorTypeRequest = ErrorType("request_error")
+	ErrorTypeApi     = ErrorType("api_error")
+)
+
+type ErrorCode string
+
+const (
+	ErrorCodeBadRequest              = ErrorCode("bad_request")
+	ErrorCodeInvalidField            = ErrorCode("invalid_field")
+	ErrorCodeInvalidJson             = ErrorCode("invalid_json")
+	ErrorCodeInvalidUrl              = ErrorCode("invalid_url")
+	ErrorCodeAuthenticationMissing   = ErrorCode("authentication_missing")
+	ErrorCodeAuthenticationMalformed = ErrorCode("authentication_malformed")
+	ErrorCodeInvalidToken            = ErrorCode("invalid_token")
+	ErrorCodeForbidden               = ErrorCode("forbidden")
+	ErrorCodeNotFound                = ErrorCode("not_found")
+	ErrorCodeMethodNotAllowed        = ErrorCode("method_not_allowed")
+	ErrorCodeNotAcceptable           = ErrorCode("not_acceptable")
+	ErrorCodeConflict                = ErrorCode("conflict")
+	ErrorCodeEntityArchived          = ErrorCode("entity_archived")
+	ErrorCodeRequestBodyTooLarge     = ErrorCode("request_body_too_large")
+	ErrorCodeUnsupportedMediaType    = ErrorCode("unsupported_media_type")
+	ErrorCodeTooManyRequests         = ErrorCode("too_many_requests")
+	ErrorCodeInternalError           = ErrorCode("internal_error")
+	ErrorCodeServiceUnavailable      = ErrorCode("service_unavailable")
+	ErrorCodePaddleVersionInvalid    = ErrorCode("paddle_version_invalid")
+)
+
+// Sentinel errors for use with errors.Is. An *ApiError matches a sentinel
+// when their codes are equal, e.g. errors.Is(err, ErrNotFound).
+var (
+	ErrBadRequest              = &ApiError{Code: ErrorCodeBadRequest}
+	ErrInvalidField            = &ApiError{Code: ErrorCodeInvalidField}
+	ErrInvalidJson             = &ApiError{Code: ErrorCodeInvalidJson}
+	ErrInvalidUrl              = &ApiError{Code: ErrorCodeInvalidUrl}
+	ErrAuthenticationMissing   = &ApiError{Code: ErrorCodeAuthenticationMissing}
+	ErrAuthenticationMalformed = &ApiError{Code: ErrorCodeAuthenticationMalformed}
+	ErrInvalidToken            = &ApiError{Code: ErrorCodeInvalidToken}
+	ErrForbidden               = &ApiError{Code: ErrorCodeForbidden}
+	ErrNotFound                = &ApiError{Code: ErrorCodeNotFound}
+	ErrMethodNotAllowed        = &ApiError{Code: ErrorCodeMethodNotAllowed}
+	ErrNotAcceptable           = &ApiError{Code: ErrorCodeNotAcceptable}
+	ErrConflict                = &ApiError{Code: ErrorCodeConflict}
+	ErrEntityArchived          = &ApiError{Code: ErrorCodeEntityArchived}
+	ErrRequestBodyTooLarge     = &ApiError{Code: ErrorCodeRequestBodyTooLarge}
+	ErrUnsupportedMediaType    = &ApiError{Code: ErrorCodeUnsupportedMediaType}
+	ErrTooManyRequests         = &ApiError{Code: ErrorCodeTooManyRequests}
+	ErrInternalError           = &ApiError{Code: ErrorCodeInternalError}
+	ErrServiceUnavailable      = &ApiError{Code: ErrorCodeServiceUnavailable}
+	ErrPaddleVersionInvalid    = &ApiError{Code: ErrorCodePaddleVersionInvalid}
+)
+
+type ApiFieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+type ApiError struct {
+	Type             ErrorType       `json:"type"`
+	Code             ErrorCode       `json:"code"`
+	Detail           string          `json:"detail"`
+	DocumentationUrl string          `json:"documentation_url"`
+	Errors           []ApiFieldError `json:"errors,omitempty"`
+
+	StatusCode int    `json:"-"`
+	RequestId  string `json:"-"`
+	Method     string `json:"-"`
+	Path       string `json:"-"`
+
+	// RetryAfter is how long Paddle asked the client to wait before retrying,
+	// from the Retry-After header, or zero when it was not sent.
+	RetryAfter time.Duration `json:"-"`
+}
+
+func newApiError(res *http.Response, apiErr *ApiError, requestId string) *ApiError {
+	if apiErr == nil {
+		apiErr = &ApiError{
+			Type:   ErrorTypeApi,
+			Detail: http.StatusText(res.StatusCode),
+		}
+	}
+	apiErr.StatusCode = res.StatusCode
+	apiErr.RequestId = requestId
+	if res.Request != nil {
+		apiErr.Method = res.Request.Method
+		apiErr.Path = res.Request.URL.Path
+	}
+	apiErr.RetryAfter = parseRetryAfter(res.Header.Get("Retry-After"), time.Now())
+	return apiErr
+}
+
+// parseRetryAfter parses a Retry-After header given either as a number of
+// seconds or as an HTTP date.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if seconds, convErr := strconv.Atoi(v); convErr == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if t, parseErr := http.ParseTime(v); parseErr == nil {
+		return max(t.Sub(now), 0)
+	}
+	return 0
+}
+
+func (e *ApiError) Error() string {
+	var sb strings.Builder
+	if e.Method != "" || e.Path != "" {
+		fmt.Fprintf(&sb, "[%v %v] ", e.Method, e.Path)
+	}
+	if e.StatusCode != 0 {
+		fmt.Fprintf(&sb, "HTTP %d ", e.StatusCode)
+	}
+	fmt.Fprintf(&sb, "'%s': %s", e.Code, e.Detail)
+	for _, fieldErr := range e.Errors {
+		fmt.Fprintf(&sb, "; %s: %s", fieldErr.Field, fieldErr.Message)
+	}
+	if e.RequestId != "" {
+		fmt.Fprintf(&sb, " (request_id %s)", e.RequestId)
+	}
+	return sb.String()
+}
+
+func (e *ApiError) Is(target error) bool {
+	t, ok := target.(*ApiError)
+	if !ok {
+		return false
+	}
+	if t.Code != "" {
+		return e.Code == t.Code
+	}
+	if t.Type != "" {
+		return e.Type == t.Type
+	}
+	return false
+}
+
+// FieldErrors returns the validation messages for each field Paddle rejected.
+func (e *ApiError) FieldErrors() map[string][]string {
+	fields := make(map[string][]string, len(e.Errors))
+	for _, fieldErr := range e.Errors {
+		fields[fieldErr.Field] = append(fields[fieldErr.Field], fieldErr.Message)
+	}
+	return fields
+}
@@ -0,0 +1,61 @@
+package paddle
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+)
+
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+type idempotencyKey struct{}
+
+// WithIdempotencyKey attaches an idempotency key to mutating requests made
+// with the returned context, so that a call may be safely retried.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+func IdempotencyKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyKey{}).(string)
+	return key, ok && key != ""
+}
+
+// NewIdempotencyKey returns a random version 4 UUID.
+func NewIdempotencyKey() (string, error) {
+	var b [16]byte
+	if _, randErr := rand.Read(b[:]); randErr != nil {
+		return "", randErr
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
+
+func (c *Client) setIdempotencyKey(ctx context.Context, req *http.Request) error {
+	if !isMutating(req.Method) {
+		return nil
+	}
+	key, ok := IdempotencyKeyFromContext(ctx)
+	if !ok {
+		if c.maxRetries == 0 {
+			return nil
+		}
+		var keyErr error
+		if key, keyErr = NewIdempotencyKey(); keyErr != nil {
+			return keyErr
+		}
+	}
+	req.Header.Set(IdempotencyKeyHeader, key)
+	return nil
+}
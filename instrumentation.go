@@ -0,0 +1,43 @@
+package paddle
+
+import "context"
+
+// Operation identifies the service method being called, e.g. the
+// "update" method of the "subscriptions" service.
+type Operation struct {
+	Service    string
+	Method     string
+	ResourceId string
+}
+
+func (o Operation) Name() string {
+	return "paddle." + o.Service + "." + o.Method
+}
+
+// Instrumentation observes each service method call. StartOperation is
+// called before any request is made and the returned function is called
+// with the method's result once it completes.
+type Instrumentation interface {
+	StartOperation(ctx context.Context, op Operation) (context.Context, func(err error))
+}
+
+type operationKey struct{}
+
+// OperationFromContext returns the service method being performed, which
+// is available to Middleware for every request made by that method.
+func OperationFromContext(ctx context.Context) (Operation, bool) {
+	op, ok := ctx.Value(operationKey{}).(Operation)
+	return op, ok
+}
+
+func operation(service string, method string, id string) Operation {
+	return Operation{Service: service, Method: method, ResourceId: id}
+}
+
+func (c *Client) startOperation(ctx context.Context, op Operation) (context.Context, func(err error)) {
+	ctx = context.WithValue(ctx, operationKey{}, op)
+	if c.instrumentation == nil {
+		return ctx, func(error) {}
+	}
+	return c.instrumentation.StartOperation(ctx, op)
+}
@@ -0,0 +1,646 @@
+// Command paddlegen generates models, enums, list params and service methods
+// from the vendored Paddle OpenAPI document.
+//
+//	go run ./internal/cmd/paddlegen -spec openapi/paddle.json -overlay openapi/overlay.json -out zz_generated.go
+//
+// The document is kept as published by Paddle. How it maps onto Go is
+// described by x-go extensions in a separate overlay, which paddlegen merges
+// into the document before generating; see overlayNode for its shape.
+//
+// Schemas may set x-go-type to map onto a type written by hand in package
+// paddle, e.g. Amount or CustomData, and properties may set x-go-name to
+// override the derived field name. Query parameters may set x-go-name and
+// x-go-tag to override the derived field name and query tag.
+//
+// Schemas used only in request bodies are params: their optional properties
+// are omitted when empty and are pointers unless x-go-pointer is false.
+//
+// Operations are named List, Get, Create or Update after their method and
+// response unless they set x-go-method. x-go-variants lists further methods
+// sharing an operation's path, e.g. to send a fixed request body. x-go-doc
+// documents the params type of a list operation.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+	"strings"
+)
+
+func main() {
+	specPath := flag.String("spec", "openapi/paddle.json", "path to the OpenAPI document")
+	overlayPath := flag.String("overlay", "openapi/overlay.json", "path of the overlay holding the x-go extensions")
+	outPath := flag.String("out", "zz_generated.go", "path of the generated Go file")
+	pkg := flag.String("package", "paddle", "package name of the generated file")
+	flag.Parse()
+
+	doc, loadErr := load(*specPath, *overlayPath)
+	if loadErr != nil {
+		log.Fatal(loadErr)
+	}
+
+	g := &generator{doc: doc, source: *specPath}
+	src, genErr := g.generate(*pkg)
+	if genErr != nil {
+		log.Fatal(genErr)
+	}
+	if writeErr := os.WriteFile(*outPath, src, 0o644); writeErr != nil {
+		log.Fatal(writeErr)
+	}
+}
+
+// load reads the OpenAPI document at specPath and applies the overlay at
+// overlayPath, if any.
+func load(specPath string, overlayPath string) (*document, error) {
+	data, readErr := os.ReadFile(specPath)
+	if readErr != nil {
+		return nil, readErr
+	}
+	var doc document
+	if jsonErr := json.Unmarshal(data, &doc); jsonErr != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", specPath, jsonErr)
+	}
+	if overlayPath == "" {
+		return &doc, nil
+	}
+	overlay, overlayErr := os.ReadFile(overlayPath)
+	if overlayErr != nil {
+		return nil, overlayErr
+	}
+	if applyErr := doc.applyOverlay(overlay); applyErr != nil {
+		return nil, fmt.Errorf("failed to apply %s: %w", overlayPath, applyErr)
+	}
+	return &doc, nil
+}
+
+type document struct {
+	Paths      map[string]map[string]*operation `json:"paths"`
+	Components struct {
+		Schemas map[string]*schema `json:"schemas"`
+	} `json:"components"`
+}
+
+type operation struct {
+	OperationId string              `json:"operationId"`
+	Tags        []string            `json:"tags"`
+	Summary     string              `json:"summary"`
+	Parameters  []*parameter        `json:"parameters"`
+	RequestBody *content            `json:"requestBody"`
+	Responses   map[string]*content `json:"responses"`
+	GoMethod    string              `json:"x-go-method"`
+	Variants    []*operation        `json:"x-go-variants"`
+	GoDoc       string              `json:"x-go-doc"`
+}
+
+type parameter struct {
+	Name   string  `json:"name"`
+	In     string  `json:"in"`
+	Schema *schema `json:"schema"`
+	GoName string  `json:"x-go-name"`
+	GoTag  string  `json:"x-go-tag"`
+}
+
+type content struct {
+	Content map[string]struct {
+		Schema *schema `json:"schema"`
+	} `json:"content"`
+}
+
+func (c *content) schema() *schema {
+	if c == nil {
+		return nil
+	}
+	return c.Content["application/json"].Schema
+}
+
+type schema struct {
+	Ref         string     `json:"$ref"`
+	Type        string     `json:"type"`
+	Format      string     `json:"format"`
+	Description string     `json:"description"`
+	Enum        []string   `json:"enum"`
+	Nullable    bool       `json:"nullable"`
+	Properties  properties `json:"properties"`
+	Items       *schema    `json:"items"`
+	AllOf       []*schema  `json:"allOf"`
+	Required    []string   `json:"required"`
+	GoType      string     `json:"x-go-type"`
+	GoName      string     `json:"x-go-name"`
+	GoPointer   *bool      `json:"x-go-pointer"`
+}
+
+type property struct {
+	name   string
+	schema *schema
+}
+
+// properties preserves the declaration order of an object's properties so
+// generated struct fields follow the document.
+type properties []property
+
+func (p *properties) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if _, tokErr := dec.Token(); tokErr != nil {
+		return tokErr
+	}
+	for dec.More() {
+		tok, tokErr := dec.Token()
+		if tokErr != nil {
+			return tokErr
+		}
+		var s schema
+		if decodeErr := dec.Decode(&s); decodeErr != nil {
+			return decodeErr
+		}
+		*p = append(*p, property{name: tok.(string), schema: &s})
+	}
+	return nil
+}
+
+func (p properties) get(name string) *schema {
+	for _, prop := range p {
+		if prop.name == name {
+			return prop.schema
+		}
+	}
+	return nil
+}
+
+type generator struct {
+	doc        *document
+	source     string
+	buf        bytes.Buffer
+	usesTime   bool
+	inputs     map[string]bool
+	signatures map[string][]string
+}
+
+func (g *generator) printf(format string, args ...any) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *generator) generate(pkg string) ([]byte, error) {
+	body := generator{doc: g.doc, inputs: g.doc.inputs(), signatures: map[string][]string{}}
+
+	for _, name := range sortedKeys(g.doc.Components.Schemas) {
+		s := g.doc.Components.Schemas[name]
+		if len(s.Enum) > 0 {
+			body.writeEnum(name, s)
+		} else {
+			body.writeStruct(name, s, body.inputs[name])
+		}
+	}
+	if svcErr := body.writeServices(); svcErr != nil {
+		return nil, svcErr
+	}
+
+	g.printf("// Code generated by paddlegen from %s. DO NOT EDIT.\n\n", g.source)
+	g.printf("package %s\n\n", pkg)
+	g.printf("import (\n\t\"context\"\n")
+	if body.usesTime {
+		g.printf("\t\"time\"\n")
+	}
+	g.printf(")\n\n")
+	g.buf.Write(body.buf.Bytes())
+
+	src, fmtErr := format.Source(g.buf.Bytes())
+	if fmtErr != nil {
+		return g.buf.Bytes(), fmt.Errorf("failed to format generated code: %w", fmtErr)
+	}
+	return src, nil
+}
+
+// operations calls fn for every operation and variant, ordered by path and
+// method.
+func (d *document) operations(fn func(path string, method string, op *operation)) {
+	for _, path := range sortedKeys(d.Paths) {
+		for _, method := range sortedKeys(d.Paths[path]) {
+			op := d.Paths[path][method]
+			fn(path, method, op)
+			for _, variant := range op.Variants {
+				fn(path, method, variant.inherit(op))
+			}
+		}
+	}
+}
+
+// inherit fills in the parts of a variant it does not override from op.
+func (v *operation) inherit(op *operation) *operation {
+	variant := *v
+	if variant.Tags == nil {
+		variant.Tags = op.Tags
+	}
+	if variant.Parameters == nil {
+		variant.Parameters = op.Parameters
+	}
+	if variant.RequestBody == nil {
+		variant.RequestBody = op.RequestBody
+	}
+	if variant.Responses == nil {
+		variant.Responses = op.Responses
+	}
+	return &variant
+}
+
+// inputs returns the schemas which are only used in request bodies.
+func (d *document) inputs() map[string]bool {
+	requests, responses := map[string]bool{}, map[string]bool{}
+	d.operations(func(_ string, _ string, op *operation) {
+		d.collectRefs(op.RequestBody.schema(), requests)
+		for _, res := range op.Responses {
+			d.collectRefs(res.schema(), responses)
+		}
+	})
+	for name := range responses {
+		delete(requests, name)
+	}
+	return requests
+}
+
+func (d *document) collectRefs(s *schema, refs map[string]bool) {
+	if s == nil {
+		return
+	}
+	if s.Ref != "" {
+		name := refName(s.Ref)
+		if refs[name] {
+			return
+		}
+		refs[name] = true
+		s = d.Components.Schemas[name]
+		if s == nil {
+			return
+		}
+	}
+	for _, prop := range s.Properties {
+		d.collectRefs(prop.schema, refs)
+	}
+	d.collectRefs(s.Items, refs)
+	for _, sub := range s.AllOf {
+		d.collectRefs(sub, refs)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func camel(s string) string {
+	parts := strings.FieldsFunc(s, func(r rune) bool {
+		return r == '_' || r == '-' || r == ' ' || r == '.'
+	})
+	for i, part := range parts {
+		parts[i] = strings.ToUpper(part[:1]) + part[1:]
+	}
+	return strings.Join(parts, "")
+}
+
+func lowerCamel(s string) string {
+	c := camel(s)
+	return strings.ToLower(c[:1]) + c[1:]
+}
+
+func snake(s string) string {
+	var sb strings.Builder
+	for i, r := range s {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				sb.WriteByte('_')
+			}
+			r += 'a' - 'A'
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+func writeComment(buf *bytes.Buffer, text string) {
+	if text == "" {
+		return
+	}
+	for _, line := range strings.Split(text, "\n") {
+		fmt.Fprintf(buf, "// %s\n", line)
+	}
+}
+
+func (g *generator) goType(s *schema) string {
+	nullable := s.Nullable
+	if len(s.AllOf) == 1 {
+		s = s.AllOf[0]
+	}
+
+	var t string
+	pointable := true
+	switch {
+	case s.GoType != "":
+		t = s.GoType
+		pointable = s.GoType != "CustomData"
+	case s.Ref != "":
+		t = refName(s.Ref)
+	case s.Type == "array":
+		t = "[]" + g.goType(s.Items)
+		pointable = false
+	case s.Type == "string" && s.Format == "date-time":
+		g.usesTime = true
+		t = "time.Time"
+	case s.Type == "string":
+		t = "string"
+	case s.Type == "integer":
+		t = "int"
+	case s.Type == "number":
+		t = "float64"
+	case s.Type == "boolean":
+		t = "bool"
+	default:
+		t = "any"
+		pointable = false
+	}
+	if nullable && pointable {
+		return "*" + t
+	}
+	return t
+}
+
+func (g *generator) writeEnum(name string, s *schema) {
+	writeComment(&g.buf, s.Description)
+	g.printf("type %s string\n\nconst (\n", name)
+	consts := make([]string, len(s.Enum))
+	for i, v := range s.Enum {
+		consts[i] = name + camel(v)
+		g.printf("\t%s = %s(%q)\n", consts[i], name, v)
+	}
+	g.printf(")\n\n")
+	g.printf("func (v %s) IsValid() bool { return isValidEnum(v) }\n", name)
+	g.printf("func (v %s) IsKnown() bool {\n\treturn isKnownEnum(v, %s)\n}\n\n", name, strings.Join(consts, ", "))
+}
+
+func (g *generator) writeStruct(name string, s *schema, input bool) {
+	writeComment(&g.buf, s.Description)
+	g.printf("type %s struct {\n", name)
+	g.writeFields(s, input)
+	g.printf("}\n\n")
+}
+
+func (g *generator) writeFields(s *schema, input bool) {
+	for _, prop := range s.Properties {
+		fieldName := prop.schema.GoName
+		if fieldName == "" {
+			fieldName = camel(prop.name)
+		}
+		t, tag := g.goType(prop.schema), prop.name
+		if input && !contains(s.Required, prop.name) {
+			tag += ",omitempty"
+			required := *prop.schema
+			required.Nullable = false
+			t = g.goType(&required)
+			if prop.schema.GoPointer == nil || *prop.schema.GoPointer {
+				t = "*" + t
+			}
+		}
+		g.printf("\t%s %s `json:%q`\n", fieldName, t, tag)
+	}
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+type serviceMethod struct {
+	path   string
+	method string
+	op     *operation
+}
+
+func (g *generator) writeServices() error {
+	services := map[string][]serviceMethod{}
+	g.doc.operations(func(path string, method string, op *operation) {
+		if len(op.Tags) > 0 {
+			services[op.Tags[0]] = append(services[op.Tags[0]], serviceMethod{path: path, method: method, op: op})
+		}
+	})
+
+	tags := sortedKeys(services)
+	for _, tag := range tags {
+		g.printf("type %sService service\n\n", tag)
+		for _, m := range services[tag] {
+			if methodErr := g.writeMethod(tag, m.path, m.method, m.op); methodErr != nil {
+				return methodErr
+			}
+		}
+	}
+
+	for _, tag := range tags {
+		g.printf("type %sAPI interface {\n", tag)
+		for _, sig := range g.signatures[tag] {
+			g.printf("\t%s\n", sig)
+		}
+		g.printf("}\n\n")
+		g.printf("var _ %sAPI = (*%sService)(nil)\n\n", tag, tag)
+	}
+
+	g.printf("type generatedServices struct {\n")
+	for _, tag := range tags {
+		g.printf("\t%s *%sService\n", tag, tag)
+	}
+	g.printf("}\n\n")
+	g.printf("func (gs *generatedServices) init(s *service) {\n")
+	for _, tag := range tags {
+		g.printf("\tgs.%s = (*%sService)(s)\n", tag, tag)
+	}
+	g.printf("}\n\n")
+
+	g.printf("type generatedAPI interface {\n")
+	for _, tag := range tags {
+		g.printf("\t%sAPI() %sAPI\n", tag, tag)
+	}
+	g.printf("}\n\n")
+	for _, tag := range tags {
+		g.printf("func (gs *generatedServices) %sAPI() %sAPI { return gs.%s }\n", tag, tag, tag)
+	}
+	return nil
+}
+
+func (g *generator) writeMethod(tag string, path string, method string, op *operation) error {
+	res := op.Responses["200"]
+	if res == nil {
+		res = op.Responses["201"]
+	}
+	if res.schema() == nil {
+		return fmt.Errorf("%s: no 200 or 201 response", op.OperationId)
+	}
+	data := res.schema().Properties.get("data")
+	if data == nil {
+		return fmt.Errorf("%s: response has no data property", op.OperationId)
+	}
+	list := method == "get" && data.Type == "array"
+
+	name := op.GoMethod
+	if name == "" {
+		switch {
+		case list:
+			name = "List"
+		case method == "get":
+			name = "Get"
+		case method == "post":
+			name = "Create"
+		case method == "patch":
+			name = "Update"
+		default:
+			return fmt.Errorf("%s: x-go-method is required for %s", op.OperationId, method)
+		}
+	}
+
+	var pathParams, queryParams []*parameter
+	for _, p := range op.Parameters {
+		switch p.In {
+		case "path":
+			pathParams = append(pathParams, p)
+		case "query":
+			queryParams = append(queryParams, p)
+		}
+	}
+
+	argNames := map[string]string{}
+	var args []string
+	idArg := `""`
+	for i, p := range pathParams {
+		argName := lowerCamel(p.Name)
+		if i == len(pathParams)-1 && !list {
+			argName = "id"
+			idArg = "id"
+		}
+		argNames[p.Name] = argName
+		args = append(args, argName+" string")
+	}
+
+	var endpoint []string
+	literal := ""
+	for _, seg := range strings.Split(strings.TrimPrefix(path, "/"), "/") {
+		if strings.HasPrefix(seg, "{") {
+			name := strings.Trim(seg, "{}")
+			endpoint = append(endpoint, fmt.Sprintf("%q", literal), argNames[name])
+			literal = "/"
+			continue
+		}
+		literal += seg + "/"
+	}
+	if literal = strings.TrimSuffix(literal, "/"); literal != "" {
+		endpoint = append(endpoint, fmt.Sprintf("%q", literal))
+	}
+	endpointExpr := strings.Join(endpoint, " + ")
+
+	serviceName := strings.ToLower(tag)
+	opExpr := fmt.Sprintf("operation(%q, %q, %s)", serviceName, snake(name), idArg)
+	writeComment(&g.buf, op.Summary+".")
+
+	if list {
+		itemType := g.goType(data.Items)
+		paramsType := "List" + tag + "Params"
+		args = append(args, "params *"+paramsType)
+		sig := fmt.Sprintf("%s(ctx context.Context, %s) ([]*%s, error)", name, strings.Join(args, ", "), itemType)
+		g.signatures[tag] = append(g.signatures[tag], sig)
+		g.printf("func (s *%sService) %s {\n", tag, sig)
+		g.printf("\tendpoint, queryErr := withQuery(ctx, %s, params)\n", endpointExpr)
+		g.printf("\tif queryErr != nil {\n\t\treturn nil, queryErr\n\t}\n")
+		g.printf("\treturn listItems[%s](ctx, s.client, %s, endpoint)\n}\n\n", itemType, opExpr)
+		writeComment(&g.buf, op.GoDoc)
+		g.writeQueryParams(paramsType, queryParams)
+		return nil
+	}
+
+	itemType := g.goType(data)
+	queryType := ""
+	var queryFields []string
+	if len(queryParams) > 0 {
+		queryType = lowerCamel(name + itemType + "Params")
+		for _, p := range queryParams {
+			fieldName := queryFieldName(p)
+			args = append(args, lowerCamel(fieldName)+" "+g.goType(p.Schema))
+			queryFields = append(queryFields, fieldName+": "+lowerCamel(fieldName))
+		}
+	}
+
+	body := "nil"
+	var bodyDecl string
+	if reqSchema := op.RequestBody.schema(); reqSchema != nil {
+		if reqSchema.Ref != "" {
+			args = append(args, "params *"+refName(reqSchema.Ref))
+			body = "params"
+		} else {
+			var fields bytes.Buffer
+			fieldGen := generator{doc: g.doc}
+			fieldGen.writeFields(reqSchema, true)
+			fields.Write(fieldGen.buf.Bytes())
+			g.usesTime = g.usesTime || fieldGen.usesTime
+			bodyDecl = fmt.Sprintf("\tbody := struct {\n%s\t}{}\n", fields.String())
+			body = "body"
+		}
+	}
+
+	sig := fmt.Sprintf("%s(ctx context.Context, %s) (*%s, error)", name, strings.Join(args, ", "), itemType)
+	g.signatures[tag] = append(g.signatures[tag], sig)
+	g.printf("func (s *%sService) %s {\n", tag, sig)
+	g.printf("%s", bodyDecl)
+	if queryType != "" {
+		g.printf("\tendpoint, queryErr := withQuery(ctx, %s, &%s{%s})\n", endpointExpr, queryType, strings.Join(queryFields, ", "))
+		g.printf("\tif queryErr != nil {\n\t\treturn nil, queryErr\n\t}\n")
+		endpointExpr = "endpoint"
+	}
+	switch method {
+	case "get":
+		g.printf("\treturn getItem[%s](ctx, s.client, %s, %s)\n}\n\n", itemType, opExpr, endpointExpr)
+	case "post":
+		g.printf("\treturn postItem[%s](ctx, s.client, %s, %s, %s)\n}\n\n", itemType, opExpr, endpointExpr, body)
+	case "patch":
+		g.printf("\treturn patchItem[%s](ctx, s.client, %s, %s, %s)\n}\n\n", itemType, opExpr, endpointExpr, body)
+	default:
+		return fmt.Errorf("%s: unsupported method %s", op.OperationId, method)
+	}
+	if queryType != "" {
+		g.writeQueryParams(queryType, queryParams)
+	}
+	return nil
+}
+
+func queryFieldName(p *parameter) string {
+	if p.GoName != "" {
+		return p.GoName
+	}
+	fieldName := camel(p.Name)
+	if p.Schema.Type == "array" && (p.Name == "id" || strings.HasSuffix(p.Name, "_id")) {
+		fieldName += "s"
+	}
+	return fieldName
+}
+
+func (g *generator) writeQueryParams(name string, params []*parameter) {
+	g.printf("type %s struct {\n", name)
+	for _, p := range params {
+		tag := p.GoTag
+		if tag == "" {
+			tag = p.Name
+		}
+		g.printf("\t%s %s `query:%q`\n", queryFieldName(p), g.goType(p.Schema), tag)
+	}
+	g.printf("}\n\n")
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSpec = `{
+  "paths": {
+    "/widgets": {
+      "get": {
+        "operationId": "list-widgets",
+        "tags": ["Widgets"],
+        "summary": "List widgets",
+        "parameters": [
+          {"name": "id", "in": "query", "schema": {"type": "array", "items": {"type": "string"}}},
+          {"name": "status", "in": "query", "schema": {"type": "array", "items": {"$ref": "#/components/schemas/WidgetStatus"}}},
+          {"name": "include", "in": "query", "schema": {"type": "string"}},
+          {"name": "after", "in": "query", "schema": {"type": "string"}},
+          {"name": "per_page", "in": "query", "schema": {"type": "integer"}}
+        ],
+        "responses": {"200": {"content": {"application/json": {"schema": {
+          "type": "object",
+          "properties": {"data": {"type": "array", "items": {"$ref": "#/components/schemas/Widget"}}}
+        }}}}}
+      },
+      "post": {
+        "operationId": "create-widget",
+        "tags": ["Widgets"],
+        "summary": "Create a widget",
+        "requestBody": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/CreateWidgetParams"}}}},
+        "responses": {"201": {"content": {"application/json": {"schema": {
+          "type": "object",
+          "properties": {"data": {"$ref": "#/components/schemas/Widget"}}
+        }}}}}
+      }
+    },
+    "/widgets/{widget_id}/archive": {
+      "post": {
+        "operationId": "archive-widget",
+        "tags": ["Widgets"],
+        "summary": "Archive a widget",
+        "parameters": [{"name": "widget_id", "in": "path", "schema": {"type": "string"}}],
+        "responses": {"200": {"content": {"application/json": {"schema": {
+          "type": "object",
+          "properties": {"data": {"$ref": "#/components/schemas/Widget"}}
+        }}}}}
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "WidgetStatus": {"type": "string", "description": "Status of a widget.", "enum": ["active", "archived"]},
+      "Widget": {
+        "type": "object",
+        "properties": {
+          "id": {"type": "string"},
+          "status": {"$ref": "#/components/schemas/WidgetStatus"},
+          "price": {"type": "string"},
+          "custom_data": {"type": "object", "nullable": true},
+          "created_at": {"type": "string", "format": "date-time"}
+        }
+      },
+      "CreateWidgetParams": {
+        "type": "object",
+        "required": ["name"],
+        "properties": {
+          "name": {"type": "string"},
+          "description": {"type": "string"},
+          "tags": {"type": "array", "items": {"type": "string"}},
+          "custom_data": {"type": "object", "nullable": true}
+        }
+      }
+    }
+  }
+}`
+
+const testOverlay = `{
+  "paths": {
+    "/widgets": {"get": {
+      "x-go-doc": "ListWidgetsParams filters Widgets.List.",
+      "parameters": {"include": {"x-go-name": "IncludeParts", "x-go-tag": "include,value=parts"}}
+    }},
+    "/widgets/{widget_id}/archive": {"post": {"x-go-method": "Archive"}}
+  },
+  "components": {"schemas": {
+    "Widget": {"properties": {"price": {"x-go-type": "Amount"}, "custom_data": {"x-go-type": "CustomData"}}},
+    "CreateWidgetParams": {"properties": {"tags": {"x-go-pointer": false}, "custom_data": {"x-go-type": "CustomData"}}}
+  }}
+}`
+
+func parseTestSpec(t *testing.T) *document {
+	t.Helper()
+	var doc document
+	if err := json.Unmarshal([]byte(testSpec), &doc); err != nil {
+		t.Fatal(err)
+	}
+	return &doc
+}
+
+func TestGenerate(t *testing.T) {
+	doc := parseTestSpec(t)
+	if err := doc.applyOverlay([]byte(testOverlay)); err != nil {
+		t.Fatal(err)
+	}
+	src, err := (&generator{doc: doc, source: "widgets.json"}).generate("widgets")
+	if err != nil {
+		t.Fatalf("generate() = %v\n%s", err, src)
+	}
+	// Compare ignoring gofmt's alignment.
+	normalise := func(s string) string { return strings.Join(strings.Fields(s), " ") }
+	got := normalise(string(src))
+
+	for _, want := range []string{
+		"// Code generated by paddlegen from widgets.json. DO NOT EDIT.",
+		"\t\"time\"\n",
+		"// Status of a widget.\ntype WidgetStatus string",
+		`WidgetStatusArchived = WidgetStatus("archived")`,
+		"return isKnownEnum(v, WidgetStatusActive, WidgetStatusArchived)",
+		"Price      Amount     `json:\"price\"`",
+		"CustomData CustomData `json:\"custom_data\"`",
+		"CreatedAt  time.Time  `json:\"created_at\"`",
+		"Name        string      `json:\"name\"`",
+		"Description *string     `json:\"description,omitempty\"`",
+		"Tags        []string    `json:\"tags,omitempty\"`",
+		"CustomData  *CustomData `json:\"custom_data,omitempty\"`",
+		"func (s *WidgetsService) List(ctx context.Context, params *ListWidgetsParams) ([]*Widget, error) {",
+		"// ListWidgetsParams filters Widgets.List.\ntype ListWidgetsParams struct {",
+		"Ids          []string       `query:\"id\"`",
+		"Status       []WidgetStatus `query:\"status\"`",
+		"IncludeParts string         `query:\"include,value=parts\"`",
+		"After        string         `query:\"after\"`",
+		"PerPage      int            `query:\"per_page\"`",
+		"func (s *WidgetsService) Create(ctx context.Context, params *CreateWidgetParams) (*Widget, error) {",
+		`return postItem[Widget](ctx, s.client, operation("widgets", "create", ""), "widgets", params)`,
+		"// Archive a widget.\nfunc (s *WidgetsService) Archive(ctx context.Context, id string) (*Widget, error) {",
+		`return postItem[Widget](ctx, s.client, operation("widgets", "archive", id), "widgets/"+id+"/archive", nil)`,
+		"Archive(ctx context.Context, id string) (*Widget, error)\n}",
+		"var _ WidgetsAPI = (*WidgetsService)(nil)",
+	} {
+		if !strings.Contains(got, normalise(want)) {
+			t.Errorf("generate() is missing %q", want)
+		}
+	}
+	if t.Failed() {
+		t.Logf("generated:\n%s", src)
+	}
+}
+
+func TestApplyOverlayErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		overlay string
+		wantErr string
+	}{
+		{"unknown operation", `{"paths": {"/gadgets": {"get": {"x-go-method": "All"}}}}`, "get /gadgets: no such operation"},
+		{"unknown method", `{"paths": {"/widgets": {"delete": {"x-go-method": "Delete"}}}}`, "delete /widgets: no such operation"},
+		{"unknown parameter", `{"paths": {"/widgets": {"get": {"parameters": {"code": {"x-go-name": "Codes"}}}}}}`, "get /widgets: no parameter code"},
+		{"unknown schema", `{"components": {"schemas": {"Gadget": {"x-go-type": "Amount"}}}}`, "schema Gadget: no such schema"},
+		{"unknown property", `{"components": {"schemas": {"Widget": {"properties": {"cost": {"x-go-type": "Amount"}}}}}}`, "schema Widget: no property cost"},
+		{"unknown extension", `{"components": {"schemas": {"Widget": {"x-go-colour": "blue"}}}}`, `schema Widget: unsupported extension "x-go-colour"`},
+		{"extension on the wrong target", `{"paths": {"/widgets": {"get": {"x-go-type": "Amount"}}}}`, `get /widgets: unsupported extension "x-go-type"`},
+		{"not an extension", `{"components": {"schemas": {"Widget": {"nullable": true}}}}`, `schema Widget: unsupported overlay key "nullable"`},
+		{"unknown top-level key", `{"info": {}}`, `unsupported overlay key "info"`},
+		{"wrong extension type", `{"components": {"schemas": {"Widget": {"x-go-pointer": "no"}}}}`, "schema Widget: json: cannot unmarshal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := parseTestSpec(t).applyOverlay([]byte(tt.overlay))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("applyOverlay() = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestGeneratedUpToDate checks that zz_generated.go matches the vendored
+// document and overlay; run go generate in the repository root if it fails.
+func TestGeneratedUpToDate(t *testing.T) {
+	root := filepath.Join("..", "..", "..")
+	doc, loadErr := load(filepath.Join(root, "openapi", "paddle.json"), filepath.Join(root, "openapi", "overlay.json"))
+	if loadErr != nil {
+		t.Fatal(loadErr)
+	}
+	src, genErr := (&generator{doc: doc, source: "openapi/paddle.json"}).generate("paddle")
+	if genErr != nil {
+		t.Fatal(genErr)
+	}
+	committed, readErr := os.ReadFile(filepath.Join(root, "zz_generated.go"))
+	if readErr != nil {
+		t.Fatal(readErr)
+	}
+	if string(src) != string(committed) {
+		t.Error("zz_generated.go is out of date with openapi/paddle.json and openapi/overlay.json")
+	}
+}
+
+func TestNames(t *testing.T) {
+	tests := []struct {
+		in         string
+		camel      string
+		lowerCamel string
+		snake      string
+	}{
+		{"customer_id", "CustomerId", "customerId", "customer_id"},
+		{"update-payment-method-transaction", "UpdatePaymentMethodTransaction", "updatePaymentMethodTransaction", "update-payment-method-transaction"},
+		{"PreviewUpdate", "PreviewUpdate", "previewUpdate", "preview_update"},
+	}
+	for _, tt := range tests {
+		if got := camel(tt.in); got != tt.camel {
+			t.Errorf("camel(%q) = %q, want %q", tt.in, got, tt.camel)
+		}
+		if got := lowerCamel(tt.in); got != tt.lowerCamel {
+			t.Errorf("lowerCamel(%q) = %q, want %q", tt.in, got, tt.lowerCamel)
+		}
+		if got := snake(tt.in); got != tt.snake {
+			t.Errorf("snake(%q) = %q, want %q", tt.in, got, tt.snake)
+		}
+	}
+
+	params := []struct {
+		p    *parameter
+		want string
+	}{
+		{&parameter{Name: "customer_id", Schema: &schema{Type: "array"}}, "CustomerIds"},
+		{&parameter{Name: "customer_id", Schema: &schema{Type: "string"}}, "CustomerId"},
+		{&parameter{Name: "per_page", Schema: &schema{Type: "integer"}}, "PerPage"},
+		{&parameter{Name: "include", GoName: "IncludePrices", Schema: &schema{Type: "string"}}, "IncludePrices"},
+	}
+	for _, tt := range params {
+		if got := queryFieldName(tt.p); got != tt.want {
+			t.Errorf("queryFieldName(%s) = %q, want %q", tt.p.Name, got, tt.want)
+		}
+	}
+}
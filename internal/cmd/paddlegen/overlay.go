@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// An overlay has the shape of the parts of the OpenAPI document it
+// annotates, except that parameters are keyed by name:
+//
+//	{
+//	  "paths": {"/prices": {"get": {"parameters": {"include": {"x-go-name": "IncludeProduct"}}}}},
+//	  "components": {"schemas": {"Price": {"properties": {"custom_data": {"x-go-type": "CustomData"}}}}}
+//	}
+//
+// Only x-go extensions may be set, and every annotated path, operation,
+// parameter, schema and property must exist in the document, so that
+// updating the vendored document cannot silently drop a mapping.
+type overlayNode map[string]json.RawMessage
+
+// applyOverlay merges the x-go extensions in data into the document.
+func (d *document) applyOverlay(data []byte) error {
+	var root overlayNode
+	if jsonErr := json.Unmarshal(data, &root); jsonErr != nil {
+		return jsonErr
+	}
+	for _, key := range sortedKeys(root) {
+		switch key {
+		case "paths":
+			var paths map[string]map[string]json.RawMessage
+			if jsonErr := json.Unmarshal(root[key], &paths); jsonErr != nil {
+				return fmt.Errorf("paths: %w", jsonErr)
+			}
+			for _, path := range sortedKeys(paths) {
+				for _, method := range sortedKeys(paths[path]) {
+					at := method + " " + path
+					op := d.Paths[path][method]
+					if op == nil {
+						return fmt.Errorf("%s: no such operation", at)
+					}
+					if opErr := op.applyOverlay(paths[path][method], at); opErr != nil {
+						return opErr
+					}
+				}
+			}
+		case "components":
+			var components struct {
+				Schemas map[string]json.RawMessage `json:"schemas"`
+			}
+			if jsonErr := strictUnmarshal(root[key], &components); jsonErr != nil {
+				return fmt.Errorf("components: %w", jsonErr)
+			}
+			for _, name := range sortedKeys(components.Schemas) {
+				s := d.Components.Schemas[name]
+				if s == nil {
+					return fmt.Errorf("schema %s: no such schema", name)
+				}
+				if schemaErr := s.applyOverlay(components.Schemas[name], "schema "+name); schemaErr != nil {
+					return schemaErr
+				}
+			}
+		default:
+			return fmt.Errorf("unsupported overlay key %q", key)
+		}
+	}
+	return nil
+}
+
+func (op *operation) applyOverlay(data json.RawMessage, at string) error {
+	nested, extErr := mergeExtensions(op, data, at)
+	if extErr != nil {
+		return extErr
+	}
+	for _, key := range sortedKeys(nested) {
+		if key != "parameters" {
+			return fmt.Errorf("%s: unsupported overlay key %q", at, key)
+		}
+		var params map[string]json.RawMessage
+		if jsonErr := json.Unmarshal(nested[key], &params); jsonErr != nil {
+			return fmt.Errorf("%s: parameters: %w", at, jsonErr)
+		}
+		for _, name := range sortedKeys(params) {
+			p := op.parameter(name)
+			if p == nil {
+				return fmt.Errorf("%s: no parameter %s", at, name)
+			}
+			if paramErr := p.applyOverlay(params[name], at+" parameter "+name); paramErr != nil {
+				return paramErr
+			}
+		}
+	}
+	return nil
+}
+
+func (op *operation) parameter(name string) *parameter {
+	for _, p := range op.Parameters {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+func (p *parameter) applyOverlay(data json.RawMessage, at string) error {
+	nested, extErr := mergeExtensions(p, data, at)
+	if extErr != nil {
+		return extErr
+	}
+	for _, key := range sortedKeys(nested) {
+		if key != "schema" || p.Schema == nil {
+			return fmt.Errorf("%s: unsupported overlay key %q", at, key)
+		}
+		if schemaErr := p.Schema.applyOverlay(nested[key], at); schemaErr != nil {
+			return schemaErr
+		}
+	}
+	return nil
+}
+
+func (s *schema) applyOverlay(data json.RawMessage, at string) error {
+	nested, extErr := mergeExtensions(s, data, at)
+	if extErr != nil {
+		return extErr
+	}
+	for _, key := range sortedKeys(nested) {
+		switch {
+		case key == "properties":
+			var props map[string]json.RawMessage
+			if jsonErr := json.Unmarshal(nested[key], &props); jsonErr != nil {
+				return fmt.Errorf("%s: properties: %w", at, jsonErr)
+			}
+			for _, name := range sortedKeys(props) {
+				prop := s.Properties.get(name)
+				if prop == nil {
+					return fmt.Errorf("%s: no property %s", at, name)
+				}
+				if propErr := prop.applyOverlay(props[name], at+"."+name); propErr != nil {
+					return propErr
+				}
+			}
+		case key == "items" && s.Items != nil:
+			if itemsErr := s.Items.applyOverlay(nested[key], at+"[]"); itemsErr != nil {
+				return itemsErr
+			}
+		default:
+			return fmt.Errorf("%s: unsupported overlay key %q", at, key)
+		}
+	}
+	return nil
+}
+
+// mergeExtensions decodes the x-go extensions in data into target and
+// returns the remaining keys. Extensions target does not declare are
+// rejected.
+func mergeExtensions(target any, data json.RawMessage, at string) (overlayNode, error) {
+	var node overlayNode
+	if jsonErr := json.Unmarshal(data, &node); jsonErr != nil {
+		return nil, fmt.Errorf("%s: %w", at, jsonErr)
+	}
+	ext, nested := overlayNode{}, overlayNode{}
+	declared := extensions(target)
+	for key, value := range node {
+		switch {
+		case declared[key]:
+			ext[key] = value
+		case strings.HasPrefix(key, "x-go-"):
+			return nil, fmt.Errorf("%s: unsupported extension %q", at, key)
+		default:
+			nested[key] = value
+		}
+	}
+	if len(ext) == 0 {
+		return nested, nil
+	}
+	extData, _ := json.Marshal(ext)
+	if jsonErr := json.Unmarshal(extData, target); jsonErr != nil {
+		return nil, fmt.Errorf("%s: %w", at, jsonErr)
+	}
+	return nested, nil
+}
+
+// extensions returns the x-go extensions declared by the struct target
+// points to.
+func extensions(target any) map[string]bool {
+	declared := map[string]bool{}
+	t := reflect.TypeOf(target).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if strings.HasPrefix(name, "x-go-") {
+			declared[name] = true
+		}
+	}
+	return declared
+}
+
+func strictUnmarshal(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
@@ -0,0 +1,209 @@
+package paddle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type SubscriptionAction string
+
+const (
+	SubscriptionActionPause                 = SubscriptionAction("pause")
+	SubscriptionActionResume                = SubscriptionAction("resume")
+	SubscriptionActionCancel                = SubscriptionAction("cancel")
+	SubscriptionActionUpdateItems           = SubscriptionAction("update_items")
+	SubscriptionActionRemoveScheduledChange = SubscriptionAction("remove_scheduled_change")
+)
+
+var subscriptionActions = []SubscriptionAction{
+	SubscriptionActionPause,
+	SubscriptionActionResume,
+	SubscriptionActionCancel,
+	SubscriptionActionUpdateItems,
+	SubscriptionActionRemoveScheduledChange,
+}
+
+// ErrInvalidTransition is returned when an action does not apply to a
+// subscription in its current state.
+var ErrInvalidTransition = errors.New("invalid subscription transition")
+
+// SubscriptionTransition describes the outcome of an action on a
+// subscription.
+type SubscriptionTransition struct {
+	Action        SubscriptionAction
+	EffectiveFrom SubscriptionEffectFromOption
+	// Status is the subscription's status once the request succeeds. Actions
+	// taking effect at the next billing period leave it unchanged and set
+	// ScheduledChange instead.
+	Status          SubscriptionStatus
+	ScheduledChange *SubscriptionScheduledChangeAction
+}
+
+// AllowedActions returns the actions which apply to the subscription in its
+// current state.
+func (s *Subscription) AllowedActions() []SubscriptionAction {
+	var allowed []SubscriptionAction
+	for _, action := range subscriptionActions {
+		for _, from := range []SubscriptionEffectFromOption{SubscriptionEffectFromOptionImmediately, SubscriptionEffectFromOptionNextBillingPeriod} {
+			if _, err := s.Transition(action, from); err == nil {
+				allowed = append(allowed, action)
+				break
+			}
+		}
+	}
+	return allowed
+}
+
+// Transition reports the result of performing the action, or an error
+// wrapping ErrInvalidTransition which explains why it is not allowed.
+// effectiveFrom applies to pause, resume and cancel; when empty, Paddle's
+// default is assumed: immediately for resume, the next billing period
+// otherwise.
+func (s *Subscription) Transition(action SubscriptionAction, effectiveFrom SubscriptionEffectFromOption) (*SubscriptionTransition, error) {
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w: subscription %s: cannot %s: %s", ErrInvalidTransition,
+			s.Id, strings.ReplaceAll(string(action), "_", " "), fmt.Sprintf(format, args...))
+	}
+	if s.Status == SubscriptionStatusCanceled {
+		return nil, invalid("it is canceled")
+	}
+
+	t := &SubscriptionTransition{Action: action, Status: s.Status}
+	switch action {
+	case SubscriptionActionPause, SubscriptionActionCancel:
+		t.EffectiveFrom = effectiveFrom
+		if t.EffectiveFrom == "" {
+			t.EffectiveFrom = SubscriptionEffectFromOptionNextBillingPeriod
+		}
+	case SubscriptionActionResume:
+		t.EffectiveFrom = effectiveFrom
+		if t.EffectiveFrom == "" {
+			t.EffectiveFrom = SubscriptionEffectFromOptionImmediately
+		}
+	}
+	if t.EffectiveFrom != "" && !t.EffectiveFrom.IsKnown() {
+		return nil, invalid("unknown effective_from %q", t.EffectiveFrom)
+	}
+	scheduled := t.EffectiveFrom == SubscriptionEffectFromOptionNextBillingPeriod
+
+	switch action {
+	case SubscriptionActionPause:
+		if s.Status != SubscriptionStatusActive {
+			return nil, invalid("only active subscriptions can be paused, it is %s", s.Status)
+		}
+		if s.ScheduledChange != nil {
+			return nil, invalid("it has a scheduled %s; remove it first", s.ScheduledChange.Action)
+		}
+		if scheduled {
+			t.ScheduledChange = scheduledChange(SubscriptionScheduledChangeActionPause)
+		} else {
+			t.Status = SubscriptionStatusPaused
+		}
+
+	case SubscriptionActionResume:
+		if s.Status != SubscriptionStatusPaused {
+			return nil, invalid("only paused subscriptions can be resumed, it is %s", s.Status)
+		}
+		if scheduled {
+			return nil, invalid("a paused subscription has no billing period; resume it immediately")
+		}
+		t.Status = SubscriptionStatusActive
+
+	case SubscriptionActionCancel:
+		if scheduled {
+			if s.Status == SubscriptionStatusPaused || s.Status == SubscriptionStatusPastDue {
+				return nil, invalid("it is %s, so it can only be canceled immediately", s.Status)
+			}
+			if s.ScheduledChange != nil {
+				return nil, invalid("it already has a scheduled %s", s.ScheduledChange.Action)
+			}
+			t.ScheduledChange = scheduledChange(SubscriptionScheduledChangeActionCancel)
+		} else {
+			t.Status = SubscriptionStatusCanceled
+		}
+
+	case SubscriptionActionUpdateItems:
+		if s.Status != SubscriptionStatusActive && s.Status != SubscriptionStatusTrialing {
+			return nil, invalid("only active or trialing subscriptions can change items, it is %s", s.Status)
+		}
+
+	case SubscriptionActionRemoveScheduledChange:
+		if s.ScheduledChange == nil {
+			return nil, invalid("it has no scheduled change")
+		}
+
+	default:
+		return nil, invalid("unknown action")
+	}
+	return t, nil
+}
+
+type transitionCheckKey struct{}
+
+// WithTransitionCheck makes subscription actions called with the returned
+// context fetch the subscription first and check the action with
+// Transition. An action which is not allowed fails with an error wrapping
+// ErrInvalidTransition and is not sent. It applies to Cancel, Pause,
+// Resume, RemoveScheduledCancellation, and to Update and PreviewUpdate when
+// they change items.
+func WithTransitionCheck(ctx context.Context) context.Context {
+	return context.WithValue(ctx, transitionCheckKey{}, true)
+}
+
+// checkTransition runs before a mutating request when the context asks for
+// it and the current operation is a subscription action.
+func (c *Client) checkTransition(ctx context.Context, body any) error {
+	if checked, _ := ctx.Value(transitionCheckKey{}).(bool); !checked {
+		return nil
+	}
+	op, ok := OperationFromContext(ctx)
+	if !ok || op.Service != "subscriptions" {
+		return nil
+	}
+	action, effectiveFrom, ok := subscriptionAction(op.Method, body)
+	if !ok {
+		return nil
+	}
+	sub, _, getErr := makeApiRequest[Subscription](ctx, c, http.MethodGet, "subscriptions/"+op.ResourceId, nil)
+	if getErr != nil {
+		return fmt.Errorf("failed to check %s: %w", op.Name(), getErr)
+	}
+	_, transitionErr := sub.Transition(action, effectiveFrom)
+	return transitionErr
+}
+
+// subscriptionAction returns the action a SubscriptionsService method
+// performs with body.
+func subscriptionAction(method string, body any) (SubscriptionAction, SubscriptionEffectFromOption, bool) {
+	switch method {
+	case "cancel":
+		if p, _ := body.(*CancelSubscriptionParams); p != nil {
+			return SubscriptionActionCancel, p.EffectiveFrom, true
+		}
+		return SubscriptionActionCancel, "", true
+	case "pause":
+		if p, _ := body.(*PauseSubscriptionParams); p != nil {
+			return SubscriptionActionPause, p.EffectiveFrom, true
+		}
+		return SubscriptionActionPause, "", true
+	case "resume":
+		if p, _ := body.(*ResumeSubscriptionParams); p != nil {
+			return SubscriptionActionResume, p.EffectiveFrom, true
+		}
+		return SubscriptionActionResume, "", true
+	case "update", "preview_update":
+		if p, _ := body.(*UpdateSubscriptionParams); p != nil && p.Items != nil {
+			return SubscriptionActionUpdateItems, "", true
+		}
+	case "remove_scheduled_cancellation":
+		return SubscriptionActionRemoveScheduledChange, "", true
+	}
+	return "", "", false
+}
+
+func scheduledChange(action SubscriptionScheduledChangeAction) *SubscriptionScheduledChangeAction {
+	return &action
+}
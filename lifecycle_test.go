@@ -0,0 +1,193 @@
+package paddle
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTransition(t *testing.T) {
+	const every = "*"
+	var (
+		immediately = SubscriptionEffectFromOptionImmediately
+		next        = SubscriptionEffectFromOptionNextBillingPeriod
+	)
+	statuses := []SubscriptionStatus{
+		SubscriptionStatusActive,
+		SubscriptionStatusTrialing,
+		SubscriptionStatusPastDue,
+		SubscriptionStatusPaused,
+		SubscriptionStatusCanceled,
+	}
+	scheduled := []SubscriptionScheduledChangeAction{
+		"",
+		SubscriptionScheduledChangeActionCancel,
+		SubscriptionScheduledChangeActionPause,
+		SubscriptionScheduledChangeActionResume,
+	}
+	actions := []struct {
+		action SubscriptionAction
+		from   SubscriptionEffectFromOption
+	}{
+		{SubscriptionActionPause, ""},
+		{SubscriptionActionPause, immediately},
+		{SubscriptionActionPause, next},
+		{SubscriptionActionResume, ""},
+		{SubscriptionActionResume, immediately},
+		{SubscriptionActionResume, next},
+		{SubscriptionActionCancel, ""},
+		{SubscriptionActionCancel, immediately},
+		{SubscriptionActionCancel, next},
+		{SubscriptionActionUpdateItems, ""},
+		{SubscriptionActionRemoveScheduledChange, ""},
+	}
+
+	// allowed lists every transition which succeeds; any other combination
+	// must fail. An empty wantStatus leaves the status unchanged, and every
+	// status other than canceled matches the wildcard.
+	allowed := []struct {
+		status        SubscriptionStatus
+		scheduled     SubscriptionScheduledChangeAction
+		action        SubscriptionAction
+		from          SubscriptionEffectFromOption
+		wantStatus    SubscriptionStatus
+		wantScheduled SubscriptionScheduledChangeAction
+	}{
+		{SubscriptionStatusActive, "", SubscriptionActionPause, "", "", SubscriptionScheduledChangeActionPause},
+		{SubscriptionStatusActive, "", SubscriptionActionPause, next, "", SubscriptionScheduledChangeActionPause},
+		{SubscriptionStatusActive, "", SubscriptionActionPause, immediately, SubscriptionStatusPaused, ""},
+
+		{SubscriptionStatusPaused, every, SubscriptionActionResume, "", SubscriptionStatusActive, ""},
+		{SubscriptionStatusPaused, every, SubscriptionActionResume, immediately, SubscriptionStatusActive, ""},
+
+		{every, every, SubscriptionActionCancel, immediately, SubscriptionStatusCanceled, ""},
+		{SubscriptionStatusActive, "", SubscriptionActionCancel, "", "", SubscriptionScheduledChangeActionCancel},
+		{SubscriptionStatusActive, "", SubscriptionActionCancel, next, "", SubscriptionScheduledChangeActionCancel},
+		{SubscriptionStatusTrialing, "", SubscriptionActionCancel, "", "", SubscriptionScheduledChangeActionCancel},
+		{SubscriptionStatusTrialing, "", SubscriptionActionCancel, next, "", SubscriptionScheduledChangeActionCancel},
+
+		{SubscriptionStatusActive, every, SubscriptionActionUpdateItems, "", "", ""},
+		{SubscriptionStatusTrialing, every, SubscriptionActionUpdateItems, "", "", ""},
+
+		{every, SubscriptionScheduledChangeActionCancel, SubscriptionActionRemoveScheduledChange, "", "", ""},
+		{every, SubscriptionScheduledChangeActionPause, SubscriptionActionRemoveScheduledChange, "", "", ""},
+		{every, SubscriptionScheduledChangeActionResume, SubscriptionActionRemoveScheduledChange, "", "", ""},
+	}
+
+	for _, status := range statuses {
+		for _, change := range scheduled {
+			for _, a := range actions {
+				name := string(status) + "/" + string(change) + "/" + string(a.action) + "/" + string(a.from)
+				t.Run(name, func(t *testing.T) {
+					sub := &Subscription{Id: "sub_1", Status: status}
+					if change != "" {
+						sub.ScheduledChange = &SubscriptionScheduledChange{Action: change}
+					}
+					got, err := sub.Transition(a.action, a.from)
+
+					var want *SubscriptionTransition
+					for _, rule := range allowed {
+						if (rule.status == status || rule.status == every && status != SubscriptionStatusCanceled) &&
+							(rule.scheduled == change || rule.scheduled == every) &&
+							rule.action == a.action && rule.from == a.from {
+							want = &SubscriptionTransition{Action: a.action, Status: status}
+							if rule.wantStatus != "" {
+								want.Status = rule.wantStatus
+							}
+							if rule.wantScheduled != "" {
+								want.ScheduledChange = scheduledChange(rule.wantScheduled)
+							}
+							want.EffectiveFrom = a.from
+							if a.from == "" && a.action == SubscriptionActionResume {
+								want.EffectiveFrom = immediately
+							} else if a.from == "" && (a.action == SubscriptionActionPause || a.action == SubscriptionActionCancel) {
+								want.EffectiveFrom = next
+							}
+						}
+					}
+
+					if want == nil {
+						if !errors.Is(err, ErrInvalidTransition) {
+							t.Fatalf("Transition() = %+v, %v, want ErrInvalidTransition", got, err)
+						}
+						return
+					}
+					if err != nil {
+						t.Fatalf("Transition() error = %v", err)
+					}
+					if !reflect.DeepEqual(got, want) {
+						t.Errorf("Transition() = %+v, want %+v", got, want)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestTransitionErrors(t *testing.T) {
+	sub := &Subscription{Id: "sub_1", Status: SubscriptionStatusActive}
+	tests := []struct {
+		action SubscriptionAction
+		from   SubscriptionEffectFromOption
+		want   string
+	}{
+		{"upgrade", "", "invalid subscription transition: subscription sub_1: cannot upgrade: unknown action"},
+		{SubscriptionActionCancel, "later", `invalid subscription transition: subscription sub_1: cannot cancel: unknown effective_from "later"`},
+		{SubscriptionActionRemoveScheduledChange, "", "invalid subscription transition: subscription sub_1: cannot remove scheduled change: it has no scheduled change"},
+	}
+	for _, tt := range tests {
+		if _, err := sub.Transition(tt.action, tt.from); err == nil || err.Error() != tt.want {
+			t.Errorf("Transition(%s, %q) error = %v, want %s", tt.action, tt.from, err, tt.want)
+		}
+	}
+}
+
+func TestAllowedActions(t *testing.T) {
+	tests := []struct {
+		status    SubscriptionStatus
+		scheduled SubscriptionScheduledChangeAction
+		want      []SubscriptionAction
+	}{
+		{SubscriptionStatusActive, "", []SubscriptionAction{SubscriptionActionPause, SubscriptionActionCancel, SubscriptionActionUpdateItems}},
+		{SubscriptionStatusActive, SubscriptionScheduledChangeActionCancel, []SubscriptionAction{SubscriptionActionCancel, SubscriptionActionUpdateItems, SubscriptionActionRemoveScheduledChange}},
+		{SubscriptionStatusPastDue, "", []SubscriptionAction{SubscriptionActionCancel}},
+		{SubscriptionStatusPaused, SubscriptionScheduledChangeActionResume, []SubscriptionAction{SubscriptionActionResume, SubscriptionActionCancel, SubscriptionActionRemoveScheduledChange}},
+		{SubscriptionStatusCanceled, "", nil},
+	}
+	for _, tt := range tests {
+		sub := &Subscription{Status: tt.status}
+		if tt.scheduled != "" {
+			sub.ScheduledChange = &SubscriptionScheduledChange{Action: tt.scheduled}
+		}
+		if got := sub.AllowedActions(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s with scheduled %q: AllowedActions() = %v, want %v", tt.status, tt.scheduled, got, tt.want)
+		}
+	}
+}
+
+func TestSubscriptionAction(t *testing.T) {
+	items := &[]UpdateSubscriptionItem{{PriceId: "pri_1", Quantity: 1}}
+	tests := []struct {
+		method     string
+		body       any
+		wantAction SubscriptionAction
+		wantFrom   SubscriptionEffectFromOption
+		wantOk     bool
+	}{
+		{"cancel", (*CancelSubscriptionParams)(nil), SubscriptionActionCancel, "", true},
+		{"cancel", &CancelSubscriptionParams{EffectiveFrom: SubscriptionEffectFromOptionImmediately}, SubscriptionActionCancel, SubscriptionEffectFromOptionImmediately, true},
+		{"pause", &PauseSubscriptionParams{EffectiveFrom: SubscriptionEffectFromOptionNextBillingPeriod}, SubscriptionActionPause, SubscriptionEffectFromOptionNextBillingPeriod, true},
+		{"resume", (*ResumeSubscriptionParams)(nil), SubscriptionActionResume, "", true},
+		{"update", &UpdateSubscriptionParams{Items: items}, SubscriptionActionUpdateItems, "", true},
+		{"preview_update", &UpdateSubscriptionParams{Items: items}, SubscriptionActionUpdateItems, "", true},
+		{"update", &UpdateSubscriptionParams{CustomData: &CustomData{"a": "b"}}, "", "", false},
+		{"remove_scheduled_cancellation", struct{}{}, SubscriptionActionRemoveScheduledChange, "", true},
+		{"get_update_payment_method_transaction", nil, "", "", false},
+	}
+	for _, tt := range tests {
+		action, from, ok := subscriptionAction(tt.method, tt.body)
+		if action != tt.wantAction || from != tt.wantFrom || ok != tt.wantOk {
+			t.Errorf("subscriptionAction(%s) = %s, %q, %v, want %s, %q, %v", tt.method, action, from, ok, tt.wantAction, tt.wantFrom, tt.wantOk)
+		}
+	}
+}
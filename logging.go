@@ -0,0 +1,93 @@
+package paddle
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const redacted = "[REDACTED]"
+
+var sensitiveHeaders = map[string]bool{
+	"Authorization":        true,
+	"Cookie":               true,
+	WebhookSignatureHeader: true,
+}
+
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (d discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return d }
+func (d discardHandler) WithGroup(string) slog.Handler           { return d }
+
+func newLogger(logger *slog.Logger) *slog.Logger {
+	if logger == nil {
+		return slog.New(discardHandler{})
+	}
+	return logger
+}
+
+type redactedHeader http.Header
+
+func (h redactedHeader) LogValue() slog.Value {
+	attrs := make([]slog.Attr, 0, len(h))
+	for key, values := range h {
+		if sensitiveHeaders[http.CanonicalHeaderKey(key)] {
+			attrs = append(attrs, slog.String(key, redacted))
+			continue
+		}
+		attrs = append(attrs, slog.Any(key, values))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+func (c CardDetails) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("type", string(c.Type)),
+		slog.String("last4", redacted),
+		slog.String("expiry", redacted),
+		slog.String("cardholder_name", redacted),
+	)
+}
+
+func loggingMiddleware(logger *slog.Logger) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *http.Request) (*ApiResponse, error) {
+			logger.DebugContext(ctx, "paddle: sending request",
+				slog.String("method", req.Method),
+				slog.String("path", req.URL.Path),
+				slog.Any("headers", redactedHeader(req.Header)))
+
+			start := time.Now()
+			res, err := next(ctx, req)
+
+			attrs := []any{
+				slog.String("method", req.Method),
+				slog.String("path", req.URL.Path),
+				slog.Duration("latency", time.Since(start)),
+			}
+			if res != nil {
+				attrs = append(attrs,
+					slog.Int("status", res.StatusCode),
+					slog.String("request_id", res.Meta.RequestId))
+			}
+			if err == nil {
+				logger.InfoContext(ctx, "paddle: request completed", attrs...)
+				return res, err
+			}
+
+			var apiErr *ApiError
+			if errors.As(err, &apiErr) {
+				attrs = append(attrs,
+					slog.String("error_code", string(apiErr.Code)),
+					slog.Any("field_errors", apiErr.FieldErrors()))
+			}
+			attrs = append(attrs, slog.Any("error", err))
+			logger.ErrorContext(ctx, "paddle: request failed", attrs...)
+			return res, err
+		}
+	}
+}
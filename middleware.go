@@ -0,0 +1,55 @@
+package paddle
+
+import (
+	"context"
+	"net/http"
+)
+
+// Handler performs an API request and decodes its response. When Paddle
+// responds with an error, the decoded *ApiResponse is returned alongside
+// the *ApiError.
+type Handler func(ctx context.Context, req *http.Request) (*ApiResponse, error)
+
+// Middleware wraps a Handler, e.g. to add headers, record timings or retry
+// failed requests.
+type Middleware func(next Handler) Handler
+
+func chainMiddleware(h Handler, middleware []Middleware) Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		if middleware[i] != nil {
+			h = middleware[i](h)
+		}
+	}
+	return h
+}
+
+// HeaderMiddleware sets the given headers on every request, replacing any
+// existing values.
+func HeaderMiddleware(headers http.Header) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *http.Request) (*ApiResponse, error) {
+			for key, values := range headers {
+				req.Header.Del(key)
+				for _, v := range values {
+					req.Header.Add(key, v)
+				}
+			}
+			return next(ctx, req)
+		}
+	}
+}
+
+// APIKeyMiddleware resolves the API key per request, e.g. for a tenant
+// stored in the context.
+func APIKeyMiddleware(apiKey func(ctx context.Context) (string, error)) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *http.Request) (*ApiResponse, error) {
+			key, keyErr := apiKey(ctx)
+			if keyErr != nil {
+				return nil, keyErr
+			}
+			req.Header.Set("Authorization", "Bearer "+key)
+			return next(ctx, req)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+module github.com/texm/go-paddle/mirror
+
+go 1.21
+
+require (
+	github.com/lib/pq v1.10.9
+	github.com/mattn/go-sqlite3 v1.14.52
+	github.com/texm/go-paddle v0.1.0
+)
@@ -0,0 +1,224 @@
+// Package mirror keeps a copy of Paddle customers and their addresses,
+// subscriptions, transactions, products and prices in a SQL database, so
+// reads no longer need the API.
+//
+//	db, _ := sql.Open("sqlite3", "paddle.db") // driver registered by the program
+//	m := mirror.New(db, mirror.SQLite, client)
+//	if err := m.Migrate(ctx); err != nil { ... }
+//	if err := m.Backfill(ctx); err != nil { ... }
+//	http.Handle("/webhooks/paddle", m.WebhookHandler())
+//
+// Each row records the resource's updated_at, or the event's occurred_at
+// when the resource has none, as its version. A write never replaces a row
+// with a newer version, so backfills and late or repeated webhooks can be
+// applied in any order.
+package mirror
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/texm/go-paddle"
+)
+
+var ErrNotFound = errors.New("mirror: not found")
+
+type Mirror struct {
+	db      *sql.DB
+	dialect Dialect
+	api     paddle.API
+}
+
+func New(db *sql.DB, dialect Dialect, api paddle.API) *Mirror {
+	return &Mirror{db: db, dialect: dialect, api: api}
+}
+
+// Migrate creates the mirror's tables if they do not exist.
+func (m *Mirror) Migrate(ctx context.Context) error {
+	for _, stmt := range Schema(m.dialect) {
+		if _, execErr := m.db.ExecContext(ctx, stmt); execErr != nil {
+			return fmt.Errorf("failed to migrate: %w", execErr)
+		}
+	}
+	return nil
+}
+
+// Backfill copies every resource from the API into the database.
+func (m *Mirror) Backfill(ctx context.Context) error {
+	fetchedAt := time.Now()
+	allStatuses := []paddle.Status{paddle.StatusActive, paddle.StatusArchived}
+
+	customerList, customersErr := m.api.CustomersAPI().List(ctx, &paddle.ListCustomersParams{Status: allStatuses})
+	if customersErr != nil {
+		return fmt.Errorf("failed to list customers: %w", customersErr)
+	}
+	if storeErr := storeAll(ctx, m, customers, customerList, fetchedAt); storeErr != nil {
+		return storeErr
+	}
+	for _, c := range customerList {
+		addressList, addressesErr := m.api.AddressesAPI().List(ctx, c.Id, &paddle.ListAddressesParams{Status: allStatuses})
+		if addressesErr != nil {
+			return fmt.Errorf("failed to list addresses for %s: %w", c.Id, addressesErr)
+		}
+		if storeErr := storeAll(ctx, m, addresses, addressList, fetchedAt); storeErr != nil {
+			return storeErr
+		}
+	}
+
+	subscriptionList, subscriptionsErr := m.api.SubscriptionsAPI().List(ctx, nil)
+	if subscriptionsErr != nil {
+		return fmt.Errorf("failed to list subscriptions: %w", subscriptionsErr)
+	}
+	if storeErr := storeAll(ctx, m, subscriptions, subscriptionList, fetchedAt); storeErr != nil {
+		return storeErr
+	}
+
+	transactionList, transactionsErr := m.api.TransactionsAPI().List(ctx, nil)
+	if transactionsErr != nil {
+		return fmt.Errorf("failed to list transactions: %w", transactionsErr)
+	}
+	if storeErr := storeAll(ctx, m, transactions, transactionList, fetchedAt); storeErr != nil {
+		return storeErr
+	}
+
+	productList, productsErr := m.api.ProductsAPI().List(ctx, &paddle.ListProductsParams{Status: allStatuses})
+	if productsErr != nil {
+		return fmt.Errorf("failed to list products: %w", productsErr)
+	}
+	if storeErr := storeAll(ctx, m, products, productList, fetchedAt); storeErr != nil {
+		return storeErr
+	}
+
+	priceList, pricesErr := m.api.PricesAPI().List(ctx, &paddle.ListPricesParams{Status: allStatuses})
+	if pricesErr != nil {
+		return fmt.Errorf("failed to list prices: %w", pricesErr)
+	}
+	return storeAll(ctx, m, prices, priceList, fetchedAt)
+}
+
+// HandleEvent applies a webhook event. Events for other resources are
+// ignored, and an event which has already been applied is skipped.
+func (m *Mirror) HandleEvent(ctx context.Context, event *paddle.WebhookEvent) error {
+	var storeErr error
+	switch resource, _, _ := strings.Cut(event.Type, "."); resource {
+	case "customer":
+		storeErr = storeEvent(ctx, m, customers, event)
+	case "address":
+		storeErr = storeEvent(ctx, m, addresses, event)
+	case "subscription":
+		storeErr = storeEvent(ctx, m, subscriptions, event)
+	case "transaction":
+		storeErr = storeEvent(ctx, m, transactions, event)
+	case "product":
+		storeErr = storeEvent(ctx, m, products, event)
+	case "price":
+		storeErr = storeEvent(ctx, m, prices, event)
+	default:
+		return nil
+	}
+	if errors.Is(storeErr, errDuplicateEvent) {
+		return nil
+	}
+	return storeErr
+}
+
+// WebhookHandler verifies deliveries with the client's webhook secret and
+// applies them. Paddle retries deliveries which do not succeed.
+func (m *Mirror) WebhookHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		event, parseErr := m.api.ParseWebhook(r)
+		if parseErr != nil {
+			http.Error(w, "invalid webhook", http.StatusBadRequest)
+			return
+		}
+		if handleErr := m.HandleEvent(r.Context(), event); handleErr != nil {
+			http.Error(w, "failed to apply webhook", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+var errDuplicateEvent = errors.New("mirror: event already applied")
+
+func storeAll[T any](ctx context.Context, m *Mirror, t *table[T], items []*T, fallback time.Time) error {
+	tx, txErr := m.db.BeginTx(ctx, nil)
+	if txErr != nil {
+		return txErr
+	}
+	defer tx.Rollback()
+	for _, item := range items {
+		if storeErr := store(ctx, m, tx, t, item, fallback); storeErr != nil {
+			return storeErr
+		}
+	}
+	return tx.Commit()
+}
+
+func storeEvent[T any](ctx context.Context, m *Mirror, t *table[T], event *paddle.WebhookEvent) error {
+	var item T
+	if jsonErr := json.Unmarshal(event.Data, &item); jsonErr != nil {
+		return fmt.Errorf("failed to decode %s data: %w", event.Type, jsonErr)
+	}
+
+	tx, txErr := m.db.BeginTx(ctx, nil)
+	if txErr != nil {
+		return txErr
+	}
+	defer tx.Rollback()
+
+	d := m.dialect
+	var seen int
+	countErr := tx.QueryRowContext(ctx,
+		fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE event_id = %s", eventsTable, d.placeholder(1)),
+		event.Id).Scan(&seen)
+	if countErr != nil {
+		return countErr
+	}
+	if seen > 0 {
+		return errDuplicateEvent
+	}
+	if _, execErr := tx.ExecContext(ctx,
+		fmt.Sprintf("INSERT INTO %s (event_id, event_type, occurred_at) VALUES (%s, %s, %s)",
+			eventsTable, d.placeholder(1), d.placeholder(2), d.placeholder(3)),
+		event.Id, event.Type, d.timeValue(event.OccurredAt)); execErr != nil {
+		return execErr
+	}
+
+	if storeErr := store(ctx, m, tx, t, &item, event.OccurredAt); storeErr != nil {
+		return storeErr
+	}
+	return tx.Commit()
+}
+
+func store[T any](ctx context.Context, m *Mirror, tx *sql.Tx, t *table[T], item *T, fallback time.Time) error {
+	version := t.updatedAt(item)
+	if version.IsZero() {
+		version = fallback
+	}
+	data, jsonErr := json.Marshal(item)
+	if jsonErr != nil {
+		return jsonErr
+	}
+
+	args := []any{t.id(item)}
+	for _, c := range t.columns {
+		v := c.value(item)
+		if tv, ok := v.(time.Time); ok {
+			v = m.dialect.timeValue(tv)
+		}
+		args = append(args, v)
+	}
+	args = append(args, version.UnixNano(), string(data))
+
+	if _, execErr := tx.ExecContext(ctx, t.upsert(m.dialect), args...); execErr != nil {
+		return fmt.Errorf("failed to store %s %s: %w", t.name, t.id(item), execErr)
+	}
+	return nil
+}
@@ -0,0 +1,447 @@
+package mirror
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/texm/go-paddle"
+	"github.com/texm/go-paddle/paddletest"
+)
+
+// postgresEnv names a Postgres DSN to run the tests against as well as
+// SQLite. The mirror's tables are dropped before and after each test.
+const postgresEnv = "PADDLE_MIRROR_POSTGRES_DSN"
+
+var t0 = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+type testDB struct {
+	dialect Dialect
+	open    func(t *testing.T) *sql.DB
+	// customData selects custom_data.plan.seats from a row's JSON.
+	customData string
+}
+
+var testDBs = []testDB{
+	{
+		dialect: SQLite,
+		open: func(t *testing.T) *sql.DB {
+			db, openErr := sql.Open("sqlite3", ":memory:")
+			if openErr != nil {
+				t.Fatal(openErr)
+			}
+			// Each connection to :memory: is a separate database.
+			db.SetMaxOpenConns(1)
+			if pingErr := db.Ping(); pingErr != nil {
+				t.Skipf("sqlite unavailable: %v", pingErr)
+			}
+			return db
+		},
+		customData: "json_extract(data, '$.custom_data.plan.seats')",
+	},
+	{
+		dialect: Postgres,
+		open: func(t *testing.T) *sql.DB {
+			dsn := os.Getenv(postgresEnv)
+			if dsn == "" {
+				t.Skipf("%s not set", postgresEnv)
+			}
+			db, openErr := sql.Open("postgres", dsn)
+			if openErr != nil {
+				t.Fatal(openErr)
+			}
+			dropTables(t, db)
+			t.Cleanup(func() { dropTables(t, db) })
+			return db
+		},
+		customData: "(data->'custom_data'->'plan'->>'seats')::int",
+	},
+}
+
+func dropTables(t *testing.T, db *sql.DB) {
+	for _, name := range []string{customers.name, addresses.name, subscriptions.name, transactions.name, products.name, prices.name, eventsTable} {
+		if _, execErr := db.Exec("DROP TABLE IF EXISTS " + name); execErr != nil {
+			t.Fatal(execErr)
+		}
+	}
+}
+
+// forEachDB runs f against a migrated mirror in each available database.
+func forEachDB(t *testing.T, api paddle.API, f func(t *testing.T, m *Mirror, tdb testDB)) {
+	for _, tdb := range testDBs {
+		t.Run(tdb.dialect.String(), func(t *testing.T) {
+			db := tdb.open(t)
+			t.Cleanup(func() { db.Close() })
+			m := New(db, tdb.dialect, api)
+			if migrateErr := m.Migrate(context.Background()); migrateErr != nil {
+				t.Fatal(migrateErr)
+			}
+			// Migrate is idempotent.
+			if migrateErr := m.Migrate(context.Background()); migrateErr != nil {
+				t.Fatal(migrateErr)
+			}
+			f(t, m, tdb)
+		})
+	}
+}
+
+func newEvent(t *testing.T, id string, eventType string, occurredAt time.Time, data any) *paddle.WebhookEvent {
+	t.Helper()
+	raw, jsonErr := json.Marshal(data)
+	if jsonErr != nil {
+		t.Fatal(jsonErr)
+	}
+	return &paddle.WebhookEvent{Id: id, Type: eventType, OccurredAt: occurredAt, Data: raw}
+}
+
+func countEvents(t *testing.T, m *Mirror) int {
+	t.Helper()
+	var n int
+	if scanErr := m.db.QueryRow("SELECT COUNT(*) FROM " + eventsTable).Scan(&n); scanErr != nil {
+		t.Fatal(scanErr)
+	}
+	return n
+}
+
+func TestNotFound(t *testing.T) {
+	forEachDB(t, nil, func(t *testing.T, m *Mirror, _ testDB) {
+		ctx := context.Background()
+		tests := []struct {
+			get  func() error
+			want string
+		}{
+			{func() error { _, err := m.Customer(ctx, "ctm_1"); return err }, "customer ctm_1"},
+			{func() error { _, err := m.Address(ctx, "add_1"); return err }, "address add_1"},
+			{func() error { _, err := m.Subscription(ctx, "sub_1"); return err }, "subscription sub_1"},
+			{func() error { _, err := m.Transaction(ctx, "txn_1"); return err }, "transaction txn_1"},
+			{func() error { _, err := m.Product(ctx, "pro_1"); return err }, "product pro_1"},
+			{func() error { _, err := m.Price(ctx, "pri_1"); return err }, "price pri_1"},
+		}
+		for _, tt := range tests {
+			err := tt.get()
+			if !errors.Is(err, ErrNotFound) {
+				t.Fatalf("error = %v, want ErrNotFound", err)
+			}
+			if want := ErrNotFound.Error() + ": " + tt.want; err.Error() != want {
+				t.Errorf("error = %q, want %q", err, want)
+			}
+		}
+	})
+}
+
+func TestVersionGuardedUpsert(t *testing.T) {
+	forEachDB(t, nil, func(t *testing.T, m *Mirror, _ testDB) {
+		ctx := context.Background()
+		steps := []struct {
+			name       string
+			updatedAt  time.Time
+			occurredAt time.Time
+			email      string
+			want       string
+		}{
+			{"first write", t0.Add(2 * time.Hour), t0, "b@example.com", "b@example.com"},
+			{"older version is ignored", t0.Add(time.Hour), t0.Add(3 * time.Hour), "a@example.com", "b@example.com"},
+			{"same version is applied", t0.Add(2 * time.Hour), t0, "c@example.com", "c@example.com"},
+			{"newer version is applied", t0.Add(3 * time.Hour), t0, "d@example.com", "d@example.com"},
+			{"older occurred_at without updated_at is ignored", time.Time{}, t0.Add(time.Hour), "e@example.com", "d@example.com"},
+			{"newer occurred_at without updated_at is applied", time.Time{}, t0.Add(4 * time.Hour), "f@example.com", "f@example.com"},
+		}
+		for i, step := range steps {
+			c := paddle.Customer{Id: "ctm_1", Status: paddle.StatusActive, Email: step.email, CreatedAt: t0, UpdatedAt: step.updatedAt}
+			if err := m.HandleEvent(ctx, newEvent(t, fmt.Sprintf("evt_%d", i), "customer.updated", step.occurredAt, c)); err != nil {
+				t.Fatalf("%s: HandleEvent() = %v", step.name, err)
+			}
+			got, getErr := m.Customer(ctx, "ctm_1")
+			if getErr != nil {
+				t.Fatalf("%s: %v", step.name, getErr)
+			}
+			if got.Email != step.want {
+				t.Errorf("%s: email = %q, want %q", step.name, got.Email, step.want)
+			}
+			// The email column is written with the same guard as the data.
+			byEmail, listErr := m.Customers(ctx, CustomerQuery{Email: step.want})
+			if listErr != nil {
+				t.Fatal(listErr)
+			}
+			if len(byEmail) != 1 {
+				t.Errorf("%s: Customers(email=%s) returned %d", step.name, step.want, len(byEmail))
+			}
+		}
+	})
+}
+
+func TestDuplicateEvents(t *testing.T) {
+	forEachDB(t, nil, func(t *testing.T, m *Mirror, _ testDB) {
+		ctx := context.Background()
+		first := paddle.Product{Id: "pro_1", Name: "Basic", Status: paddle.StatusActive, CreatedAt: t0, UpdatedAt: t0}
+		// A redelivery must not apply, even if its data would win on version.
+		retried := first
+		retried.Name = "Changed"
+		retried.UpdatedAt = t0.Add(time.Hour)
+
+		for i, p := range []paddle.Product{first, retried, retried} {
+			if err := m.HandleEvent(ctx, newEvent(t, "evt_1", "product.created", t0, p)); err != nil {
+				t.Fatalf("delivery %d: HandleEvent() = %v", i+1, err)
+			}
+		}
+		got, getErr := m.Product(ctx, "pro_1")
+		if getErr != nil {
+			t.Fatal(getErr)
+		}
+		if got.Name != "Basic" {
+			t.Errorf("name = %q after duplicate deliveries, want Basic", got.Name)
+		}
+		if n := countEvents(t, m); n != 1 {
+			t.Errorf("%d events recorded, want 1", n)
+		}
+
+		if err := m.HandleEvent(ctx, newEvent(t, "evt_2", "discount.created", t0, map[string]string{"id": "dsc_1"})); err != nil {
+			t.Fatalf("HandleEvent(discount.created) = %v", err)
+		}
+		if n := countEvents(t, m); n != 1 {
+			t.Errorf("%d events recorded after an ignored event, want 1", n)
+		}
+
+		badData := &paddle.WebhookEvent{Id: "evt_3", Type: "product.updated", OccurredAt: t0, Data: json.RawMessage(`[]`)}
+		if err := m.HandleEvent(ctx, badData); err == nil {
+			t.Error("HandleEvent() with invalid data = nil")
+		}
+		if n := countEvents(t, m); n != 1 {
+			t.Errorf("%d events recorded after a failed event, want 1", n)
+		}
+	})
+}
+
+func TestOutOfOrderWebhooks(t *testing.T) {
+	canceledAt := t0.Add(2 * time.Hour)
+	subscription := func(status paddle.SubscriptionStatus, updatedAt time.Time) paddle.Subscription {
+		sub := paddle.Subscription{Id: "sub_1", CustomerId: "ctm_1", Status: status, CurrencyCode: paddle.CurrencyCodeUSD, CreatedAt: t0, UpdatedAt: updatedAt}
+		if status == paddle.SubscriptionStatusCanceled {
+			sub.CanceledAt = &canceledAt
+		}
+		return sub
+	}
+	events := []struct {
+		id, eventType string
+		sub           paddle.Subscription
+	}{
+		{"evt_1", "subscription.created", subscription(paddle.SubscriptionStatusActive, t0)},
+		{"evt_2", "subscription.past_due", subscription(paddle.SubscriptionStatusPastDue, t0.Add(time.Hour))},
+		{"evt_3", "subscription.canceled", subscription(paddle.SubscriptionStatusCanceled, canceledAt)},
+	}
+	orders := [][]int{{0, 1, 2}, {2, 1, 0}, {1, 2, 0}, {2, 0, 1}, {0, 2, 1, 0}}
+
+	forEachDB(t, nil, func(t *testing.T, m *Mirror, _ testDB) {
+		ctx := context.Background()
+		for _, order := range orders {
+			dropTables(t, m.db)
+			if migrateErr := m.Migrate(ctx); migrateErr != nil {
+				t.Fatal(migrateErr)
+			}
+			for _, i := range order {
+				e := events[i]
+				if err := m.HandleEvent(ctx, newEvent(t, e.id, e.eventType, e.sub.UpdatedAt, e.sub)); err != nil {
+					t.Fatalf("order %v: HandleEvent(%s) = %v", order, e.eventType, err)
+				}
+			}
+			got, getErr := m.Subscription(ctx, "sub_1")
+			if getErr != nil {
+				t.Fatal(getErr)
+			}
+			if got.Status != paddle.SubscriptionStatusCanceled || got.CanceledAt == nil || !got.CanceledAt.Equal(canceledAt) {
+				t.Errorf("order %v: status = %s, canceled_at = %v, want canceled at %v", order, got.Status, got.CanceledAt, canceledAt)
+			}
+			for status, want := range map[paddle.SubscriptionStatus]int{
+				paddle.SubscriptionStatusActive:   0,
+				paddle.SubscriptionStatusPastDue:  0,
+				paddle.SubscriptionStatusCanceled: 1,
+			} {
+				subs, listErr := m.Subscriptions(ctx, SubscriptionQuery{CustomerId: "ctm_1", Status: []paddle.SubscriptionStatus{status}})
+				if listErr != nil {
+					t.Fatal(listErr)
+				}
+				if len(subs) != want {
+					t.Errorf("order %v: %d %s subscriptions, want %d", order, len(subs), status, want)
+				}
+			}
+		}
+	})
+}
+
+func TestJSONWrites(t *testing.T) {
+	customerId, subscriptionId := "ctm_1", "sub_1"
+	billedAt := t0.Add(time.Hour)
+	txn := paddle.Transaction{
+		Id:             "txn_1",
+		Status:         paddle.TransactionStatusCompleted,
+		CustomerId:     &customerId,
+		SubscriptionId: &subscriptionId,
+		CurrencyCode:   paddle.CurrencyCodeGBP,
+		Origin:         paddle.TransactionOriginSubscriptionRecurring,
+		CollectionMode: paddle.PaymentCollectionModeAutomatic,
+		BillingPeriod:  &paddle.TimePeriod{StartsAt: t0, EndsAt: t0.AddDate(0, 1, 0)},
+		Items: []paddle.TransactionItem{{
+			Price:    paddle.Price{Id: "pri_1", ProductId: "pro_1", Description: "Monthly \"pro\" ünïcode", UnitPrice: paddle.Money{Amount: paddle.NewAmount(1000), CurrencyCode: paddle.CurrencyCodeGBP}},
+			Quantity: 3,
+		}},
+		CustomData: paddle.CustomData{"plan": map[string]any{"seats": float64(3), "tags": []any{"a", "b"}}},
+		CreatedAt:  t0,
+		UpdatedAt:  billedAt,
+		BilledAt:   &billedAt,
+	}
+
+	forEachDB(t, nil, func(t *testing.T, m *Mirror, tdb testDB) {
+		ctx := context.Background()
+		if err := m.HandleEvent(ctx, newEvent(t, "evt_1", "transaction.completed", billedAt, txn)); err != nil {
+			t.Fatal(err)
+		}
+
+		got, getErr := m.Transaction(ctx, "txn_1")
+		if getErr != nil {
+			t.Fatal(getErr)
+		}
+		gotJSON, _ := json.Marshal(got)
+		wantJSON, _ := json.Marshal(txn)
+		if string(gotJSON) != string(wantJSON) {
+			t.Errorf("Transaction() = %s, want %s", gotJSON, wantJSON)
+		}
+
+		var seats int
+		if scanErr := m.db.QueryRow("SELECT " + tdb.customData + " FROM " + transactions.name).Scan(&seats); scanErr != nil {
+			t.Fatal(scanErr)
+		}
+		if seats != 3 {
+			t.Errorf("custom_data.plan.seats = %d, want 3", seats)
+		}
+
+		tests := []struct {
+			name string
+			q    TransactionQuery
+			want int
+		}{
+			{"subscription", TransactionQuery{SubscriptionId: subscriptionId}, 1},
+			{"other subscription", TransactionQuery{SubscriptionId: "sub_2"}, 0},
+			{"billed in range", TransactionQuery{BilledFrom: billedAt, BilledTo: billedAt.Add(time.Second)}, 1},
+			{"billed before range", TransactionQuery{BilledFrom: billedAt.Add(time.Nanosecond)}, 0},
+			{"billed after range", TransactionQuery{BilledTo: billedAt}, 0},
+			{"status", TransactionQuery{CustomerId: customerId, Status: []paddle.TransactionStatus{paddle.TransactionStatusBilled, paddle.TransactionStatusCompleted}}, 1},
+		}
+		for _, tt := range tests {
+			txns, listErr := m.Transactions(ctx, tt.q)
+			if listErr != nil {
+				t.Fatalf("%s: %v", tt.name, listErr)
+			}
+			if len(txns) != tt.want {
+				t.Errorf("%s: %d transactions, want %d", tt.name, len(txns), tt.want)
+			}
+		}
+	})
+}
+
+func TestBackfill(t *testing.T) {
+	srv := paddletest.NewServer()
+	defer srv.Close()
+	now := t0
+	srv.Now = func() time.Time { return now }
+	srv.PerPage = 2
+	client := srv.PaddleClient(nil)
+
+	product := srv.AddProduct(paddle.Product{Name: "Pro"})
+	srv.AddProduct(paddle.Product{Name: "Legacy", Status: paddle.StatusArchived})
+	price := srv.AddPrice(paddle.Price{ProductId: product.Id, Description: "Monthly"})
+	srv.AddPrice(paddle.Price{ProductId: product.Id, Description: "Yearly", Status: paddle.StatusArchived})
+	var customerIds []string
+	for i := 0; i < 3; i++ {
+		c := srv.AddCustomer(paddle.Customer{Email: fmt.Sprintf("c%d@example.com", i)})
+		customerIds = append(customerIds, c.Id)
+		srv.AddAddress(paddle.Address{CustomerId: c.Id, CountryCode: "GB"})
+		srv.AddAddress(paddle.Address{CustomerId: c.Id, CountryCode: "US", Status: paddle.StatusArchived})
+	}
+	sub := srv.AddSubscription(paddle.Subscription{CustomerId: customerIds[0], CurrencyCode: paddle.CurrencyCodeUSD})
+	srv.AddTransaction(paddle.Transaction{CustomerId: &customerIds[0], SubscriptionId: &sub.Id, Status: paddle.TransactionStatusCompleted})
+
+	forEachDB(t, client, func(t *testing.T, m *Mirror, _ testDB) {
+		ctx := context.Background()
+		now = t0
+		if _, updateErr := client.Customers.Update(ctx, customerIds[1], &paddle.UpdateCustomerParams{Email: ptr("before@example.com")}); updateErr != nil {
+			t.Fatal(updateErr)
+		}
+		if err := m.Backfill(ctx); err != nil {
+			t.Fatalf("Backfill() = %v", err)
+		}
+
+		counts := []struct {
+			name string
+			list func() (int, error)
+			want int
+		}{
+			{"customers", func() (int, error) { l, err := m.Customers(ctx, CustomerQuery{}); return len(l), err }, 3},
+			{"addresses", func() (int, error) { l, err := m.Addresses(ctx, AddressQuery{}); return len(l), err }, 6},
+			{"archived addresses", func() (int, error) {
+				l, err := m.Addresses(ctx, AddressQuery{Status: []paddle.Status{paddle.StatusArchived}})
+				return len(l), err
+			}, 3},
+			{"subscriptions", func() (int, error) { l, err := m.Subscriptions(ctx, SubscriptionQuery{}); return len(l), err }, 1},
+			{"transactions", func() (int, error) { l, err := m.Transactions(ctx, TransactionQuery{}); return len(l), err }, 1},
+			{"products", func() (int, error) { l, err := m.Products(ctx); return len(l), err }, 2},
+			{"prices", func() (int, error) { l, err := m.Prices(ctx, product.Id); return len(l), err }, 2},
+		}
+		for _, c := range counts {
+			n, listErr := c.list()
+			if listErr != nil {
+				t.Fatalf("%s: %v", c.name, listErr)
+			}
+			if n != c.want {
+				t.Errorf("%d %s after backfill, want %d", n, c.name, c.want)
+			}
+		}
+		productList, productsErr := m.Products(ctx, paddle.StatusActive)
+		if productsErr != nil {
+			t.Fatal(productsErr)
+		}
+		if len(productList) != 1 || productList[0].Prices == nil || len(*productList[0].Prices) != 2 || (*productList[0].Prices)[0].Id != price.Id {
+			t.Errorf("Products(active) = %+v, want %s with its prices", productList, product.Id)
+		}
+
+		// A webhook newer than the API's copy arrives, then the API changes
+		// another customer and the mirror is resynced.
+		now = t0.Add(2 * time.Hour)
+		newer := paddle.Customer{Id: customerIds[1], Status: paddle.StatusActive, Email: "webhook@example.com", CreatedAt: t0, UpdatedAt: now}
+		if err := m.HandleEvent(ctx, newEvent(t, "evt_1", "customer.updated", now, newer)); err != nil {
+			t.Fatal(err)
+		}
+		now = t0.Add(time.Hour)
+		if _, updateErr := client.Customers.Update(ctx, customerIds[2], &paddle.UpdateCustomerParams{Email: ptr("after@example.com")}); updateErr != nil {
+			t.Fatal(updateErr)
+		}
+		if err := m.Backfill(ctx); err != nil {
+			t.Fatalf("second Backfill() = %v", err)
+		}
+
+		for id, want := range map[string]string{
+			customerIds[0]: "c0@example.com",
+			customerIds[1]: "webhook@example.com",
+			customerIds[2]: "after@example.com",
+		} {
+			c, getErr := m.Customer(ctx, id)
+			if getErr != nil {
+				t.Fatal(getErr)
+			}
+			if c.Email != want {
+				t.Errorf("customer %s email = %q after resync, want %q", id, c.Email, want)
+			}
+		}
+	})
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,210 @@
+package mirror
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/texm/go-paddle"
+)
+
+type CustomerQuery struct {
+	Email  string
+	Status []paddle.Status
+}
+
+type AddressQuery struct {
+	CustomerId string
+	Status     []paddle.Status
+}
+
+type SubscriptionQuery struct {
+	CustomerId string
+	Status     []paddle.SubscriptionStatus
+	// NextBilledBefore matches subscriptions next billed before the time.
+	NextBilledBefore time.Time
+}
+
+type TransactionQuery struct {
+	CustomerId     string
+	SubscriptionId string
+	Status         []paddle.TransactionStatus
+	// BilledFrom and BilledTo bound billed_at, inclusive and exclusive.
+	BilledFrom time.Time
+	BilledTo   time.Time
+}
+
+func (m *Mirror) Customer(ctx context.Context, id string) (*paddle.Customer, error) {
+	return get(ctx, m, customers, id)
+}
+
+func (m *Mirror) Address(ctx context.Context, id string) (*paddle.Address, error) {
+	return get(ctx, m, addresses, id)
+}
+
+func (m *Mirror) Subscription(ctx context.Context, id string) (*paddle.Subscription, error) {
+	return get(ctx, m, subscriptions, id)
+}
+
+func (m *Mirror) Transaction(ctx context.Context, id string) (*paddle.Transaction, error) {
+	return get(ctx, m, transactions, id)
+}
+
+func (m *Mirror) Product(ctx context.Context, id string) (*paddle.Product, error) {
+	return get(ctx, m, products, id)
+}
+
+func (m *Mirror) Price(ctx context.Context, id string) (*paddle.Price, error) {
+	return get(ctx, m, prices, id)
+}
+
+func (m *Mirror) Customers(ctx context.Context, q CustomerQuery) ([]*paddle.Customer, error) {
+	w := m.where()
+	w.equal("email", q.Email)
+	in(w, "status", q.Status)
+	return list(ctx, m, customers, w)
+}
+
+func (m *Mirror) Addresses(ctx context.Context, q AddressQuery) ([]*paddle.Address, error) {
+	w := m.where()
+	w.equal("customer_id", q.CustomerId)
+	in(w, "status", q.Status)
+	return list(ctx, m, addresses, w)
+}
+
+func (m *Mirror) Subscriptions(ctx context.Context, q SubscriptionQuery) ([]*paddle.Subscription, error) {
+	w := m.where()
+	w.equal("customer_id", q.CustomerId)
+	in(w, "status", q.Status)
+	w.compare("next_billed_at", "<", q.NextBilledBefore)
+	return list(ctx, m, subscriptions, w)
+}
+
+func (m *Mirror) Transactions(ctx context.Context, q TransactionQuery) ([]*paddle.Transaction, error) {
+	w := m.where()
+	w.equal("customer_id", q.CustomerId)
+	w.equal("subscription_id", q.SubscriptionId)
+	in(w, "status", q.Status)
+	w.compare("billed_at", ">=", q.BilledFrom)
+	w.compare("billed_at", "<", q.BilledTo)
+	return list(ctx, m, transactions, w)
+}
+
+// Products returns the products, each with its prices.
+func (m *Mirror) Products(ctx context.Context, status ...paddle.Status) ([]*paddle.Product, error) {
+	w := m.where()
+	in(w, "status", status)
+	productList, listErr := list(ctx, m, products, w)
+	if listErr != nil {
+		return nil, listErr
+	}
+	for _, p := range productList {
+		pw := m.where()
+		pw.equal("product_id", p.Id)
+		productPrices, pricesErr := list(ctx, m, prices, pw)
+		if pricesErr != nil {
+			return nil, pricesErr
+		}
+		p.Prices = &productPrices
+	}
+	return productList, nil
+}
+
+func (m *Mirror) Prices(ctx context.Context, productId string, status ...paddle.Status) ([]*paddle.Price, error) {
+	w := m.where()
+	w.equal("product_id", productId)
+	in(w, "status", status)
+	return list(ctx, m, prices, w)
+}
+
+type where struct {
+	dialect    Dialect
+	conditions []string
+	args       []any
+}
+
+func (m *Mirror) where() *where {
+	return &where{dialect: m.dialect}
+}
+
+func (w *where) arg(v any) string {
+	if t, ok := v.(time.Time); ok {
+		v = w.dialect.timeValue(t)
+	}
+	w.args = append(w.args, v)
+	return w.dialect.placeholder(len(w.args))
+}
+
+func (w *where) equal(column string, value string) {
+	if value != "" {
+		w.conditions = append(w.conditions, column+" = "+w.arg(value))
+	}
+}
+
+func (w *where) compare(column string, op string, t time.Time) {
+	if !t.IsZero() {
+		w.conditions = append(w.conditions, column+" "+op+" "+w.arg(t))
+	}
+}
+
+func in[T ~string](w *where, column string, values []T) {
+	if len(values) == 0 {
+		return
+	}
+	placeholders := make([]string, len(values))
+	for i, v := range values {
+		placeholders[i] = w.arg(string(v))
+	}
+	w.conditions = append(w.conditions, fmt.Sprintf("%s IN (%s)", column, strings.Join(placeholders, ", ")))
+}
+
+func (w *where) String() string {
+	if len(w.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(w.conditions, " AND ")
+}
+
+func get[T any](ctx context.Context, m *Mirror, t *table[T], id string) (*T, error) {
+	var data []byte
+	row := m.db.QueryRowContext(ctx,
+		fmt.Sprintf("SELECT data FROM %s WHERE id = %s", t.name, m.dialect.placeholder(1)), id)
+	if scanErr := row.Scan(&data); scanErr != nil {
+		if errors.Is(scanErr, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s %s", ErrNotFound, t.resource, id)
+		}
+		return nil, scanErr
+	}
+	var item T
+	if jsonErr := json.Unmarshal(data, &item); jsonErr != nil {
+		return nil, jsonErr
+	}
+	return &item, nil
+}
+
+func list[T any](ctx context.Context, m *Mirror, t *table[T], w *where) ([]*T, error) {
+	rows, queryErr := m.db.QueryContext(ctx,
+		fmt.Sprintf("SELECT data FROM %s%s ORDER BY created_at, id", t.name, w), w.args...)
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	defer rows.Close()
+
+	items := []*T{}
+	for rows.Next() {
+		var data []byte
+		if scanErr := rows.Scan(&data); scanErr != nil {
+			return nil, scanErr
+		}
+		var item T
+		if jsonErr := json.Unmarshal(data, &item); jsonErr != nil {
+			return nil, jsonErr
+		}
+		items = append(items, &item)
+	}
+	return items, rows.Err()
+}
@@ -0,0 +1,234 @@
+package mirror
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/texm/go-paddle"
+)
+
+// Dialect adapts the mirror's SQL to a database. Register the matching
+// database/sql driver in the program; the mirror does not import one.
+type Dialect struct {
+	name        string
+	jsonType    string
+	timeType    string
+	placeholder func(n int) string
+	timeValue   func(t time.Time) any
+}
+
+// sqliteTimeLayout has a fixed width so that stored times sort as text.
+const sqliteTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+var (
+	SQLite = Dialect{
+		name:        "sqlite",
+		jsonType:    "TEXT",
+		timeType:    "TEXT",
+		placeholder: func(int) string { return "?" },
+		timeValue:   func(t time.Time) any { return t.UTC().Format(sqliteTimeLayout) },
+	}
+	Postgres = Dialect{
+		name:        "postgres",
+		jsonType:    "JSONB",
+		timeType:    "TIMESTAMPTZ",
+		placeholder: func(n int) string { return fmt.Sprintf("$%d", n) },
+		timeValue:   func(t time.Time) any { return t.UTC() },
+	}
+)
+
+func (d Dialect) String() string {
+	return d.name
+}
+
+type columnKind int
+
+const (
+	textColumn columnKind = iota
+	timeColumn
+)
+
+type column[T any] struct {
+	name  string
+	kind  columnKind
+	value func(*T) any
+}
+
+// table describes how a resource is stored: its id, the time used to order
+// writes, and the columns extracted from it for querying. The full resource
+// is kept as JSON in the data column.
+type table[T any] struct {
+	name      string
+	resource  string
+	id        func(*T) string
+	updatedAt func(*T) time.Time
+	columns   []column[T]
+	indexes   []string
+}
+
+func (t *table[T]) schema(d Dialect) []string {
+	defs := []string{"id TEXT PRIMARY KEY"}
+	for _, c := range t.columns {
+		typ := "TEXT"
+		if c.kind == timeColumn {
+			typ = d.timeType
+		}
+		defs = append(defs, c.name+" "+typ)
+	}
+	defs = append(defs, "version BIGINT NOT NULL", "data "+d.jsonType+" NOT NULL")
+
+	stmts := []string{fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n\t%s\n)", t.name, strings.Join(defs, ",\n\t"))}
+	for _, col := range t.indexes {
+		stmts = append(stmts, fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s_%s_idx ON %s (%s)", t.name, col, t.name, col))
+	}
+	return stmts
+}
+
+// upsert writes the row unless the stored version is newer, so events
+// delivered out of order never overwrite fresher state.
+func (t *table[T]) upsert(d Dialect) string {
+	names := []string{"id"}
+	for _, c := range t.columns {
+		names = append(names, c.name)
+	}
+	names = append(names, "version", "data")
+
+	placeholders := make([]string, len(names))
+	updates := make([]string, 0, len(names)-1)
+	for i, name := range names {
+		placeholders[i] = d.placeholder(i + 1)
+		if name != "id" {
+			updates = append(updates, fmt.Sprintf("%s = excluded.%s", name, name))
+		}
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)\nON CONFLICT (id) DO UPDATE SET %s\nWHERE %s.version <= excluded.version",
+		t.name, strings.Join(names, ", "), strings.Join(placeholders, ", "), strings.Join(updates, ", "), t.name)
+}
+
+func str(s *string) any {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
+
+func optionalTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return *t
+}
+
+var customers = &table[paddle.Customer]{
+	name:      "paddle_customers",
+	resource:  "customer",
+	id:        func(c *paddle.Customer) string { return c.Id },
+	updatedAt: func(c *paddle.Customer) time.Time { return c.UpdatedAt },
+	columns: []column[paddle.Customer]{
+		{"status", textColumn, func(c *paddle.Customer) any { return string(c.Status) }},
+		{"email", textColumn, func(c *paddle.Customer) any { return c.Email }},
+		{"created_at", timeColumn, func(c *paddle.Customer) any { return c.CreatedAt }},
+		{"updated_at", timeColumn, func(c *paddle.Customer) any { return c.UpdatedAt }},
+	},
+	indexes: []string{"email"},
+}
+
+var addresses = &table[paddle.Address]{
+	name:      "paddle_addresses",
+	resource:  "address",
+	id:        func(a *paddle.Address) string { return a.Id },
+	updatedAt: func(a *paddle.Address) time.Time { return a.UpdatedAt },
+	columns: []column[paddle.Address]{
+		{"status", textColumn, func(a *paddle.Address) any { return string(a.Status) }},
+		{"customer_id", textColumn, func(a *paddle.Address) any { return a.CustomerId }},
+		{"country_code", textColumn, func(a *paddle.Address) any { return a.CountryCode }},
+		{"created_at", timeColumn, func(a *paddle.Address) any { return a.CreatedAt }},
+		{"updated_at", timeColumn, func(a *paddle.Address) any { return a.UpdatedAt }},
+	},
+	indexes: []string{"customer_id"},
+}
+
+var subscriptions = &table[paddle.Subscription]{
+	name:      "paddle_subscriptions",
+	resource:  "subscription",
+	id:        func(s *paddle.Subscription) string { return s.Id },
+	updatedAt: func(s *paddle.Subscription) time.Time { return s.UpdatedAt },
+	columns: []column[paddle.Subscription]{
+		{"status", textColumn, func(s *paddle.Subscription) any { return string(s.Status) }},
+		{"customer_id", textColumn, func(s *paddle.Subscription) any { return s.CustomerId }},
+		{"currency_code", textColumn, func(s *paddle.Subscription) any { return string(s.CurrencyCode) }},
+		{"next_billed_at", timeColumn, func(s *paddle.Subscription) any { return optionalTime(s.NextBilledAt) }},
+		{"canceled_at", timeColumn, func(s *paddle.Subscription) any { return optionalTime(s.CanceledAt) }},
+		{"created_at", timeColumn, func(s *paddle.Subscription) any { return s.CreatedAt }},
+		{"updated_at", timeColumn, func(s *paddle.Subscription) any { return s.UpdatedAt }},
+	},
+	indexes: []string{"customer_id", "status"},
+}
+
+var transactions = &table[paddle.Transaction]{
+	name:      "paddle_transactions",
+	resource:  "transaction",
+	id:        func(t *paddle.Transaction) string { return t.Id },
+	updatedAt: func(t *paddle.Transaction) time.Time { return t.UpdatedAt },
+	columns: []column[paddle.Transaction]{
+		{"status", textColumn, func(t *paddle.Transaction) any { return string(t.Status) }},
+		{"customer_id", textColumn, func(t *paddle.Transaction) any { return str(t.CustomerId) }},
+		{"subscription_id", textColumn, func(t *paddle.Transaction) any { return str(t.SubscriptionId) }},
+		{"currency_code", textColumn, func(t *paddle.Transaction) any { return string(t.CurrencyCode) }},
+		{"billed_at", timeColumn, func(t *paddle.Transaction) any { return optionalTime(t.BilledAt) }},
+		{"created_at", timeColumn, func(t *paddle.Transaction) any { return t.CreatedAt }},
+		{"updated_at", timeColumn, func(t *paddle.Transaction) any { return t.UpdatedAt }},
+	},
+	indexes: []string{"customer_id", "subscription_id", "billed_at"},
+}
+
+var products = &table[paddle.Product]{
+	name:      "paddle_products",
+	resource:  "product",
+	id:        func(p *paddle.Product) string { return p.Id },
+	updatedAt: func(p *paddle.Product) time.Time { return p.UpdatedAt },
+	columns: []column[paddle.Product]{
+		{"status", textColumn, func(p *paddle.Product) any { return string(p.Status) }},
+		{"name", textColumn, func(p *paddle.Product) any { return p.Name }},
+		{"created_at", timeColumn, func(p *paddle.Product) any { return p.CreatedAt }},
+		{"updated_at", timeColumn, func(p *paddle.Product) any { return p.UpdatedAt }},
+	},
+}
+
+var prices = &table[paddle.Price]{
+	name:      "paddle_prices",
+	resource:  "price",
+	id:        func(p *paddle.Price) string { return p.Id },
+	updatedAt: func(p *paddle.Price) time.Time { return p.UpdatedAt },
+	columns: []column[paddle.Price]{
+		{"status", textColumn, func(p *paddle.Price) any { return string(p.Status) }},
+		{"product_id", textColumn, func(p *paddle.Price) any { return p.ProductId }},
+		{"created_at", timeColumn, func(p *paddle.Price) any { return p.CreatedAt }},
+		{"updated_at", timeColumn, func(p *paddle.Price) any { return p.UpdatedAt }},
+	},
+	indexes: []string{"product_id"},
+}
+
+const eventsTable = "paddle_events"
+
+func eventsSchema(d Dialect) string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	event_id TEXT PRIMARY KEY,
+	event_type TEXT NOT NULL,
+	occurred_at %s NOT NULL
+)`, eventsTable, d.timeType)
+}
+
+// Schema returns the statements Migrate runs, for use with an external
+// migration tool.
+func Schema(d Dialect) []string {
+	var stmts []string
+	stmts = append(stmts, customers.schema(d)...)
+	stmts = append(stmts, addresses.schema(d)...)
+	stmts = append(stmts, subscriptions.schema(d)...)
+	stmts = append(stmts, transactions.schema(d)...)
+	stmts = append(stmts, products.schema(d)...)
+	stmts = append(stmts, prices.schema(d)...)
+	return append(stmts, eventsSchema(d))
+}
@@ -0,0 +1,319 @@
+package paddle
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var (
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	ErrZeroDenominator  = errors.New("zero denominator")
+)
+
+type CurrencyCode string
+
+const (
+	CurrencyCodeUSD = CurrencyCode("USD")
+	CurrencyCodeEUR = CurrencyCode("EUR")
+	CurrencyCodeGBP = CurrencyCode("GBP")
+	CurrencyCodeJPY = CurrencyCode("JPY")
+	CurrencyCodeAUD = CurrencyCode("AUD")
+	CurrencyCodeCAD = CurrencyCode("CAD")
+	CurrencyCodeCHF = CurrencyCode("CHF")
+	CurrencyCodeHKD = CurrencyCode("HKD")
+	CurrencyCodeSGD = CurrencyCode("SGD")
+	CurrencyCodeSEK = CurrencyCode("SEK")
+	CurrencyCodeARS = CurrencyCode("ARS")
+	CurrencyCodeBRL = CurrencyCode("BRL")
+	CurrencyCodeCNY = CurrencyCode("CNY")
+	CurrencyCodeCOP = CurrencyCode("COP")
+	CurrencyCodeCZK = CurrencyCode("CZK")
+	CurrencyCodeDKK = CurrencyCode("DKK")
+	CurrencyCodeHUF = CurrencyCode("HUF")
+	CurrencyCodeILS = CurrencyCode("ILS")
+	CurrencyCodeINR = CurrencyCode("INR")
+	CurrencyCodeKRW = CurrencyCode("KRW")
+	CurrencyCodeMXN = CurrencyCode("MXN")
+	CurrencyCodeNOK = CurrencyCode("NOK")
+	CurrencyCodeNZD = CurrencyCode("NZD")
+	CurrencyCodePLN = CurrencyCode("PLN")
+	CurrencyCodeRUB = CurrencyCode("RUB")
+	CurrencyCodeTHB = CurrencyCode("THB")
+	CurrencyCodeTRY = CurrencyCode("TRY")
+	CurrencyCodeTWD = CurrencyCode("TWD")
+	CurrencyCodeUAH = CurrencyCode("UAH")
+	CurrencyCodeVND = CurrencyCode("VND")
+	CurrencyCodeZAR = CurrencyCode("ZAR")
+)
+
+// currencyExponents holds the ISO 4217 minor units of each active currency.
+var currencyExponents = map[CurrencyCode]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0,
+	"KRW": 0, "PYG": 0, "RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0,
+	"XAF": 0, "XOF": 0, "XPF": 0,
+	"AED": 2, "AFN": 2, "ALL": 2, "AMD": 2, "ANG": 2, "AOA": 2, "ARS": 2,
+	"AUD": 2, "AWG": 2, "AZN": 2, "BAM": 2, "BBD": 2, "BDT": 2, "BGN": 2,
+	"BMD": 2, "BND": 2, "BOB": 2, "BOV": 2, "BRL": 2, "BSD": 2, "BTN": 2,
+	"BWP": 2, "BYN": 2, "BZD": 2, "CAD": 2, "CDF": 2, "CHE": 2, "CHF": 2,
+	"CHW": 2, "CNY": 2, "COP": 2, "COU": 2, "CRC": 2, "CUP": 2, "CVE": 2,
+	"CZK": 2, "DKK": 2, "DOP": 2, "DZD": 2, "EGP": 2, "ERN": 2, "ETB": 2,
+	"EUR": 2, "FJD": 2, "FKP": 2, "GBP": 2, "GEL": 2, "GHS": 2, "GIP": 2,
+	"GMD": 2, "GTQ": 2, "GYD": 2, "HKD": 2, "HNL": 2, "HTG": 2, "HUF": 2,
+	"IDR": 2, "ILS": 2, "INR": 2, "IRR": 2, "JMD": 2, "KES": 2, "KGS": 2,
+	"KHR": 2, "KPW": 2, "KYD": 2, "KZT": 2, "LAK": 2, "LBP": 2, "LKR": 2,
+	"LRD": 2, "LSL": 2, "MAD": 2, "MDL": 2, "MGA": 2, "MKD": 2, "MMK": 2,
+	"MNT": 2, "MOP": 2, "MRU": 2, "MUR": 2, "MVR": 2, "MWK": 2, "MXN": 2,
+	"MXV": 2, "MYR": 2, "MZN": 2, "NAD": 2, "NGN": 2, "NIO": 2, "NOK": 2,
+	"NPR": 2, "NZD": 2, "PAB": 2, "PEN": 2, "PGK": 2, "PHP": 2, "PKR": 2,
+	"PLN": 2, "QAR": 2, "RON": 2, "RSD": 2, "RUB": 2, "SAR": 2, "SBD": 2,
+	"SCR": 2, "SDG": 2, "SEK": 2, "SGD": 2, "SHP": 2, "SLE": 2, "SOS": 2,
+	"SRD": 2, "SSP": 2, "STN": 2, "SVC": 2, "SYP": 2, "SZL": 2, "THB": 2,
+	"TJS": 2, "TMT": 2, "TOP": 2, "TRY": 2, "TTD": 2, "TWD": 2, "TZS": 2,
+	"UAH": 2, "USD": 2, "USN": 2, "UYU": 2, "UZS": 2, "VED": 2, "VES": 2,
+	"WST": 2, "XCD": 2, "XCG": 2, "YER": 2, "ZAR": 2, "ZMW": 2, "ZWG": 2,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"CLF": 4, "UYW": 4,
+}
+
+// Exponent returns the number of minor-unit digits for the currency, e.g. 2
+// for USD (cents), 0 for JPY and 3 for KWD. Codes missing from ISO 4217
+// are assumed to have 2.
+func (c CurrencyCode) Exponent() int {
+	if exp, ok := currencyExponents[CurrencyCode(strings.ToUpper(string(c)))]; ok {
+		return exp
+	}
+	return 2
+}
+
+// Amount is an exact decimal amount in the lowest denomination of a
+// currency, as used throughout the Paddle API. The zero value is 0.
+type Amount struct {
+	r *big.Rat
+}
+
+func NewAmount(minorUnits int64) Amount {
+	return Amount{r: new(big.Rat).SetInt64(minorUnits)}
+}
+
+func ParseAmount(s string) (Amount, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok || strings.ContainsAny(s, "/eE") {
+		return Amount{}, fmt.Errorf("invalid amount %q", s)
+	}
+	return Amount{r: r}, nil
+}
+
+func (a Amount) rat() *big.Rat {
+	if a.r == nil {
+		return new(big.Rat)
+	}
+	return a.r
+}
+
+func (a Amount) Add(b Amount) Amount {
+	return Amount{r: new(big.Rat).Add(a.rat(), b.rat())}
+}
+
+func (a Amount) Sub(b Amount) Amount {
+	return Amount{r: new(big.Rat).Sub(a.rat(), b.rat())}
+}
+
+func (a Amount) Mul(n int64) Amount {
+	return Amount{r: new(big.Rat).Mul(a.rat(), new(big.Rat).SetInt64(n))}
+}
+
+// Scale multiplies the amount by num/den, returning ErrZeroDenominator if
+// den is 0. The result may not be a whole number of minor units; use Round
+// for that.
+func (a Amount) Scale(num int64, den int64) (Amount, error) {
+	if den == 0 {
+		return Amount{}, ErrZeroDenominator
+	}
+	return Amount{r: new(big.Rat).Mul(a.rat(), big.NewRat(num, den))}, nil
+}
+
+func (a Amount) Neg() Amount {
+	return Amount{r: new(big.Rat).Neg(a.rat())}
+}
+
+func (a Amount) Cmp(b Amount) int {
+	return a.rat().Cmp(b.rat())
+}
+
+func (a Amount) Sign() int {
+	return a.rat().Sign()
+}
+
+func (a Amount) IsZero() bool {
+	return a.Sign() == 0
+}
+
+// Int64 returns the amount in minor units, reporting whether it is a whole
+// number of minor units which fits in an int64.
+func (a Amount) Int64() (int64, bool) {
+	r := a.rat()
+	if !r.IsInt() || !r.Num().IsInt64() {
+		return 0, false
+	}
+	return r.Num().Int64(), true
+}
+
+// Float64 returns the nearest float64 to the amount in minor units.
+func (a Amount) Float64() float64 {
+	f, _ := a.rat().Float64()
+	return f
+}
+
+// Round rounds the amount to a whole number of minor units, rounding half
+// to even.
+func (a Amount) Round() Amount {
+	r := a.rat()
+	if r.IsInt() {
+		return Amount{r: new(big.Rat).Set(r)}
+	}
+	quo, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	twiceRem := new(big.Int).Abs(rem)
+	twiceRem.Lsh(twiceRem, 1)
+	switch twiceRem.Cmp(r.Denom()) {
+	case 1:
+		quo.Add(quo, big.NewInt(int64(r.Sign())))
+	case 0:
+		if quo.Bit(0) == 1 {
+			quo.Add(quo, big.NewInt(int64(r.Sign())))
+		}
+	}
+	return Amount{r: new(big.Rat).SetInt(quo)}
+}
+
+// decimalPlaces returns the number of digits needed after the decimal point
+// to represent r exactly, or -1 if r has no finite decimal representation.
+func decimalPlaces(r *big.Rat) int {
+	d := new(big.Int).Set(r.Denom())
+	twos, fives := 0, 0
+	two, five, rem := big.NewInt(2), big.NewInt(5), new(big.Int)
+	for {
+		if q, m := new(big.Int).QuoRem(d, two, rem); m.Sign() == 0 {
+			d, twos = q, twos+1
+			continue
+		}
+		if q, m := new(big.Int).QuoRem(d, five, rem); m.Sign() == 0 {
+			d, fives = q, fives+1
+			continue
+		}
+		break
+	}
+	if d.Cmp(big.NewInt(1)) != 0 {
+		return -1
+	}
+	return max(twos, fives)
+}
+
+func formatRat(r *big.Rat, minPlaces int) string {
+	places := decimalPlaces(r)
+	if places < 0 {
+		places = 12
+	}
+	return r.FloatString(max(places, minPlaces))
+}
+
+// String returns the exact amount in minor units, e.g. "1050".
+func (a Amount) String() string {
+	return formatRat(a.rat(), 0)
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var n json.Number
+		if numErr := json.Unmarshal(data, &n); numErr != nil {
+			return fmt.Errorf("invalid amount %s", data)
+		}
+		s = n.String()
+	}
+	parsed, parseErr := ParseAmount(s)
+	if parseErr != nil {
+		return parseErr
+	}
+	*a = parsed
+	return nil
+}
+
+// Money is an amount in the lowest denomination of its currency.
+type Money struct {
+	Amount       Amount       `json:"amount"`
+	CurrencyCode CurrencyCode `json:"currency_code"`
+}
+
+func NewMoney(amount Amount, currency CurrencyCode) Money {
+	return Money{Amount: amount, CurrencyCode: currency}
+}
+
+// ParseMoney parses an amount given in major units, e.g. "10.50" USD.
+func ParseMoney(major string, currency CurrencyCode) (Money, error) {
+	amount, parseErr := ParseAmount(major)
+	if parseErr != nil {
+		return Money{}, parseErr
+	}
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(currency.Exponent())), nil))
+	return Money{
+		Amount:       Amount{r: new(big.Rat).Mul(amount.rat(), scale)},
+		CurrencyCode: currency,
+	}, nil
+}
+
+func (m Money) checkCurrency(o Money) error {
+	if !strings.EqualFold(string(m.CurrencyCode), string(o.CurrencyCode)) {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.CurrencyCode, o.CurrencyCode)
+	}
+	return nil
+}
+
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.checkCurrency(o); err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount.Add(o.Amount), CurrencyCode: m.CurrencyCode}, nil
+}
+
+func (m Money) Sub(o Money) (Money, error) {
+	if err := m.checkCurrency(o); err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount.Sub(o.Amount), CurrencyCode: m.CurrencyCode}, nil
+}
+
+func (m Money) Cmp(o Money) (int, error) {
+	if err := m.checkCurrency(o); err != nil {
+		return 0, err
+	}
+	return m.Amount.Cmp(o.Amount), nil
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount.IsZero()
+}
+
+// Major returns the amount in major units with at least as many decimal
+// places as the currency's exponent, e.g. "10.50" for 1050 USD.
+func (m Money) Major() string {
+	exp := m.CurrencyCode.Exponent()
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil))
+	return formatRat(new(big.Rat).Quo(m.Amount.rat(), scale), exp)
+}
+
+// String formats the amount in major units followed by the currency, e.g.
+// "10.50 USD".
+func (m Money) String() string {
+	return m.Major() + " " + string(m.CurrencyCode)
+}
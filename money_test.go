@@ -0,0 +1,205 @@
+package paddle
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func mustAmount(t *testing.T, s string) Amount {
+	t.Helper()
+	a, err := ParseAmount(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestAmountArithmetic(t *testing.T) {
+	a, b := mustAmount(t, "1050"), mustAmount(t, "0.5")
+	tests := []struct {
+		name string
+		got  Amount
+		want string
+	}{
+		{"add", a.Add(b), "1050.5"},
+		{"sub", b.Sub(a), "-1049.5"},
+		{"mul", a.Mul(3), "3150"},
+		{"neg", a.Neg(), "-1050"},
+		{"zero value", Amount{}.Add(NewAmount(7)), "7"},
+	}
+	for _, tt := range tests {
+		if got := tt.got.String(); got != tt.want {
+			t.Errorf("%s = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+	if a.Cmp(b) != 1 || b.Cmp(a) != -1 || a.Cmp(NewAmount(1050)) != 0 {
+		t.Errorf("Cmp() is inconsistent for %s and %s", a, b)
+	}
+	if !(Amount{}).IsZero() || a.Neg().Sign() != -1 {
+		t.Error("zero Amount is not zero or negated Amount is not negative")
+	}
+	if n, ok := a.Int64(); !ok || n != 1050 {
+		t.Errorf("Int64() = %d, %v, want 1050, true", n, ok)
+	}
+	if _, ok := b.Int64(); ok {
+		t.Error("Int64() of a fractional amount reported ok")
+	}
+}
+
+func TestAmountScale(t *testing.T) {
+	scaled, err := NewAmount(1000).Scale(1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := scaled.Round().String(); got != "333" {
+		t.Errorf("Scale(1, 3).Round() = %s, want 333", got)
+	}
+	if _, err := NewAmount(1000).Scale(1, 0); !errors.Is(err, ErrZeroDenominator) {
+		t.Errorf("Scale(1, 0) error = %v, want %v", err, ErrZeroDenominator)
+	}
+}
+
+func TestAmountRound(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"2.5", "2"},
+		{"3.5", "4"},
+		{"-2.5", "-2"},
+		{"-3.5", "-4"},
+		{"2.4999", "2"},
+		{"2.5001", "3"},
+		{"-2.6", "-3"},
+		{"7", "7"},
+	}
+	for _, tt := range tests {
+		if got := mustAmount(t, tt.in).Round().String(); got != tt.want {
+			t.Errorf("Round(%s) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	for _, in := range []string{"", "abc", "1/3", "1e3", "1.2.3"} {
+		if _, err := ParseAmount(in); err == nil {
+			t.Errorf("ParseAmount(%q) succeeded, want an error", in)
+		}
+	}
+	if got := mustAmount(t, " 12.50 ").String(); got != "12.5" {
+		t.Errorf("ParseAmount(\" 12.50 \") = %s, want 12.5", got)
+	}
+}
+
+func TestAmountJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{`"1050"`, `"1050"`},
+		{`1050`, `"1050"`},
+		{`"0.125"`, `"0.125"`},
+		{`"-99"`, `"-99"`},
+		{`"12345678901234567890123"`, `"12345678901234567890123"`},
+	}
+	for _, tt := range tests {
+		var a Amount
+		if err := json.Unmarshal([]byte(tt.in), &a); err != nil {
+			t.Fatalf("Unmarshal(%s) = %v", tt.in, err)
+		}
+		out, err := json.Marshal(a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != tt.want {
+			t.Errorf("round trip of %s = %s, want %s", tt.in, out, tt.want)
+		}
+	}
+
+	m := NewMoney(NewAmount(5), CurrencyCodeUSD)
+	if err := json.Unmarshal([]byte(`{"amount":null,"currency_code":"EUR"}`), &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.Amount.String() != "5" || m.CurrencyCode != CurrencyCodeEUR {
+		t.Errorf("Unmarshal() with a null amount = %s, want 5 EUR", m)
+	}
+	for _, in := range []string{`"abc"`, `true`, `"1e3"`} {
+		var a Amount
+		if err := json.Unmarshal([]byte(in), &a); err == nil {
+			t.Errorf("Unmarshal(%s) succeeded, want an error", in)
+		}
+	}
+}
+
+func TestCurrencyExponent(t *testing.T) {
+	tests := []struct {
+		currency CurrencyCode
+		want     int
+	}{
+		{CurrencyCodeUSD, 2},
+		{CurrencyCodeJPY, 0},
+		{"jpy", 0},
+		{"CLP", 0},
+		{"ISK", 0},
+		{"KWD", 3},
+		{"BHD", 3},
+		{"CLF", 4},
+		{"XXX", 2},
+	}
+	for _, tt := range tests {
+		if got := tt.currency.Exponent(); got != tt.want {
+			t.Errorf("%s.Exponent() = %d, want %d", tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestMoney(t *testing.T) {
+	tests := []struct {
+		major     string
+		currency  CurrencyCode
+		wantMinor string
+		wantMajor string
+	}{
+		{"10.50", CurrencyCodeUSD, "1050", "10.50"},
+		{"10", CurrencyCodeUSD, "1000", "10.00"},
+		{"1500", CurrencyCodeJPY, "1500", "1500"},
+		{"1.234", "KWD", "1234", "1.234"},
+		{"0.005", CurrencyCodeUSD, "0.5", "0.005"},
+		{"-3.2", CurrencyCodeEUR, "-320", "-3.20"},
+	}
+	for _, tt := range tests {
+		m, err := ParseMoney(tt.major, tt.currency)
+		if err != nil {
+			t.Fatalf("ParseMoney(%q, %s) = %v", tt.major, tt.currency, err)
+		}
+		if got := m.Amount.String(); got != tt.wantMinor {
+			t.Errorf("ParseMoney(%q, %s) = %s minor units, want %s", tt.major, tt.currency, got, tt.wantMinor)
+		}
+		if got := m.Major(); got != tt.wantMajor {
+			t.Errorf("Major() = %s, want %s", got, tt.wantMajor)
+		}
+	}
+	if _, err := ParseMoney("ten", CurrencyCodeUSD); err == nil {
+		t.Error("ParseMoney(\"ten\") succeeded, want an error")
+	}
+
+	usd := NewMoney(NewAmount(1050), CurrencyCodeUSD)
+	if got := usd.String(); got != "10.50 USD" {
+		t.Errorf("String() = %s, want 10.50 USD", got)
+	}
+	sum, err := usd.Add(NewMoney(NewAmount(50), "usd"))
+	if err != nil || sum.Amount.String() != "1100" {
+		t.Errorf("Add() = %s, %v, want 1100 minor units", sum, err)
+	}
+	diff, err := usd.Sub(NewMoney(NewAmount(2000), CurrencyCodeUSD))
+	if err != nil || diff.Major() != "-9.50" {
+		t.Errorf("Sub() = %s, %v, want -9.50 USD", diff, err)
+	}
+	if _, err := usd.Add(NewMoney(NewAmount(1), CurrencyCodeEUR)); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Add() across currencies = %v, want %v", err, ErrCurrencyMismatch)
+	}
+	if c, err := usd.Cmp(sum); err != nil || c != -1 {
+		t.Errorf("Cmp() = %d, %v, want -1", c, err)
+	}
+}
@@ -0,0 +1,261 @@
+// Package otelpaddle provides OpenTelemetry tracing and metrics for the
+// Paddle client and webhook handlers.
+//
+//	cfg := &paddle.Config{APIKey: apiKey}
+//	inst, err := otelpaddle.Instrument(cfg)
+//	if err != nil {
+//		return err
+//	}
+//	client := paddle.NewClient(cfg)
+//
+// Spans get their status and request ID, and requests are measured, only
+// when the Instrumentation is also installed as middleware, which
+// Instrument does.
+package otelpaddle
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/texm/go-paddle"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/texm/go-paddle/otelpaddle"
+
+const (
+	attrService        = attribute.Key("paddle.service")
+	attrMethod         = attribute.Key("paddle.method")
+	attrResourceId     = attribute.Key("paddle.resource_id")
+	attrRequestId      = attribute.Key("paddle.request_id")
+	attrErrorCode      = attribute.Key("paddle.error_code")
+	attrEventId        = attribute.Key("paddle.event_id")
+	attrEventType      = attribute.Key("paddle.event_type")
+	attrNotificationId = attribute.Key("paddle.notification_id")
+	attrHttpMethod     = attribute.Key("http.request.method")
+	attrHttpStatus     = attribute.Key("http.response.status_code")
+	attrUrlPath        = attribute.Key("url.path")
+)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+type Option func(*config)
+
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = mp
+	}
+}
+
+// Instrumentation implements paddle.Instrumentation, opening a span for each
+// service method call, and records request latency and error counts.
+type Instrumentation struct {
+	tracer trace.Tracer
+
+	requestDuration metric.Float64Histogram
+	requestErrors   metric.Int64Counter
+	webhookDuration metric.Float64Histogram
+	webhookErrors   metric.Int64Counter
+}
+
+var _ paddle.Instrumentation = (*Instrumentation)(nil)
+
+// New creates an Instrumentation without installing it. It must be set as
+// both Config.Instrumentation and a Config.Middleware entry; Instrument
+// does both.
+func New(opts ...Option) (*Instrumentation, error) {
+	cfg := &config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	meter := cfg.meterProvider.Meter(instrumentationName)
+	i := &Instrumentation{
+		tracer: cfg.tracerProvider.Tracer(instrumentationName),
+	}
+
+	var err, metricErr error
+	i.requestDuration, metricErr = meter.Float64Histogram("paddle.client.request.duration",
+		metric.WithDescription("Duration of Paddle API requests."),
+		metric.WithUnit("s"))
+	err = errors.Join(err, metricErr)
+	i.requestErrors, metricErr = meter.Int64Counter("paddle.client.request.errors",
+		metric.WithDescription("Number of failed Paddle API requests."),
+		metric.WithUnit("{request}"))
+	err = errors.Join(err, metricErr)
+	i.webhookDuration, metricErr = meter.Float64Histogram("paddle.webhook.duration",
+		metric.WithDescription("Duration of Paddle webhook verification and handling."),
+		metric.WithUnit("s"))
+	err = errors.Join(err, metricErr)
+	i.webhookErrors, metricErr = meter.Int64Counter("paddle.webhook.errors",
+		metric.WithDescription("Number of Paddle webhooks that failed verification or handling."),
+		metric.WithUnit("{notification}"))
+	err = errors.Join(err, metricErr)
+	if err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
+
+// Instrument creates an Instrumentation and installs it on cfg, both to
+// observe service method calls and as the outermost middleware.
+func Instrument(cfg *paddle.Config, opts ...Option) (*Instrumentation, error) {
+	i, err := New(opts...)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Instrumentation = i
+	cfg.Middleware = append([]paddle.Middleware{i.Middleware()}, cfg.Middleware...)
+	return i, nil
+}
+
+func operationAttributes(op paddle.Operation) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attrService.String(op.Service),
+		attrMethod.String(op.Method),
+	}
+	if op.ResourceId != "" {
+		attrs = append(attrs, attrResourceId.String(op.ResourceId))
+	}
+	return attrs
+}
+
+func errorAttributes(err error) []attribute.KeyValue {
+	var apiErr *paddle.ApiError
+	if !errors.As(err, &apiErr) {
+		return nil
+	}
+	attrs := []attribute.KeyValue{attrErrorCode.String(string(apiErr.Code))}
+	if apiErr.StatusCode != 0 {
+		attrs = append(attrs, attrHttpStatus.Int(apiErr.StatusCode))
+	}
+	if apiErr.RequestId != "" {
+		attrs = append(attrs, attrRequestId.String(apiErr.RequestId))
+	}
+	return attrs
+}
+
+func (i *Instrumentation) StartOperation(ctx context.Context, op paddle.Operation) (context.Context, func(err error)) {
+	ctx, span := i.tracer.Start(ctx, op.Name(),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(operationAttributes(op)...))
+	return ctx, func(err error) {
+		if err != nil {
+			span.SetAttributes(errorAttributes(err)...)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
+
+// Middleware records the latency and outcome of every request, and
+// annotates the current operation span with the HTTP status code and
+// Paddle request ID.
+func (i *Instrumentation) Middleware() paddle.Middleware {
+	return func(next paddle.Handler) paddle.Handler {
+		return func(ctx context.Context, req *http.Request) (*paddle.ApiResponse, error) {
+			start := time.Now()
+			res, err := next(ctx, req)
+			elapsed := time.Since(start).Seconds()
+
+			attrs := []attribute.KeyValue{attrHttpMethod.String(req.Method)}
+			if op, ok := paddle.OperationFromContext(ctx); ok {
+				attrs = append(attrs, attrService.String(op.Service), attrMethod.String(op.Method))
+			}
+			if res != nil {
+				attrs = append(attrs, attrHttpStatus.Int(res.StatusCode))
+			}
+
+			eventAttrs := []attribute.KeyValue{attrUrlPath.String(req.URL.Path)}
+			if res != nil {
+				eventAttrs = append(eventAttrs, attrHttpStatus.Int(res.StatusCode))
+				if res.Meta.RequestId != "" {
+					eventAttrs = append(eventAttrs, attrRequestId.String(res.Meta.RequestId))
+				}
+			}
+
+			span := trace.SpanFromContext(ctx)
+			span.SetAttributes(eventAttrs...)
+			span.AddEvent("paddle.request", trace.WithAttributes(eventAttrs...))
+
+			i.requestDuration.Record(ctx, elapsed, metric.WithAttributes(attrs...))
+			if err != nil {
+				var apiErr *paddle.ApiError
+				if errors.As(err, &apiErr) {
+					attrs = append(attrs, attrErrorCode.String(string(apiErr.Code)))
+				}
+				i.requestErrors.Add(ctx, 1, metric.WithAttributes(attrs...))
+			}
+			return res, err
+		}
+	}
+}
+
+// WebhookHandler returns an http.Handler which verifies webhooks with
+// client.ParseWebhook and dispatches them to handle. Verification and
+// handling are traced as separate spans, both carrying the event's
+// notification ID.
+func (i *Instrumentation) WebhookHandler(client *paddle.Client, handle func(ctx context.Context, event *paddle.WebhookEvent) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ctx, span := i.tracer.Start(r.Context(), "paddle.webhook.receive",
+			trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		event, parseErr := client.ParseWebhook(r.WithContext(ctx))
+		if parseErr != nil {
+			span.RecordError(parseErr)
+			span.SetStatus(codes.Error, parseErr.Error())
+			i.webhookErrors.Add(ctx, 1)
+			http.Error(w, "invalid webhook", http.StatusBadRequest)
+			return
+		}
+
+		eventAttrs := []attribute.KeyValue{
+			attrEventId.String(event.Id),
+			attrEventType.String(event.Type),
+			attrNotificationId.String(event.NotificationId),
+		}
+		span.SetAttributes(eventAttrs...)
+
+		handleCtx, handleSpan := i.tracer.Start(ctx, "paddle.webhook.handle "+event.Type,
+			trace.WithSpanKind(trace.SpanKindInternal),
+			trace.WithAttributes(eventAttrs...))
+		handleErr := handle(handleCtx, event)
+		if handleErr != nil {
+			handleSpan.RecordError(handleErr)
+			handleSpan.SetStatus(codes.Error, handleErr.Error())
+		}
+		handleSpan.End()
+
+		metricAttrs := metric.WithAttributes(attrEventType.String(event.Type))
+		i.webhookDuration.Record(ctx, time.Since(start).Seconds(), metricAttrs)
+		if handleErr != nil {
+			span.SetStatus(codes.Error, handleErr.Error())
+			i.webhookErrors.Add(ctx, 1, metricAttrs)
+			http.Error(w, "failed to handle webhook", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
@@ -0,0 +1,233 @@
+package otelpaddle
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/texm/go-paddle"
+	"github.com/texm/go-paddle/paddletest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type recorder struct {
+	spans   *tracetest.SpanRecorder
+	metrics *sdkmetric.ManualReader
+}
+
+func newRecorder() (*recorder, []Option) {
+	r := &recorder{spans: tracetest.NewSpanRecorder(), metrics: sdkmetric.NewManualReader()}
+	return r, []Option{
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(r.spans))),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(r.metrics))),
+	}
+}
+
+// collect returns the recorded metrics by name.
+func (r *recorder) collect(t *testing.T) map[string]metricdata.Aggregation {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := r.metrics.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	metrics := map[string]metricdata.Aggregation{}
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+	return metrics
+}
+
+// histogramCount returns the number of values a histogram recorded with
+// the attribute set.
+func histogramCount(metrics map[string]metricdata.Aggregation, name string, kv attribute.KeyValue) uint64 {
+	h, _ := metrics[name].(metricdata.Histogram[float64])
+	var count uint64
+	for _, dp := range h.DataPoints {
+		if v, ok := dp.Attributes.Value(kv.Key); ok && v == kv.Value {
+			count += dp.Count
+		}
+	}
+	return count
+}
+
+// counterSum returns a counter's total with the attribute set.
+func counterSum(metrics map[string]metricdata.Aggregation, name string, kv attribute.KeyValue) int64 {
+	s, _ := metrics[name].(metricdata.Sum[int64])
+	var sum int64
+	for _, dp := range s.DataPoints {
+		if v, ok := dp.Attributes.Value(kv.Key); ok && v == kv.Value {
+			sum += dp.Value
+		}
+	}
+	return sum
+}
+
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestInstrument(t *testing.T) {
+	rec, opts := newRecorder()
+	srv := paddletest.NewServer()
+	t.Cleanup(srv.Close)
+	customer := srv.AddCustomer(paddle.Customer{Email: "ada@example.com"})
+
+	// Instrument keeps the middleware already configured.
+	calls := 0
+	cfg := &paddle.Config{Middleware: []paddle.Middleware{func(next paddle.Handler) paddle.Handler {
+		return func(ctx context.Context, req *http.Request) (*paddle.ApiResponse, error) {
+			calls++
+			return next(ctx, req)
+		}
+	}}}
+	inst, instErr := Instrument(cfg, opts...)
+	if instErr != nil {
+		t.Fatal(instErr)
+	}
+	if cfg.Instrumentation != inst || len(cfg.Middleware) != 2 {
+		t.Fatalf("Instrument() installed %v with %d middleware, want the instrumentation and 2", cfg.Instrumentation, len(cfg.Middleware))
+	}
+	client := srv.PaddleClient(cfg)
+
+	ctx := context.Background()
+	if _, err := client.Customers.Get(ctx, customer.Id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Customers.Get(ctx, "ctm_missing"); !errors.Is(err, paddle.ErrNotFound) {
+		t.Fatalf("Get() = %v, want not found", err)
+	}
+	if calls != 2 {
+		t.Errorf("configured middleware called %d times, want 2", calls)
+	}
+
+	spans := rec.spans.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2", len(spans))
+	}
+	for i, span := range spans {
+		if span.Name() != "paddle.customers.get" || span.SpanKind() != trace.SpanKindClient {
+			t.Errorf("span %d = %s of kind %s, want paddle.customers.get of kind client", i, span.Name(), span.SpanKind())
+		}
+		if v, ok := spanAttribute(span, attrRequestId); !ok || v.AsString() == "" {
+			t.Errorf("span %d has no request ID", i)
+		}
+	}
+	if id, _ := spanAttribute(spans[0], attrResourceId); id.AsString() != customer.Id || spans[0].Status().Code != codes.Unset {
+		t.Errorf("first span for %s has status %v, want %s and unset", id.AsString(), spans[0].Status(), customer.Id)
+	}
+	failed := spans[1]
+	if failed.Status().Code != codes.Error || len(failed.Events()) == 0 {
+		t.Errorf("failed span has status %v and %d events, want an error", failed.Status(), len(failed.Events()))
+	}
+	if code, _ := spanAttribute(failed, attrErrorCode); code.AsString() != string(paddle.ErrorCodeNotFound) {
+		t.Errorf("failed span error code = %q, want not_found", code.AsString())
+	}
+	if status, _ := spanAttribute(failed, attrHttpStatus); status.AsInt64() != http.StatusNotFound {
+		t.Errorf("failed span status code = %d, want 404", status.AsInt64())
+	}
+
+	metrics := rec.collect(t)
+	get := attrMethod.String("get")
+	if n := histogramCount(metrics, "paddle.client.request.duration", get); n != 2 {
+		t.Errorf("request duration recorded %d times, want 2", n)
+	}
+	if n := counterSum(metrics, "paddle.client.request.errors", attrErrorCode.String(string(paddle.ErrorCodeNotFound))); n != 1 {
+		t.Errorf("request errors = %d, want 1", n)
+	}
+}
+
+func TestWebhookHandler(t *testing.T) {
+	const secret = "pdl_ntfset_test"
+	tests := []struct {
+		name       string
+		secret     string
+		handleErr  error
+		wantStatus int
+		wantSpans  []string
+	}{
+		{"handled", secret, nil, http.StatusOK, []string{"paddle.webhook.handle customer.created", "paddle.webhook.receive"}},
+		{"handler failed", secret, errors.New("database unavailable"), http.StatusInternalServerError, []string{"paddle.webhook.handle customer.created", "paddle.webhook.receive"}},
+		{"wrong secret", "pdl_ntfset_other", nil, http.StatusBadRequest, []string{"paddle.webhook.receive"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, opts := newRecorder()
+			inst, instErr := New(opts...)
+			if instErr != nil {
+				t.Fatal(instErr)
+			}
+			client := paddle.NewClient(&paddle.Config{WebhookSecretKey: secret})
+			var handled *paddle.WebhookEvent
+			handler := inst.WebhookHandler(client, func(_ context.Context, event *paddle.WebhookEvent) error {
+				handled = event
+				return tt.handleErr
+			})
+
+			req, reqErr := (&paddle.WebhookRequest{
+				Secret:    tt.secret,
+				EventType: paddle.EventTypeCustomerCreated,
+				Data:      paddle.Customer{Id: "ctm_1"},
+			}).Build(context.Background(), "http://localhost/webhooks")
+			if reqErr != nil {
+				t.Fatal(reqErr)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			spans := rec.spans.Ended()
+			if len(spans) != len(tt.wantSpans) {
+				t.Fatalf("recorded %d spans, want %v", len(spans), tt.wantSpans)
+			}
+			for i, span := range spans {
+				if span.Name() != tt.wantSpans[i] {
+					t.Errorf("span %d = %s, want %s", i, span.Name(), tt.wantSpans[i])
+				}
+				if handled != nil {
+					if id, _ := spanAttribute(span, attrNotificationId); id.AsString() != handled.NotificationId {
+						t.Errorf("span %s notification ID = %q, want %q", span.Name(), id.AsString(), handled.NotificationId)
+					}
+				}
+				if wantErr := tt.wantStatus != http.StatusOK; (span.Status().Code == codes.Error) != wantErr {
+					t.Errorf("span %s status = %v, want error %v", span.Name(), span.Status(), wantErr)
+				}
+			}
+
+			metrics := rec.collect(t)
+			eventType := attrEventType.String(paddle.EventTypeCustomerCreated)
+			if tt.wantStatus == http.StatusBadRequest {
+				if s, _ := metrics["paddle.webhook.errors"].(metricdata.Sum[int64]); len(s.DataPoints) != 1 || s.DataPoints[0].Value != 1 {
+					t.Errorf("webhook errors = %+v, want 1", s.DataPoints)
+				}
+				return
+			}
+			if n := histogramCount(metrics, "paddle.webhook.duration", eventType); n != 1 {
+				t.Errorf("webhook duration recorded %d times, want 1", n)
+			}
+			wantErrors := int64(0)
+			if tt.handleErr != nil {
+				wantErrors = 1
+			}
+			if n := counterSum(metrics, "paddle.webhook.errors", eventType); n != wantErrors {
+				t.Errorf("webhook errors = %d, want %d", n, wantErrors)
+			}
+		})
+	}
+}
@@ -7,10 +7,16 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 )
 
+//go:generate go run ./internal/cmd/paddlegen -spec openapi/paddle.json -overlay openapi/overlay.json -out zz_generated.go
+//go:generate go run ./internal/cmd/paddlemock -out paddlemock/zz_generated.go api.go zz_generated.go
+
 const (
 	apiBaseURL        = "https://api.paddle.com/"
 	sandboxApiBaseURL = "https://sandbox-api.paddle.com/"
@@ -20,22 +26,61 @@ type Config struct {
 	Sandbox    bool
 	HttpClient *http.Client
 
+	// BaseURL overrides the API endpoint selected by Sandbox, e.g. to use a
+	// mock server or proxy. It may include a path prefix.
+	BaseURL string
+
+	// UserAgent is appended to the library's User-Agent to identify the
+	// application, e.g. "billing-service/1.2".
+	UserAgent string
+
+	// Headers are sent with every request.
+	Headers http.Header
+
 	APIKey           string
 	WebhookSecretKey string
+
+	// WebhookTolerance is how far a webhook's signature timestamp may be
+	// from the current time, defaulting to DefaultWebhookTolerance. A
+	// negative value disables the check, e.g. to replay stored deliveries.
+	WebhookTolerance time.Duration
+
+	// APIVersion is sent as the Paddle-Version header on every request,
+	// defaulting to DefaultAPIVersion. Use WithAPIVersion to override it
+	// for a single call.
+	APIVersion int
+
+	// Middleware wraps every API call, the first entry being the outermost.
+	Middleware []Middleware
+
+	// Logger receives request, pagination and webhook logs. Secrets such as
+	// the Authorization header are redacted.
+	Logger *slog.Logger
+
+	// Instrumentation, if set, observes every service method call.
+	Instrumentation Instrumentation
+
+	// MaxRetries enables retrying failed requests. Mutating requests are
+	// given an idempotency key when one is not set with WithIdempotencyKey.
+	MaxRetries int
 }
 
 type Client struct {
 	client     *http.Client
 	cfg        *Config
 	baseURL    string
+	userAgent  string
 	apiKey     string
 	webhookKey []byte
+	apiVersion int
+	handler    Handler
+	logger     *slog.Logger
 
-	Customers     *CustomersService
-	Subscriptions *SubscriptionsService
-	Products      *ProductsService
-	Prices        *PricesService
-	Transactions  *TransactionsService
+	instrumentation  Instrumentation
+	maxRetries       int
+	webhookTolerance time.Duration
+
+	generatedServices
 }
 
 type service struct {
@@ -52,21 +97,45 @@ func NewClient(cfg *Config) *Client {
 		cfg:        cfg,
 		apiKey:     cfg.APIKey,
 		webhookKey: []byte(cfg.WebhookSecretKey),
+		apiVersion: cfg.APIVersion,
+		logger:     newLogger(cfg.Logger),
+
+		instrumentation: cfg.Instrumentation,
+		maxRetries:      cfg.MaxRetries,
+	}
+
+	if c.apiVersion == 0 {
+		c.apiVersion = DefaultAPIVersion
+	}
+	c.webhookTolerance = cfg.WebhookTolerance
+	if c.webhookTolerance == 0 {
+		c.webhookTolerance = DefaultWebhookTolerance
 	}
 
-	if cfg.Sandbox {
+	switch {
+	case cfg.BaseURL != "":
+		c.baseURL = strings.TrimSuffix(cfg.BaseURL, "/") + "/"
+	case cfg.Sandbox:
 		c.baseURL = sandboxApiBaseURL
-	} else {
+	default:
 		c.baseURL = apiBaseURL
 	}
 
-	s := &service{client: c}
+	c.userAgent = "go-paddle/" + Version
+	if cfg.UserAgent != "" {
+		c.userAgent += " " + cfg.UserAgent
+	}
+
+	middleware := cfg.Middleware[:len(cfg.Middleware):len(cfg.Middleware)]
+	if cfg.MaxRetries > 0 {
+		middleware = append(middleware, RetryMiddleware(cfg.MaxRetries))
+	}
+	if cfg.Logger != nil {
+		middleware = append(middleware, loggingMiddleware(c.logger))
+	}
+	c.handler = chainMiddleware(c.Do, middleware)
 
-	c.Customers = (*CustomersService)(s)
-	c.Subscriptions = (*SubscriptionsService)(s)
-	c.Products = (*ProductsService)(s)
-	c.Prices = (*PricesService)(s)
-	c.Transactions = (*TransactionsService)(s)
+	c.generatedServices.init(&service{client: c})
 
 	return c
 }
@@ -76,7 +145,7 @@ func (c *Client) TestAuthentication(ctx context.Context) error {
 	if reqErr != nil {
 		return reqErr
 	}
-	_, resErr := c.Do(ctx, req)
+	_, resErr := c.handler(ctx, req)
 	if resErr != nil {
 		return resErr
 	}
@@ -104,7 +173,14 @@ func (c *Client) NewRequest(method string, path string, body any) (*http.Request
 		return nil, reqErr
 	}
 
+	for key, values := range c.cfg.Headers {
+		for _, v := range values {
+			req.Header.Add(key, v)
+		}
+	}
+	req.Header.Set("User-Agent", c.userAgent)
 	req.Header.Set("Authorization", "Bearer "+c.cfg.APIKey)
+	setAPIVersion(req, c.apiVersion)
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
@@ -124,30 +200,19 @@ type ApiResponseMeta struct {
 	Pagination ApiResponseMetaPagination `json:"pagination,omitempty"`
 }
 
-type ErrorType string
-
-const (
-	ErrorTypeRequest = ErrorType("request_error")
-	ErrorTypeApi     = ErrorType("api_error")
-)
-
-type ApiError struct {
-	res *http.Response
-
-	Type             ErrorType `json:"type"`
-	Code             string    `json:"code"`
-	Detail           string    `json:"detail"`
-	DocumentationUrl string    `json:"documentation_url"`
-}
-
 type ApiResponse struct {
 	Data  json.RawMessage `json:"data"`
 	Error *ApiError       `json:"error,omitempty"`
 	Meta  ApiResponseMeta `json:"meta"`
+
+	StatusCode int `json:"-"`
 }
 
+// Do sends the request and decodes the response, bypassing any configured
+// middleware. When Paddle returns an error, both the decoded response and
+// the *ApiError are returned.
 func (c *Client) Do(ctx context.Context, req *http.Request) (*ApiResponse, error) {
-	resp, respErr := c.client.Do(req)
+	resp, respErr := c.client.Do(req.WithContext(ctx))
 
 	// HTTP status codes do not contribute to a response error
 	if respErr != nil {
@@ -163,9 +228,9 @@ func (c *Client) Do(ctx context.Context, req *http.Request) (*ApiResponse, error
 		if errors.As(respErr, &urlErr) {
 			if parsedUrl, parseErr := url.Parse(urlErr.URL); parseErr == nil {
 				urlErr.URL = parsedUrl.String()
-				err = errors.Join(err, urlErr)
 			}
 		}
+		err = errors.Join(err, respErr)
 
 		return nil, err
 	}
@@ -176,36 +241,18 @@ func (c *Client) Do(ctx context.Context, req *http.Request) (*ApiResponse, error
 		return nil, readErr
 	}
 
-	res := &ApiResponse{}
+	res := &ApiResponse{StatusCode: resp.StatusCode}
 	if jsonErr := json.Unmarshal(data, res); jsonErr != nil {
+		if resp.StatusCode >= http.StatusBadRequest {
+			return res, newApiError(resp, nil, "")
+		}
 		return nil, fmt.Errorf("http %d: failed to read response: %w", resp.StatusCode, jsonErr)
 	}
 
-	if res.Error != nil {
-		res.Error.res = resp
-		return nil, res.Error
+	if res.Error != nil || resp.StatusCode >= http.StatusBadRequest {
+		res.Error = newApiError(resp, res.Error, res.Meta.RequestId)
+		return res, res.Error
 	}
 
 	return res, nil
 }
-
-func (e *ApiError) Error() string {
-	res := e.res
-	return fmt.Sprintf("[%v %v] HTTP %d '%s': %s",
-		res.Request.Method, res.Request.URL.Path, res.StatusCode, e.Code, e.Detail)
-}
-
-/*
-func checkError(r *http.Response, data []byte) error {
-	if r.StatusCode < http.StatusBadRequest {
-		return nil
-	}
-	errRes := &ErrorResponse{response: r}
-	if data != nil {
-		if jsonErr := json.Unmarshal(data, errRes); jsonErr != nil {
-			return jsonErr
-		}
-	}
-	return errRes
-}
-*/
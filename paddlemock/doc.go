@@ -0,0 +1,10 @@
+// Package paddlemock provides mocks of the paddle API interfaces for tests.
+// Set the Func field of each method the code under test calls:
+//
+//	mock := &paddlemock.Client{Customers: &paddlemock.Customers{
+//		GetFunc: func(ctx context.Context, id string) (*paddle.Customer, error) {
+//			return &paddle.Customer{Id: id}, nil
+//		},
+//	}}
+//	svc := NewBillingService(mock) // accepts paddle.API
+package paddlemock
@@ -0,0 +1,408 @@
+// Code generated by paddlemock from api.go, zz_generated.go. DO NOT EDIT.
+
+package paddlemock
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/texm/go-paddle"
+)
+
+// Client is a mock of paddle.API. Calling a method whose Func field is
+// nil panics.
+type Client struct {
+	Addresses              *Addresses
+	Adjustments            *Adjustments
+	Businesses             *Businesses
+	Customers              *Customers
+	Discounts              *Discounts
+	Prices                 *Prices
+	Products               *Products
+	Subscriptions          *Subscriptions
+	Transactions           *Transactions
+	TestAuthenticationFunc func(ctx context.Context) error
+	ParseWebhookFunc       func(req *http.Request) (*paddle.WebhookEvent, error)
+}
+
+var _ paddle.API = (*Client)(nil)
+
+func (m *Client) AddressesAPI() paddle.AddressesAPI {
+	if m.Addresses == nil {
+		m.Addresses = &Addresses{}
+	}
+	return m.Addresses
+}
+
+func (m *Client) AdjustmentsAPI() paddle.AdjustmentsAPI {
+	if m.Adjustments == nil {
+		m.Adjustments = &Adjustments{}
+	}
+	return m.Adjustments
+}
+
+func (m *Client) BusinessesAPI() paddle.BusinessesAPI {
+	if m.Businesses == nil {
+		m.Businesses = &Businesses{}
+	}
+	return m.Businesses
+}
+
+func (m *Client) CustomersAPI() paddle.CustomersAPI {
+	if m.Customers == nil {
+		m.Customers = &Customers{}
+	}
+	return m.Customers
+}
+
+func (m *Client) DiscountsAPI() paddle.DiscountsAPI {
+	if m.Discounts == nil {
+		m.Discounts = &Discounts{}
+	}
+	return m.Discounts
+}
+
+func (m *Client) PricesAPI() paddle.PricesAPI {
+	if m.Prices == nil {
+		m.Prices = &Prices{}
+	}
+	return m.Prices
+}
+
+func (m *Client) ProductsAPI() paddle.ProductsAPI {
+	if m.Products == nil {
+		m.Products = &Products{}
+	}
+	return m.Products
+}
+
+func (m *Client) SubscriptionsAPI() paddle.SubscriptionsAPI {
+	if m.Subscriptions == nil {
+		m.Subscriptions = &Subscriptions{}
+	}
+	return m.Subscriptions
+}
+
+func (m *Client) TransactionsAPI() paddle.TransactionsAPI {
+	if m.Transactions == nil {
+		m.Transactions = &Transactions{}
+	}
+	return m.Transactions
+}
+
+func (m *Client) TestAuthentication(ctx context.Context) error {
+	if m.TestAuthenticationFunc == nil {
+		panic("paddlemock: Client.TestAuthentication called but TestAuthenticationFunc is not set")
+	}
+	return m.TestAuthenticationFunc(ctx)
+}
+
+func (m *Client) ParseWebhook(req *http.Request) (*paddle.WebhookEvent, error) {
+	if m.ParseWebhookFunc == nil {
+		panic("paddlemock: Client.ParseWebhook called but ParseWebhookFunc is not set")
+	}
+	return m.ParseWebhookFunc(req)
+}
+
+// Addresses is a mock of paddle.AddressesAPI. Calling a method whose Func field is
+// nil panics.
+type Addresses struct {
+	ListFunc func(ctx context.Context, customerId string, params *paddle.ListAddressesParams) ([]*paddle.Address, error)
+	GetFunc  func(ctx context.Context, customerId string, id string) (*paddle.Address, error)
+}
+
+var _ paddle.AddressesAPI = (*Addresses)(nil)
+
+func (m *Addresses) List(ctx context.Context, customerId string, params *paddle.ListAddressesParams) ([]*paddle.Address, error) {
+	if m.ListFunc == nil {
+		panic("paddlemock: Addresses.List called but ListFunc is not set")
+	}
+	return m.ListFunc(ctx, customerId, params)
+}
+
+func (m *Addresses) Get(ctx context.Context, customerId string, id string) (*paddle.Address, error) {
+	if m.GetFunc == nil {
+		panic("paddlemock: Addresses.Get called but GetFunc is not set")
+	}
+	return m.GetFunc(ctx, customerId, id)
+}
+
+// Adjustments is a mock of paddle.AdjustmentsAPI. Calling a method whose Func field is
+// nil panics.
+type Adjustments struct {
+	ListFunc func(ctx context.Context, params *paddle.ListAdjustmentsParams) ([]*paddle.Adjustment, error)
+}
+
+var _ paddle.AdjustmentsAPI = (*Adjustments)(nil)
+
+func (m *Adjustments) List(ctx context.Context, params *paddle.ListAdjustmentsParams) ([]*paddle.Adjustment, error) {
+	if m.ListFunc == nil {
+		panic("paddlemock: Adjustments.List called but ListFunc is not set")
+	}
+	return m.ListFunc(ctx, params)
+}
+
+// Businesses is a mock of paddle.BusinessesAPI. Calling a method whose Func field is
+// nil panics.
+type Businesses struct {
+	ListFunc func(ctx context.Context, customerId string, params *paddle.ListBusinessesParams) ([]*paddle.Business, error)
+	GetFunc  func(ctx context.Context, customerId string, id string) (*paddle.Business, error)
+}
+
+var _ paddle.BusinessesAPI = (*Businesses)(nil)
+
+func (m *Businesses) List(ctx context.Context, customerId string, params *paddle.ListBusinessesParams) ([]*paddle.Business, error) {
+	if m.ListFunc == nil {
+		panic("paddlemock: Businesses.List called but ListFunc is not set")
+	}
+	return m.ListFunc(ctx, customerId, params)
+}
+
+func (m *Businesses) Get(ctx context.Context, customerId string, id string) (*paddle.Business, error) {
+	if m.GetFunc == nil {
+		panic("paddlemock: Businesses.Get called but GetFunc is not set")
+	}
+	return m.GetFunc(ctx, customerId, id)
+}
+
+// Customers is a mock of paddle.CustomersAPI. Calling a method whose Func field is
+// nil panics.
+type Customers struct {
+	ListFunc   func(ctx context.Context, params *paddle.ListCustomersParams) ([]*paddle.Customer, error)
+	CreateFunc func(ctx context.Context, params *paddle.CreateCustomerParams) (*paddle.Customer, error)
+	GetFunc    func(ctx context.Context, id string) (*paddle.Customer, error)
+	UpdateFunc func(ctx context.Context, id string, params *paddle.UpdateCustomerParams) (*paddle.Customer, error)
+}
+
+var _ paddle.CustomersAPI = (*Customers)(nil)
+
+func (m *Customers) List(ctx context.Context, params *paddle.ListCustomersParams) ([]*paddle.Customer, error) {
+	if m.ListFunc == nil {
+		panic("paddlemock: Customers.List called but ListFunc is not set")
+	}
+	return m.ListFunc(ctx, params)
+}
+
+func (m *Customers) Create(ctx context.Context, params *paddle.CreateCustomerParams) (*paddle.Customer, error) {
+	if m.CreateFunc == nil {
+		panic("paddlemock: Customers.Create called but CreateFunc is not set")
+	}
+	return m.CreateFunc(ctx, params)
+}
+
+func (m *Customers) Get(ctx context.Context, id string) (*paddle.Customer, error) {
+	if m.GetFunc == nil {
+		panic("paddlemock: Customers.Get called but GetFunc is not set")
+	}
+	return m.GetFunc(ctx, id)
+}
+
+func (m *Customers) Update(ctx context.Context, id string, params *paddle.UpdateCustomerParams) (*paddle.Customer, error) {
+	if m.UpdateFunc == nil {
+		panic("paddlemock: Customers.Update called but UpdateFunc is not set")
+	}
+	return m.UpdateFunc(ctx, id, params)
+}
+
+// Discounts is a mock of paddle.DiscountsAPI. Calling a method whose Func field is
+// nil panics.
+type Discounts struct {
+	ListFunc func(ctx context.Context, params *paddle.ListDiscountsParams) ([]*paddle.Discount, error)
+	GetFunc  func(ctx context.Context, id string) (*paddle.Discount, error)
+}
+
+var _ paddle.DiscountsAPI = (*Discounts)(nil)
+
+func (m *Discounts) List(ctx context.Context, params *paddle.ListDiscountsParams) ([]*paddle.Discount, error) {
+	if m.ListFunc == nil {
+		panic("paddlemock: Discounts.List called but ListFunc is not set")
+	}
+	return m.ListFunc(ctx, params)
+}
+
+func (m *Discounts) Get(ctx context.Context, id string) (*paddle.Discount, error) {
+	if m.GetFunc == nil {
+		panic("paddlemock: Discounts.Get called but GetFunc is not set")
+	}
+	return m.GetFunc(ctx, id)
+}
+
+// Prices is a mock of paddle.PricesAPI. Calling a method whose Func field is
+// nil panics.
+type Prices struct {
+	ListFunc   func(ctx context.Context, params *paddle.ListPricesParams) ([]*paddle.Price, error)
+	CreateFunc func(ctx context.Context, params *paddle.CreatePriceParams) (*paddle.Price, error)
+	GetFunc    func(ctx context.Context, id string, includeProduct bool) (*paddle.Price, error)
+	UpdateFunc func(ctx context.Context, id string, params *paddle.UpdatePriceParams) (*paddle.Price, error)
+}
+
+var _ paddle.PricesAPI = (*Prices)(nil)
+
+func (m *Prices) List(ctx context.Context, params *paddle.ListPricesParams) ([]*paddle.Price, error) {
+	if m.ListFunc == nil {
+		panic("paddlemock: Prices.List called but ListFunc is not set")
+	}
+	return m.ListFunc(ctx, params)
+}
+
+func (m *Prices) Create(ctx context.Context, params *paddle.CreatePriceParams) (*paddle.Price, error) {
+	if m.CreateFunc == nil {
+		panic("paddlemock: Prices.Create called but CreateFunc is not set")
+	}
+	return m.CreateFunc(ctx, params)
+}
+
+func (m *Prices) Get(ctx context.Context, id string, includeProduct bool) (*paddle.Price, error) {
+	if m.GetFunc == nil {
+		panic("paddlemock: Prices.Get called but GetFunc is not set")
+	}
+	return m.GetFunc(ctx, id, includeProduct)
+}
+
+func (m *Prices) Update(ctx context.Context, id string, params *paddle.UpdatePriceParams) (*paddle.Price, error) {
+	if m.UpdateFunc == nil {
+		panic("paddlemock: Prices.Update called but UpdateFunc is not set")
+	}
+	return m.UpdateFunc(ctx, id, params)
+}
+
+// Products is a mock of paddle.ProductsAPI. Calling a method whose Func field is
+// nil panics.
+type Products struct {
+	ListFunc   func(ctx context.Context, params *paddle.ListProductsParams) ([]*paddle.Product, error)
+	CreateFunc func(ctx context.Context, params *paddle.CreateProductParams) (*paddle.Product, error)
+	GetFunc    func(ctx context.Context, id string, includePrices bool) (*paddle.Product, error)
+	UpdateFunc func(ctx context.Context, id string, params *paddle.UpdateProductParams) (*paddle.Product, error)
+}
+
+var _ paddle.ProductsAPI = (*Products)(nil)
+
+func (m *Products) List(ctx context.Context, params *paddle.ListProductsParams) ([]*paddle.Product, error) {
+	if m.ListFunc == nil {
+		panic("paddlemock: Products.List called but ListFunc is not set")
+	}
+	return m.ListFunc(ctx, params)
+}
+
+func (m *Products) Create(ctx context.Context, params *paddle.CreateProductParams) (*paddle.Product, error) {
+	if m.CreateFunc == nil {
+		panic("paddlemock: Products.Create called but CreateFunc is not set")
+	}
+	return m.CreateFunc(ctx, params)
+}
+
+func (m *Products) Get(ctx context.Context, id string, includePrices bool) (*paddle.Product, error) {
+	if m.GetFunc == nil {
+		panic("paddlemock: Products.Get called but GetFunc is not set")
+	}
+	return m.GetFunc(ctx, id, includePrices)
+}
+
+func (m *Products) Update(ctx context.Context, id string, params *paddle.UpdateProductParams) (*paddle.Product, error) {
+	if m.UpdateFunc == nil {
+		panic("paddlemock: Products.Update called but UpdateFunc is not set")
+	}
+	return m.UpdateFunc(ctx, id, params)
+}
+
+// Subscriptions is a mock of paddle.SubscriptionsAPI. Calling a method whose Func field is
+// nil panics.
+type Subscriptions struct {
+	ListFunc                              func(ctx context.Context, params *paddle.ListSubscriptionsParams) ([]*paddle.Subscription, error)
+	GetFunc                               func(ctx context.Context, id string) (*paddle.Subscription, error)
+	UpdateFunc                            func(ctx context.Context, id string, params *paddle.UpdateSubscriptionParams) (*paddle.Subscription, error)
+	RemoveScheduledCancellationFunc       func(ctx context.Context, id string) (*paddle.Subscription, error)
+	CancelFunc                            func(ctx context.Context, id string, params *paddle.CancelSubscriptionParams) (*paddle.Subscription, error)
+	PauseFunc                             func(ctx context.Context, id string, params *paddle.PauseSubscriptionParams) (*paddle.Subscription, error)
+	PreviewUpdateFunc                     func(ctx context.Context, id string, params *paddle.UpdateSubscriptionParams) (*paddle.SubscriptionUpdatePreview, error)
+	ResumeFunc                            func(ctx context.Context, id string, params *paddle.ResumeSubscriptionParams) (*paddle.Subscription, error)
+	GetUpdatePaymentMethodTransactionFunc func(ctx context.Context, id string) (*paddle.Transaction, error)
+}
+
+var _ paddle.SubscriptionsAPI = (*Subscriptions)(nil)
+
+func (m *Subscriptions) List(ctx context.Context, params *paddle.ListSubscriptionsParams) ([]*paddle.Subscription, error) {
+	if m.ListFunc == nil {
+		panic("paddlemock: Subscriptions.List called but ListFunc is not set")
+	}
+	return m.ListFunc(ctx, params)
+}
+
+func (m *Subscriptions) Get(ctx context.Context, id string) (*paddle.Subscription, error) {
+	if m.GetFunc == nil {
+		panic("paddlemock: Subscriptions.Get called but GetFunc is not set")
+	}
+	return m.GetFunc(ctx, id)
+}
+
+func (m *Subscriptions) Update(ctx context.Context, id string, params *paddle.UpdateSubscriptionParams) (*paddle.Subscription, error) {
+	if m.UpdateFunc == nil {
+		panic("paddlemock: Subscriptions.Update called but UpdateFunc is not set")
+	}
+	return m.UpdateFunc(ctx, id, params)
+}
+
+func (m *Subscriptions) RemoveScheduledCancellation(ctx context.Context, id string) (*paddle.Subscription, error) {
+	if m.RemoveScheduledCancellationFunc == nil {
+		panic("paddlemock: Subscriptions.RemoveScheduledCancellation called but RemoveScheduledCancellationFunc is not set")
+	}
+	return m.RemoveScheduledCancellationFunc(ctx, id)
+}
+
+func (m *Subscriptions) Cancel(ctx context.Context, id string, params *paddle.CancelSubscriptionParams) (*paddle.Subscription, error) {
+	if m.CancelFunc == nil {
+		panic("paddlemock: Subscriptions.Cancel called but CancelFunc is not set")
+	}
+	return m.CancelFunc(ctx, id, params)
+}
+
+func (m *Subscriptions) Pause(ctx context.Context, id string, params *paddle.PauseSubscriptionParams) (*paddle.Subscription, error) {
+	if m.PauseFunc == nil {
+		panic("paddlemock: Subscriptions.Pause called but PauseFunc is not set")
+	}
+	return m.PauseFunc(ctx, id, params)
+}
+
+func (m *Subscriptions) PreviewUpdate(ctx context.Context, id string, params *paddle.UpdateSubscriptionParams) (*paddle.SubscriptionUpdatePreview, error) {
+	if m.PreviewUpdateFunc == nil {
+		panic("paddlemock: Subscriptions.PreviewUpdate called but PreviewUpdateFunc is not set")
+	}
+	return m.PreviewUpdateFunc(ctx, id, params)
+}
+
+func (m *Subscriptions) Resume(ctx context.Context, id string, params *paddle.ResumeSubscriptionParams) (*paddle.Subscription, error) {
+	if m.ResumeFunc == nil {
+		panic("paddlemock: Subscriptions.Resume called but ResumeFunc is not set")
+	}
+	return m.ResumeFunc(ctx, id, params)
+}
+
+func (m *Subscriptions) GetUpdatePaymentMethodTransaction(ctx context.Context, id string) (*paddle.Transaction, error) {
+	if m.GetUpdatePaymentMethodTransactionFunc == nil {
+		panic("paddlemock: Subscriptions.GetUpdatePaymentMethodTransaction called but GetUpdatePaymentMethodTransactionFunc is not set")
+	}
+	return m.GetUpdatePaymentMethodTransactionFunc(ctx, id)
+}
+
+// Transactions is a mock of paddle.TransactionsAPI. Calling a method whose Func field is
+// nil panics.
+type Transactions struct {
+	ListFunc func(ctx context.Context, params *paddle.ListTransactionsParams) ([]*paddle.Transaction, error)
+	GetFunc  func(ctx context.Context, id string, include *paddle.TransactionIncludeParam) (*paddle.Transaction, error)
+}
+
+var _ paddle.TransactionsAPI = (*Transactions)(nil)
+
+func (m *Transactions) List(ctx context.Context, params *paddle.ListTransactionsParams) ([]*paddle.Transaction, error) {
+	if m.ListFunc == nil {
+		panic("paddlemock: Transactions.List called but ListFunc is not set")
+	}
+	return m.ListFunc(ctx, params)
+}
+
+func (m *Transactions) Get(ctx context.Context, id string, include *paddle.TransactionIncludeParam) (*paddle.Transaction, error) {
+	if m.GetFunc == nil {
+		panic("paddlemock: Transactions.Get called but GetFunc is not set")
+	}
+	return m.GetFunc(ctx, id, include)
+}
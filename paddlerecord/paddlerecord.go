@@ -0,0 +1,361 @@
+// Package paddlerecord records HTTP traffic between a client and the Paddle
+// API to a JSON cassette and replays it, so integration tests can run once
+// against the sandbox and offline thereafter.
+//
+//	rec, err := paddlerecord.New("testdata/billing.json", paddlerecord.ModeAuto)
+//	if err != nil { ... }
+//	defer rec.Stop()
+//	client := paddle.NewClient(&paddle.Config{Sandbox: true, APIKey: key, HttpClient: rec.Client()})
+//
+// Authorization and cookie headers are never written, and the fields named
+// in Recorder.PIIFields are replaced in request and response bodies and
+// query strings. Recorder.ContactPIIFields are replaced only within
+// customer, address and business contact objects, so that product and price
+// names survive. Replayed requests are matched by method, path and
+// normalised query, in recorded order.
+package paddlerecord
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var ErrNoInteraction = errors.New("paddlerecord: no recorded interaction matches request")
+
+type Mode int
+
+const (
+	// ModeReplay serves responses from the cassette and fails unmatched requests.
+	ModeReplay Mode = iota
+	// ModeRecord sends requests upstream and overwrites the cassette on Stop.
+	ModeRecord
+	// ModeAuto replays if the cassette exists and records otherwise.
+	ModeAuto
+)
+
+const Redacted = "REDACTED"
+
+// DefaultPIIFields are the JSON body fields and query parameters replaced
+// with Redacted when recording.
+var DefaultPIIFields = []string{
+	"email", "first_line", "second_line", "city", "postal_code", "region",
+	"tax_identifier", "company_number", "phone", "ip_address", "cardholder_name",
+	"last4", "search",
+}
+
+// DefaultContactPIIFields are the JSON body fields replaced with Redacted
+// when they belong to a customer, an address or a business contact.
+var DefaultContactPIIFields = []string{"name"}
+
+var droppedHeaders = map[string]bool{
+	"Authorization": true,
+	"Cookie":        true,
+	"Set-Cookie":    true,
+}
+
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+type Request struct {
+	Method  string          `json:"method"`
+	Path    string          `json:"path"`
+	Query   string          `json:"query,omitempty"`
+	Headers http.Header     `json:"headers,omitempty"`
+	Body    json.RawMessage `json:"body,omitempty"`
+}
+
+type Response struct {
+	StatusCode int             `json:"status_code"`
+	Headers    http.Header     `json:"headers,omitempty"`
+	Body       json.RawMessage `json:"body,omitempty"`
+	// RawBody holds a body which is not valid JSON.
+	RawBody string `json:"raw_body,omitempty"`
+}
+
+// Recorder is an http.RoundTripper which records or replays interactions.
+type Recorder struct {
+	// Transport sends requests while recording. Defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+	// PIIFields are redacted from bodies and queries. Defaults to DefaultPIIFields.
+	PIIFields []string
+	// ContactPIIFields are redacted from customer, address and business
+	// contact objects. Defaults to DefaultContactPIIFields.
+	ContactPIIFields []string
+
+	path     string
+	mode     Mode
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+func New(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{
+		PIIFields:        DefaultPIIFields,
+		ContactPIIFields: DefaultContactPIIFields,
+		path:             path,
+		mode:             mode,
+		cassette:         &Cassette{},
+	}
+
+	data, readErr := os.ReadFile(path)
+	switch {
+	case mode == ModeAuto && errors.Is(readErr, os.ErrNotExist):
+		r.mode = ModeRecord
+	case mode == ModeRecord:
+	case readErr != nil:
+		return nil, fmt.Errorf("failed to read cassette: %w", readErr)
+	default:
+		r.mode = ModeReplay
+		if jsonErr := json.Unmarshal(data, r.cassette); jsonErr != nil {
+			return nil, fmt.Errorf("failed to parse cassette %s: %w", path, jsonErr)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+	return r, nil
+}
+
+// Mode reports whether the recorder is recording or replaying.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Client returns an *http.Client using the recorder as its transport.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Stop writes the cassette when recording.
+func (r *Recorder) Stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.mode != ModeRecord {
+		return nil
+	}
+	data, jsonErr := json.MarshalIndent(r.cassette, "", "  ")
+	if jsonErr != nil {
+		return jsonErr
+	}
+	if dirErr := os.MkdirAll(filepath.Dir(r.path), 0o755); dirErr != nil {
+		return dirErr
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var readErr error
+		if body, readErr = io.ReadAll(req.Body); readErr != nil {
+			return nil, readErr
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	recorded := Request{
+		Method:  req.Method,
+		Path:    req.URL.Path,
+		Query:   r.normaliseQuery(req.URL.Query()),
+		Headers: r.headers(req.Header),
+		Body:    r.scrubJSON(body, isContactPath(req.URL.Path)),
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	res, resErr := transport.RoundTrip(req)
+	if resErr != nil {
+		return nil, resErr
+	}
+	resBody, readErr := io.ReadAll(res.Body)
+	res.Body.Close()
+	if readErr != nil {
+		return nil, readErr
+	}
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+
+	interaction := &Interaction{
+		Request: recorded,
+		Response: Response{
+			StatusCode: res.StatusCode,
+			Headers:    r.headers(res.Header),
+		},
+	}
+	if json.Valid(resBody) {
+		interaction.Response.Body = r.scrubJSON(resBody, isContactPath(req.URL.Path))
+	} else {
+		interaction.Response.RawBody = string(resBody)
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+	return res, nil
+}
+
+func (r *Recorder) replay(req *http.Request, recorded Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || !matches(interaction.Request, recorded) {
+			continue
+		}
+		r.used[i] = true
+		body := []byte(interaction.Response.Body)
+		if interaction.Response.RawBody != "" {
+			body = []byte(interaction.Response.RawBody)
+		}
+		header := interaction.Response.Headers.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %s %s?%s", ErrNoInteraction, recorded.Method, recorded.Path, recorded.Query)
+}
+
+func matches(a Request, b Request) bool {
+	return a.Method == b.Method && a.Path == b.Path && a.Query == b.Query
+}
+
+func (r *Recorder) isPII(key string, contact bool) bool {
+	return contains(r.PIIFields, key) || contact && contains(r.ContactPIIFields, key)
+}
+
+func contains(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+// contactKeys are the fields which hold customer, address and business
+// contact objects within other entities, e.g. included in a transaction.
+var contactKeys = map[string]bool{
+	"customer": true,
+	"address":  true,
+	"contacts": true,
+}
+
+// isContactObject identifies customers and addresses by their id prefix.
+func isContactObject(v map[string]any) bool {
+	id, _ := v["id"].(string)
+	return strings.HasPrefix(id, "ctm_") || strings.HasPrefix(id, "add_")
+}
+
+// isContactPath reports whether the bodies sent to and received from path
+// are customers or addresses, e.g. /customers/{id}/addresses. Request bodies
+// carry no id to identify them by.
+func isContactPath(path string) bool {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if parts[0] != "customers" {
+		return false
+	}
+	return len(parts) <= 2 || parts[2] == "addresses"
+}
+
+// normaliseQuery sorts parameters and comma-separated values and redacts PII.
+func (r *Recorder) normaliseQuery(q url.Values) string {
+	for key, values := range q {
+		for i, v := range values {
+			if r.isPII(key, false) {
+				values[i] = Redacted
+				continue
+			}
+			parts := strings.Split(v, ",")
+			sort.Strings(parts)
+			values[i] = strings.Join(parts, ",")
+		}
+		sort.Strings(values)
+	}
+	return q.Encode()
+}
+
+func (r *Recorder) headers(h http.Header) http.Header {
+	kept := http.Header{}
+	for key, values := range h {
+		if !droppedHeaders[http.CanonicalHeaderKey(key)] {
+			kept[key] = values
+		}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	return kept
+}
+
+func (r *Recorder) scrubJSON(data []byte, contact bool) json.RawMessage {
+	if len(data) == 0 {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if decodeErr := dec.Decode(&v); decodeErr != nil {
+		quoted, _ := json.Marshal(string(data))
+		return quoted
+	}
+	scrubbed, _ := json.Marshal(r.scrub(v, contact))
+	return scrubbed
+}
+
+func (r *Recorder) scrub(v any, contact bool) any {
+	switch v := v.(type) {
+	case map[string]any:
+		contact = contact || isContactObject(v)
+		for key, value := range v {
+			str, isString := value.(string)
+			switch {
+			case isString && r.isPII(key, contact):
+				v[key] = Redacted
+				continue
+			case isString && key == "next":
+				// Pagination links repeat the query of the request.
+				if next, parseErr := url.Parse(str); parseErr == nil {
+					next.RawQuery = r.normaliseQuery(next.Query())
+					v[key] = next.String()
+				}
+				continue
+			}
+			v[key] = r.scrub(value, contact || contactKeys[key])
+		}
+	case []any:
+		for i, value := range v {
+			v[i] = r.scrub(value, contact)
+		}
+	}
+	return v
+}
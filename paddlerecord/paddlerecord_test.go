@@ -0,0 +1,234 @@
+package paddlerecord
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestScrubJSON(t *testing.T) {
+	r := &Recorder{PIIFields: DefaultPIIFields, ContactPIIFields: DefaultContactPIIFields}
+	tests := []struct {
+		name    string
+		body    string
+		want    string
+		contact bool
+	}{
+		{
+			name: "customer response",
+			body: `{"data":{"id":"ctm_1","name":"Ada","email":"ada@example.com","locale":"en"}}`,
+			want: `{"data":{"email":"REDACTED","id":"ctm_1","locale":"en","name":"REDACTED"}}`,
+		},
+		{
+			name: "customer list",
+			body: `{"data":[{"id":"ctm_1","name":"Ada"},{"id":"ctm_2","name":"Grace"}]}`,
+			want: `{"data":[{"id":"ctm_1","name":"REDACTED"},{"id":"ctm_2","name":"REDACTED"}]}`,
+		},
+		{
+			name:    "create customer request",
+			body:    `{"email":"ada@example.com","name":"Ada"}`,
+			want:    `{"email":"REDACTED","name":"REDACTED"}`,
+			contact: true,
+		},
+		{
+			name: "product and price names are kept",
+			body: `{"data":{"id":"pro_1","name":"Pro plan","prices":[{"id":"pri_1","name":"Monthly"}]}}`,
+			want: `{"data":{"id":"pro_1","name":"Pro plan","prices":[{"id":"pri_1","name":"Monthly"}]}}`,
+		},
+		{
+			name: "discount name is kept",
+			body: `{"data":{"id":"dsc_1","description":"Launch","name":"LAUNCH"}}`,
+			want: `{"data":{"description":"Launch","id":"dsc_1","name":"LAUNCH"}}`,
+		},
+		{
+			name: "included customer and address",
+			body: `{"data":{"id":"txn_1","customer":{"name":"Ada"},"address":{"first_line":"1 Road","city":"Leeds"},"items":[{"price":{"name":"Monthly"}}]}}`,
+			want: `{"data":{"address":{"city":"REDACTED","first_line":"REDACTED"},"customer":{"name":"REDACTED"},"id":"txn_1","items":[{"price":{"name":"Monthly"}}]}}`,
+		},
+		{
+			name: "business contacts",
+			body: `{"data":{"id":"biz_1","name":"Acme Ltd","contacts":[{"name":"Ada","email":"ada@example.com"}]}}`,
+			want: `{"data":{"contacts":[{"email":"REDACTED","name":"REDACTED"}],"id":"biz_1","name":"Acme Ltd"}}`,
+		},
+		{
+			name: "only strings are replaced",
+			body: `{"data":{"id":"ctm_1","name":null,"email":{"nested":"x"}}}`,
+			want: `{"data":{"email":{"nested":"x"},"id":"ctm_1","name":null}}`,
+		},
+		{
+			name: "pagination link",
+			body: `{"data":[],"meta":{"pagination":{"next":"https://api.paddle.com/customers?search=ada&id=b,a&after=ctm_1"}}}`,
+			want: `{"data":[],"meta":{"pagination":{"next":"https://api.paddle.com/customers?after=ctm_1\u0026id=a%2Cb\u0026search=REDACTED"}}}`,
+		},
+		{
+			name: "numbers are kept exactly",
+			body: `{"data":{"id":"ctm_1","quantity":12345678901234567890}}`,
+			want: `{"data":{"id":"ctm_1","quantity":12345678901234567890}}`,
+		},
+		{
+			name: "not json",
+			body: `plain`,
+			want: `"plain"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(r.scrubJSON([]byte(tt.body), tt.contact)); got != tt.want {
+				t.Errorf("scrubJSON() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsContactPath(t *testing.T) {
+	tests := map[string]bool{
+		"/customers":                         true,
+		"/customers/ctm_1":                   true,
+		"/customers/ctm_1/addresses":         true,
+		"/customers/ctm_1/addresses/add_1":   true,
+		"/customers/ctm_1/businesses":        false,
+		"/customers/ctm_1/credit-balances":   false,
+		"/products":                          false,
+		"/transactions/txn_1/invoice":        false,
+		"/customers/ctm_1/businesses/biz_1/": false,
+	}
+	for path, want := range tests {
+		if got := isContactPath(path); got != want {
+			t.Errorf("isContactPath(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestNormaliseQuery(t *testing.T) {
+	r := &Recorder{PIIFields: DefaultPIIFields}
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"", ""},
+		{"status=active&id=b,a", "id=a%2Cb&status=active"},
+		{"id=a,b&status=active", "id=a%2Cb&status=active"},
+		{"include=prices&include=address", "include=address&include=prices"},
+		{"search=ada&email=ada@example.com", "email=REDACTED&search=REDACTED"},
+	}
+	for _, tt := range tests {
+		q, _ := url.ParseQuery(tt.query)
+		if got := r.normaliseQuery(q); got != tt.want {
+			t.Errorf("normaliseQuery(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	var upstreamCalls int
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamCalls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret")
+		fmt.Fprintf(w, `{"data":[{"id":"ctm_%d","email":"ada@example.com"}],"meta":{"request_id":"%s"}}`,
+			upstreamCalls, r.URL.RawQuery)
+	}))
+	defer upstream.Close()
+
+	path := filepath.Join(t.TempDir(), "cassettes", "customers.json")
+	rec, newErr := New(path, ModeAuto)
+	if newErr != nil {
+		t.Fatal(newErr)
+	}
+	if rec.Mode() != ModeRecord {
+		t.Fatalf("Mode() = %v without a cassette, want ModeRecord", rec.Mode())
+	}
+	get := func(client *http.Client, query string) (string, error) {
+		req, _ := http.NewRequest(http.MethodGet, upstream.URL+"/customers?"+query, nil)
+		req.Header.Set("Authorization", "Bearer pdl_live_secret")
+		res, resErr := client.Do(req)
+		if resErr != nil {
+			return "", resErr
+		}
+		defer res.Body.Close()
+		body, _ := io.ReadAll(res.Body)
+		return string(body), nil
+	}
+
+	for _, query := range []string{"id=ctm_1,ctm_2&status=active", "status=archived", "status=archived"} {
+		if _, err := get(rec.Client(), query); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if stopErr := rec.Stop(); stopErr != nil {
+		t.Fatal(stopErr)
+	}
+
+	cassette, readErr := os.ReadFile(path)
+	if readErr != nil {
+		t.Fatal(readErr)
+	}
+	for _, secret := range []string{"pdl_live_secret", "ada@example.com", "session=secret", "Authorization"} {
+		if strings.Contains(string(cassette), secret) {
+			t.Errorf("cassette contains %q", secret)
+		}
+	}
+
+	replay, replayErr := New(path, ModeAuto)
+	if replayErr != nil {
+		t.Fatal(replayErr)
+	}
+	if replay.Mode() != ModeReplay {
+		t.Fatalf("Mode() = %v with a cassette, want ModeReplay", replay.Mode())
+	}
+	calls := upstreamCalls
+
+	tests := []struct {
+		name    string
+		query   string
+		wantId  string
+		wantErr error
+	}{
+		{"reordered params and values", "status=active&id=ctm_2,ctm_1", "ctm_1", nil},
+		{"first of repeated requests", "status=archived", "ctm_2", nil},
+		{"second of repeated requests", "status=archived", "ctm_3", nil},
+		{"interactions are used once", "status=archived", "", ErrNoInteraction},
+		{"different values", "id=ctm_1&status=active", "", ErrNoInteraction},
+	}
+	for _, tt := range tests {
+		body, err := get(replay.Client(), tt.query)
+		if !errors.Is(err, tt.wantErr) {
+			t.Fatalf("%s: error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+		if tt.wantErr != nil {
+			continue
+		}
+		var res struct {
+			Data []struct {
+				Id    string `json:"id"`
+				Email string `json:"email"`
+			} `json:"data"`
+		}
+		if jsonErr := json.Unmarshal([]byte(body), &res); jsonErr != nil {
+			t.Fatalf("%s: %v", tt.name, jsonErr)
+		}
+		if len(res.Data) != 1 || res.Data[0].Id != tt.wantId || res.Data[0].Email != Redacted {
+			t.Errorf("%s: replayed %s, want %s with a redacted email", tt.name, body, tt.wantId)
+		}
+	}
+	if upstreamCalls != calls {
+		t.Errorf("replay called upstream %d times", upstreamCalls-calls)
+	}
+	if stopErr := replay.Stop(); stopErr != nil {
+		t.Fatal(stopErr)
+	}
+}
+
+func TestNewReplayWithoutCassette(t *testing.T) {
+	if _, err := New(filepath.Join(t.TempDir(), "missing.json"), ModeReplay); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("New(ModeReplay) error = %v, want os.ErrNotExist", err)
+	}
+}
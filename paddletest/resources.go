@@ -0,0 +1,757 @@
+package paddletest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/texm/go-paddle"
+)
+
+func (s *Server) routeCustomers(r *http.Request, parts []string, q url.Values) routeResult {
+	if len(parts) > 1 && parts[1] == "addresses" {
+		return s.routeAddresses(r, parts[0], parts[2:], q)
+	}
+	switch {
+	case len(parts) == 0 && r.Method == http.MethodGet:
+		search := strings.ToLower(q.Get("search"))
+		return page(s, r, q, s.customers, func(c *paddle.Customer) bool {
+			if search != "" {
+				name := ""
+				if c.Name != nil {
+					name = *c.Name
+				}
+				if !strings.Contains(strings.ToLower(c.Email+" "+name+" "+c.Id), search) {
+					return false
+				}
+			}
+			return matchList(q, "status", c.Status) && matchList(q, "email", c.Email)
+		})
+
+	case len(parts) == 0 && r.Method == http.MethodPost:
+		var params paddle.CreateCustomerParams
+		if apiErr := decodeBody(r, &params); apiErr != nil {
+			return routeResult{err: apiErr}
+		}
+		if params.Email == "" {
+			return routeResult{err: invalidField("email", "email is required")}
+		}
+		for _, existing := range s.customers.list() {
+			if strings.EqualFold(existing.Email, params.Email) {
+				return routeResult{err: newApiError(http.StatusConflict, paddle.ErrorCodeConflict,
+					"customer email conflicts with customer of id "+existing.Id)}
+			}
+		}
+		now := s.Now().UTC()
+		c := &paddle.Customer{
+			Id:        s.newId("ctm"),
+			Status:    paddle.StatusActive,
+			Name:      params.Name,
+			Email:     params.Email,
+			Locale:    "en",
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if params.CustomData != nil {
+			c.CustomData = *params.CustomData
+		}
+		if params.Locale != nil {
+			c.Locale = *params.Locale
+		}
+		c = s.customers.put(c)
+		return routeResult{data: c, events: []event{{paddle.EventTypeCustomerCreated, c}}}
+
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		c, ok := s.customers.get(parts[0])
+		if !ok {
+			return routeResult{err: errNotFound("customer", parts[0])}
+		}
+		return routeResult{data: c}
+
+	case len(parts) == 1 && r.Method == http.MethodPatch:
+		c, ok := s.customers.get(parts[0])
+		if !ok {
+			return routeResult{err: errNotFound("customer", parts[0])}
+		}
+		var params paddle.UpdateCustomerParams
+		if apiErr := decodeBody(r, &params); apiErr != nil {
+			return routeResult{err: apiErr}
+		}
+		if params.Email != nil {
+			c.Email = *params.Email
+		}
+		if params.Name != nil {
+			c.Name = params.Name
+		}
+		if params.CustomData != nil {
+			c.CustomData = *params.CustomData
+		}
+		if params.Locale != nil {
+			c.Locale = *params.Locale
+		}
+		c.UpdatedAt = s.Now().UTC()
+		c = s.customers.put(c)
+		return routeResult{data: c, events: []event{{paddle.EventTypeCustomerUpdated, c}}}
+	}
+	return methodNotAllowed(r)
+}
+
+func (s *Server) routeAddresses(r *http.Request, customerId string, parts []string, q url.Values) routeResult {
+	if _, ok := s.customers.get(customerId); !ok {
+		return routeResult{err: errNotFound("customer", customerId)}
+	}
+	switch {
+	case len(parts) == 0 && r.Method == http.MethodGet:
+		return page(s, r, q, s.addresses, func(a *paddle.Address) bool {
+			return a.CustomerId == customerId && matchList(q, "status", a.Status)
+		})
+
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		a, ok := s.addresses.get(parts[0])
+		if !ok || a.CustomerId != customerId {
+			return routeResult{err: errNotFound("address", parts[0])}
+		}
+		return routeResult{data: a}
+	}
+	return methodNotAllowed(r)
+}
+
+func (s *Server) productWithPrices(p *paddle.Product) *paddle.Product {
+	prices := []*paddle.Price{}
+	for _, price := range s.prices.list() {
+		if price.ProductId == p.Id {
+			prices = append(prices, price)
+		}
+	}
+	p.Prices = &prices
+	return p
+}
+
+func (s *Server) routeProducts(r *http.Request, parts []string, q url.Values) routeResult {
+	switch {
+	case len(parts) == 0 && r.Method == http.MethodGet:
+		res := page(s, r, q, s.products, func(p *paddle.Product) bool {
+			return matchList(q, "status", p.Status) && matchList(q, "tax_category", p.TaxCategory)
+		})
+		if hasInclude(q, "prices") {
+			for _, p := range res.data.([]*paddle.Product) {
+				s.productWithPrices(p)
+			}
+		}
+		return res
+
+	case len(parts) == 0 && r.Method == http.MethodPost:
+		var params paddle.CreateProductParams
+		if apiErr := decodeBody(r, &params); apiErr != nil {
+			return routeResult{err: apiErr}
+		}
+		if params.Name == "" {
+			return routeResult{err: invalidField("name", "name is required")}
+		}
+		if !params.TaxCategory.IsKnown() {
+			return routeResult{err: invalidField("tax_category", "tax_category is invalid")}
+		}
+		p := &paddle.Product{
+			Id:          s.newId("pro"),
+			Name:        params.Name,
+			Description: params.Description,
+			TaxCategory: params.TaxCategory,
+			ImageUrl:    params.ImageUrl,
+			Status:      paddle.StatusActive,
+			CreatedAt:   s.Now().UTC(),
+			UpdatedAt:   s.Now().UTC(),
+		}
+		if params.CustomData != nil {
+			p.CustomData = *params.CustomData
+		}
+		p = s.products.put(p)
+		return routeResult{data: p, events: []event{{paddle.EventTypeProductCreated, p}}}
+	}
+
+	if len(parts) != 1 {
+		return methodNotAllowed(r)
+	}
+	p, ok := s.products.get(parts[0])
+	if !ok {
+		return routeResult{err: errNotFound("product", parts[0])}
+	}
+	switch r.Method {
+	case http.MethodGet:
+		if hasInclude(q, "prices") {
+			s.productWithPrices(p)
+		}
+		return routeResult{data: p}
+
+	case http.MethodPatch:
+		var params paddle.UpdateProductParams
+		if apiErr := decodeBody(r, &params); apiErr != nil {
+			return routeResult{err: apiErr}
+		}
+		if params.Name != nil {
+			p.Name = *params.Name
+		}
+		if params.TaxCategory != nil {
+			p.TaxCategory = *params.TaxCategory
+		}
+		if params.Description != nil {
+			p.Description = params.Description
+		}
+		if params.ImageUrl != nil {
+			p.ImageUrl = params.ImageUrl
+		}
+		if params.CustomData != nil {
+			p.CustomData = *params.CustomData
+		}
+		if params.Status != nil {
+			p.Status = *params.Status
+		}
+		p.UpdatedAt = s.Now().UTC()
+		p = s.products.put(p)
+		return routeResult{data: p, events: []event{{paddle.EventTypeProductUpdated, p}}}
+	}
+	return methodNotAllowed(r)
+}
+
+func (s *Server) routePrices(r *http.Request, parts []string, q url.Values) routeResult {
+	includeProduct := func(p *paddle.Price) {
+		if product, ok := s.products.get(p.ProductId); ok {
+			p.Product = product
+		}
+	}
+
+	switch {
+	case len(parts) == 0 && r.Method == http.MethodGet:
+		recurring, recurringErr := strconv.ParseBool(q.Get("recurring"))
+		res := page(s, r, q, s.prices, func(p *paddle.Price) bool {
+			if recurringErr == nil && (p.BillingCycle != nil) != recurring {
+				return false
+			}
+			return matchList(q, "status", p.Status) && matchList(q, "product_id", p.ProductId)
+		})
+		if hasInclude(q, "product") {
+			for _, p := range res.data.([]*paddle.Price) {
+				includeProduct(p)
+			}
+		}
+		return res
+
+	case len(parts) == 0 && r.Method == http.MethodPost:
+		var params paddle.CreatePriceParams
+		if apiErr := decodeBody(r, &params); apiErr != nil {
+			return routeResult{err: apiErr}
+		}
+		if _, ok := s.products.get(params.ProductId); !ok {
+			return routeResult{err: invalidField("product_id", "product "+params.ProductId+" not found")}
+		}
+		if params.Description == "" {
+			return routeResult{err: invalidField("description", "description is required")}
+		}
+		p := &paddle.Price{
+			Id:                 s.newId("pri"),
+			ProductId:          params.ProductId,
+			Description:        params.Description,
+			BillingCycle:       params.BillingCycle,
+			TrialPeriod:        params.TrialPeriod,
+			TaxMode:            params.TaxMode,
+			UnitPrice:          params.UnitPrice,
+			UnitPriceOverrides: params.UnitPriceOverrides,
+			Status:             paddle.StatusActive,
+			Quantity:           paddle.MinMax{Minimum: 1, Maximum: 100},
+			CreatedAt:          s.Now().UTC(),
+			UpdatedAt:          s.Now().UTC(),
+		}
+		if params.Name != nil {
+			p.Name = *params.Name
+		}
+		if p.TaxMode == "" {
+			p.TaxMode = paddle.TaxModeAccountSetting
+		}
+		if params.Quantity != nil {
+			p.Quantity = *params.Quantity
+		}
+		if params.CustomData != nil {
+			p.CustomData = *params.CustomData
+		}
+		p = s.prices.put(p)
+		return routeResult{data: p, events: []event{{paddle.EventTypePriceCreated, p}}}
+	}
+
+	if len(parts) != 1 {
+		return methodNotAllowed(r)
+	}
+	p, ok := s.prices.get(parts[0])
+	if !ok {
+		return routeResult{err: errNotFound("price", parts[0])}
+	}
+	switch r.Method {
+	case http.MethodGet:
+		if hasInclude(q, "product") {
+			includeProduct(p)
+		}
+		return routeResult{data: p}
+
+	case http.MethodPatch:
+		var params paddle.UpdatePriceParams
+		if apiErr := decodeBody(r, &params); apiErr != nil {
+			return routeResult{err: apiErr}
+		}
+		if params.Description != nil {
+			p.Description = *params.Description
+		}
+		if params.Name != nil {
+			p.Name = *params.Name
+		}
+		if params.BillingCycle != nil {
+			p.BillingCycle = params.BillingCycle
+		}
+		if params.TrialPeriod != nil {
+			p.TrialPeriod = params.TrialPeriod
+		}
+		if params.TaxMode != nil {
+			p.TaxMode = *params.TaxMode
+		}
+		if params.UnitPrice != nil {
+			p.UnitPrice = *params.UnitPrice
+		}
+		if params.UnitPriceOverrides != nil {
+			p.UnitPriceOverrides = *params.UnitPriceOverrides
+		}
+		if params.Quantity != nil {
+			p.Quantity = *params.Quantity
+		}
+		if params.CustomData != nil {
+			p.CustomData = *params.CustomData
+		}
+		if params.Status != nil {
+			p.Status = *params.Status
+		}
+		p.UpdatedAt = s.Now().UTC()
+		p = s.prices.put(p)
+		return routeResult{data: p, events: []event{{paddle.EventTypePriceUpdated, p}}}
+	}
+	return methodNotAllowed(r)
+}
+
+func (s *Server) routeSubscriptions(r *http.Request, parts []string, q url.Values) routeResult {
+	if len(parts) == 0 {
+		if r.Method != http.MethodGet {
+			return methodNotAllowed(r)
+		}
+		return page(s, r, q, s.subscriptions, func(sub *paddle.Subscription) bool {
+			if priceIds := splitList(q.Get("price_id")); len(priceIds) > 0 {
+				found := false
+				for _, item := range sub.Items {
+					found = found || contains(priceIds, item.Price.Id)
+				}
+				if !found {
+					return false
+				}
+			}
+			action := paddle.SubscriptionScheduledChangeAction("")
+			if sub.ScheduledChange != nil {
+				action = sub.ScheduledChange.Action
+			}
+			return matchList(q, "status", sub.Status) &&
+				matchList(q, "customer_id", sub.CustomerId) &&
+				matchList(q, "address_id", sub.AddressId) &&
+				matchList(q, "collection_mode", sub.CollectionMode) &&
+				(q.Get("scheduled_change_action") == "" || matchList(q, "scheduled_change_action", action))
+		})
+	}
+
+	sub, ok := s.subscriptions.get(parts[0])
+	if !ok {
+		return routeResult{err: errNotFound("subscription", parts[0])}
+	}
+	now := s.Now().UTC()
+
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		return routeResult{data: sub}
+
+	case len(parts) == 1 && r.Method == http.MethodPatch:
+		if _, apiErr := s.applySubscriptionUpdate(r, sub); apiErr != nil {
+			return routeResult{err: apiErr}
+		}
+		sub.UpdatedAt = now
+		sub = s.subscriptions.put(sub)
+		return routeResult{data: sub, events: []event{{paddle.EventTypeSubscriptionUpdated, sub}}}
+
+	case len(parts) == 2 && parts[1] == "preview" && r.Method == http.MethodPatch:
+		if _, apiErr := s.applySubscriptionUpdate(r, sub); apiErr != nil {
+			return routeResult{err: apiErr}
+		}
+		if preview, ok := s.previews[sub.Id]; ok {
+			return routeResult{data: clone(preview)}
+		}
+		zero := paddle.NewMoney(paddle.Amount{}, sub.CurrencyCode)
+		return routeResult{data: &paddle.SubscriptionUpdatePreview{
+			NextBilledAt: sub.NextBilledAt,
+			UpdateSummary: paddle.SubscriptionUpdatePreviewSummary{
+				Credit: zero,
+				Charge: zero,
+				Result: paddle.CurrencyPriceAction{
+					CurrencyCode: sub.CurrencyCode,
+					Action:       paddle.SubscriptionChangeResultActionCharge,
+				},
+			},
+		}}
+
+	case len(parts) == 2 && parts[1] == "cancel" && r.Method == http.MethodPost:
+		var params paddle.CancelSubscriptionParams
+		if apiErr := decodeBody(r, &params); apiErr != nil {
+			return routeResult{err: apiErr}
+		}
+		effectiveFrom, apiErr := checkCancel(sub, params.EffectiveFrom)
+		if apiErr != nil {
+			return routeResult{err: apiErr}
+		}
+		events := []event{}
+		if effectiveFrom == paddle.SubscriptionEffectFromOptionImmediately {
+			sub.Status = paddle.SubscriptionStatusCanceled
+			sub.CanceledAt = &now
+			sub.ScheduledChange = nil
+			sub.NextBilledAt = nil
+			events = append(events, event{paddle.EventTypeSubscriptionCanceled, nil})
+		} else {
+			effectiveAt := now
+			if sub.CurrentBillingPeriod != nil {
+				effectiveAt = sub.CurrentBillingPeriod.EndsAt
+			}
+			sub.ScheduledChange = &paddle.SubscriptionScheduledChange{
+				Action:      paddle.SubscriptionScheduledChangeActionCancel,
+				EffectiveAt: effectiveAt,
+			}
+			events = append(events, event{paddle.EventTypeSubscriptionUpdated, nil})
+		}
+		sub.UpdatedAt = now
+		sub = s.subscriptions.put(sub)
+		for i := range events {
+			events[i].data = sub
+		}
+		return routeResult{data: sub, events: events}
+
+	case len(parts) == 2 && parts[1] == "pause" && r.Method == http.MethodPost:
+		var params paddle.PauseSubscriptionParams
+		if apiErr := decodeBody(r, &params); apiErr != nil {
+			return routeResult{err: apiErr}
+		}
+		effectiveFrom, apiErr := checkPause(sub, params.EffectiveFrom, params.ResumeAt, now)
+		if apiErr != nil {
+			return routeResult{err: apiErr}
+		}
+		eventType := paddle.EventTypeSubscriptionUpdated
+		if effectiveFrom == paddle.SubscriptionEffectFromOptionNextBillingPeriod {
+			effectiveAt := now
+			if sub.CurrentBillingPeriod != nil {
+				effectiveAt = sub.CurrentBillingPeriod.EndsAt
+			}
+			sub.ScheduledChange = &paddle.SubscriptionScheduledChange{
+				Action:      paddle.SubscriptionScheduledChangeActionPause,
+				EffectiveAt: effectiveAt,
+				ResumeAt:    params.ResumeAt,
+			}
+		} else {
+			sub.Status = paddle.SubscriptionStatusPaused
+			sub.PausedAt = &now
+			sub.NextBilledAt = nil
+			sub.CurrentBillingPeriod = nil
+			sub.ScheduledChange = nil
+			if params.ResumeAt != nil {
+				sub.ScheduledChange = &paddle.SubscriptionScheduledChange{
+					Action:      paddle.SubscriptionScheduledChangeActionResume,
+					EffectiveAt: *params.ResumeAt,
+				}
+			}
+			eventType = paddle.EventTypeSubscriptionPaused
+		}
+		sub.UpdatedAt = now
+		sub = s.subscriptions.put(sub)
+		return routeResult{data: sub, events: []event{{eventType, sub}}}
+
+	case len(parts) == 2 && parts[1] == "resume" && r.Method == http.MethodPost:
+		var params paddle.ResumeSubscriptionParams
+		if apiErr := decodeBody(r, &params); apiErr != nil {
+			return routeResult{err: apiErr}
+		}
+		if apiErr := checkResume(sub, params.EffectiveFrom); apiErr != nil {
+			return routeResult{err: apiErr}
+		}
+		sub.Status = paddle.SubscriptionStatusActive
+		sub.PausedAt = nil
+		sub.ScheduledChange = nil
+		sub.CurrentBillingPeriod = &paddle.TimePeriod{StartsAt: now, EndsAt: addInterval(now, sub.BillingCycle)}
+		sub.NextBilledAt = &sub.CurrentBillingPeriod.EndsAt
+		sub.UpdatedAt = now
+		sub = s.subscriptions.put(sub)
+		return routeResult{data: sub, events: []event{{paddle.EventTypeSubscriptionResumed, sub}}}
+
+	case len(parts) == 2 && parts[1] == "update-payment-method-transaction" && r.Method == http.MethodGet:
+		customerId, addressId, subscriptionId := sub.CustomerId, sub.AddressId, sub.Id
+		checkoutUrl := s.URL + "/checkout?_ptxn=" + s.newId("txn")
+		txn := &paddle.Transaction{
+			Id:             s.newId("txn"),
+			Status:         paddle.TransactionStatusReady,
+			CustomerId:     &customerId,
+			AddressId:      &addressId,
+			SubscriptionId: &subscriptionId,
+			CurrencyCode:   sub.CurrencyCode,
+			Origin:         paddle.TransactionOriginSubscriptionPaymentMethodChange,
+			CollectionMode: sub.CollectionMode,
+			Checkout:       &paddle.Checkout{Url: &checkoutUrl},
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+		txn = s.transactions.put(txn)
+		return routeResult{data: txn, events: []event{{paddle.EventTypeTransactionCreated, txn}}}
+	}
+	return methodNotAllowed(r)
+}
+
+// Error codes Paddle returns when a subscription is not in a state which
+// allows an action.
+const (
+	errorCodeSubscriptionCanceled       = paddle.ErrorCode("subscription_is_canceled_action_invalid")
+	errorCodeSubscriptionMustBePaused   = paddle.ErrorCode("subscription_must_be_paused")
+	errorCodeSubscriptionNotActive      = paddle.ErrorCode("subscription_not_active")
+	errorCodeSubscriptionPendingChanges = paddle.ErrorCode("subscription_locked_pending_changes")
+)
+
+// The checks below encode Paddle's documented rules for cancel, pause and
+// resume. They deliberately do not use paddle.Subscription.Transition so the
+// fake can be used to test it.
+
+func defaultEffectFrom(effectiveFrom paddle.SubscriptionEffectFromOption) (paddle.SubscriptionEffectFromOption, *apiError) {
+	switch effectiveFrom {
+	case "":
+		return paddle.SubscriptionEffectFromOptionNextBillingPeriod, nil
+	case paddle.SubscriptionEffectFromOptionImmediately, paddle.SubscriptionEffectFromOptionNextBillingPeriod:
+		return effectiveFrom, nil
+	}
+	return "", invalidField("effective_from", "effective_from must be immediately or next_billing_period")
+}
+
+// checkCancel allows any subscription which is not already canceled to be
+// canceled immediately. Paused and past due subscriptions have no billing
+// period to cancel at the end of, and a subscription can only have one
+// scheduled change.
+func checkCancel(sub *paddle.Subscription, effectiveFrom paddle.SubscriptionEffectFromOption) (paddle.SubscriptionEffectFromOption, *apiError) {
+	if sub.Status == paddle.SubscriptionStatusCanceled {
+		return "", newApiError(http.StatusBadRequest, errorCodeSubscriptionCanceled, "subscription is canceled")
+	}
+	effectiveFrom, apiErr := defaultEffectFrom(effectiveFrom)
+	if apiErr != nil || effectiveFrom == paddle.SubscriptionEffectFromOptionImmediately {
+		return effectiveFrom, apiErr
+	}
+	switch {
+	case sub.Status == paddle.SubscriptionStatusPaused || sub.Status == paddle.SubscriptionStatusPastDue:
+		return "", invalidField("effective_from", string(sub.Status)+" subscriptions can only be canceled immediately")
+	case sub.ScheduledChange != nil:
+		return "", newApiError(http.StatusBadRequest, errorCodeSubscriptionPendingChanges,
+			"subscription has a scheduled "+string(sub.ScheduledChange.Action))
+	}
+	return effectiveFrom, nil
+}
+
+// checkPause allows only active subscriptions without a scheduled change to
+// be paused. resume_at must be after the pause takes effect.
+func checkPause(sub *paddle.Subscription, effectiveFrom paddle.SubscriptionEffectFromOption, resumeAt *time.Time, now time.Time) (paddle.SubscriptionEffectFromOption, *apiError) {
+	switch {
+	case sub.Status == paddle.SubscriptionStatusCanceled:
+		return "", newApiError(http.StatusBadRequest, errorCodeSubscriptionCanceled, "subscription is canceled")
+	case sub.Status != paddle.SubscriptionStatusActive:
+		return "", newApiError(http.StatusBadRequest, errorCodeSubscriptionNotActive,
+			"only active subscriptions can be paused, subscription is "+string(sub.Status))
+	case sub.ScheduledChange != nil:
+		return "", newApiError(http.StatusBadRequest, errorCodeSubscriptionPendingChanges,
+			"subscription has a scheduled "+string(sub.ScheduledChange.Action))
+	}
+	effectiveFrom, apiErr := defaultEffectFrom(effectiveFrom)
+	if apiErr != nil {
+		return "", apiErr
+	}
+	effectiveAt := now
+	if effectiveFrom == paddle.SubscriptionEffectFromOptionNextBillingPeriod && sub.CurrentBillingPeriod != nil {
+		effectiveAt = sub.CurrentBillingPeriod.EndsAt
+	}
+	if resumeAt != nil && !resumeAt.After(effectiveAt) {
+		return "", invalidField("resume_at", "resume_at must be after the subscription is paused")
+	}
+	return effectiveFrom, nil
+}
+
+// checkResume allows only paused subscriptions to be resumed, and only
+// immediately: a paused subscription has no billing period to wait for.
+func checkResume(sub *paddle.Subscription, effectiveFrom paddle.SubscriptionEffectFromOption) *apiError {
+	switch {
+	case sub.Status == paddle.SubscriptionStatusCanceled:
+		return newApiError(http.StatusBadRequest, errorCodeSubscriptionCanceled, "subscription is canceled")
+	case sub.Status != paddle.SubscriptionStatusPaused:
+		return newApiError(http.StatusBadRequest, errorCodeSubscriptionMustBePaused,
+			"only paused subscriptions can be resumed, subscription is "+string(sub.Status))
+	case effectiveFrom != "" && effectiveFrom != paddle.SubscriptionEffectFromOptionImmediately:
+		return invalidField("effective_from", "paused subscriptions can only be resumed immediately")
+	}
+	return nil
+}
+
+func (s *Server) applySubscriptionUpdate(r *http.Request, sub *paddle.Subscription) (*paddle.UpdateSubscriptionParams, *apiError) {
+	var raw map[string]json.RawMessage
+	if apiErr := decodeBody(r, &raw); apiErr != nil {
+		return nil, apiErr
+	}
+	data, _ := json.Marshal(raw)
+	var params paddle.UpdateSubscriptionParams
+	if jsonErr := json.Unmarshal(data, &params); jsonErr != nil {
+		return nil, newApiError(http.StatusBadRequest, paddle.ErrorCodeInvalidJson, jsonErr.Error())
+	}
+
+	if sc, ok := raw["scheduled_change"]; ok {
+		if string(sc) != "null" {
+			return nil, invalidField("scheduled_change", "scheduled_change can only be removed")
+		}
+		sub.ScheduledChange = nil
+	}
+	if params.CustomerId != nil {
+		sub.CustomerId = *params.CustomerId
+	}
+	if params.AddressId != nil {
+		sub.AddressId = *params.AddressId
+	}
+	if params.BusinessId != nil {
+		sub.BusinessId = params.BusinessId
+	}
+	if params.CurrencyCode != nil {
+		sub.CurrencyCode = *params.CurrencyCode
+	}
+	if params.NextBilledAt != nil {
+		next := params.NextBilledAt.UTC()
+		sub.NextBilledAt = &next
+	}
+	if params.CollectionMode != nil {
+		sub.CollectionMode = *params.CollectionMode
+	}
+	if params.BillingDetails != nil {
+		sub.BillingDetails = params.BillingDetails
+	}
+	if params.CustomData != nil {
+		sub.CustomData = *params.CustomData
+	}
+	if params.Items != nil {
+		if params.ProrationBillingMode == "" {
+			return nil, invalidField("proration_billing_mode", "proration_billing_mode is required when updating items")
+		}
+		items := make([]paddle.SubscriptionItem, 0, len(*params.Items))
+		now := s.Now().UTC()
+		for i, item := range *params.Items {
+			price, ok := s.prices.get(item.PriceId)
+			if !ok {
+				return nil, invalidField("items["+strconv.Itoa(i)+"].price_id", "price "+item.PriceId+" not found")
+			}
+			items = append(items, paddle.SubscriptionItem{
+				Status:       paddle.SubscriptionItemStatusActive,
+				Quantity:     item.Quantity,
+				Recurring:    price.BillingCycle != nil,
+				CreatedAt:    now,
+				UpdatedAt:    now,
+				NextBilledAt: sub.NextBilledAt,
+				Price:        *price,
+			})
+		}
+		sub.Items = items
+	}
+	return &params, nil
+}
+
+func (s *Server) routeTransactions(r *http.Request, parts []string, q url.Values) routeResult {
+	if r.Method != http.MethodGet || len(parts) > 1 {
+		return methodNotAllowed(r)
+	}
+	if len(parts) == 0 {
+		res := page(s, r, q, s.transactions, func(t *paddle.Transaction) bool {
+			return matchList(q, "status", t.Status) &&
+				matchList(q, "collection_mode", t.CollectionMode) &&
+				matchOptional(q, "customer_id", t.CustomerId) &&
+				matchOptional(q, "subscription_id", t.SubscriptionId) &&
+				matchOptional(q, "invoice_number", t.InvoiceNumber) &&
+				matchTime(q, "created_at", &t.CreatedAt) &&
+				matchTime(q, "updated_at", &t.UpdatedAt) &&
+				matchTime(q, "billed_at", t.BilledAt)
+		})
+		for _, t := range res.data.([]*paddle.Transaction) {
+			s.includeTransaction(t, q)
+		}
+		return res
+	}
+	t, ok := s.transactions.get(parts[0])
+	if !ok {
+		return routeResult{err: errNotFound("transaction", parts[0])}
+	}
+	s.includeTransaction(t, q)
+	return routeResult{data: t}
+}
+
+func (s *Server) includeTransaction(t *paddle.Transaction, q url.Values) {
+	if hasInclude(q, "customer") && t.CustomerId != nil {
+		t.Customer, _ = s.customers.get(*t.CustomerId)
+	}
+	if hasInclude(q, "address") && t.AddressId != nil {
+		t.Address, _ = s.addresses.get(*t.AddressId)
+	}
+}
+
+func matchOptional(q url.Values, key string, v *string) bool {
+	if q.Get(key) == "" {
+		return true
+	}
+	return v != nil && contains(splitList(q.Get(key)), *v)
+}
+
+func matchTime(q url.Values, key string, t *time.Time) bool {
+	for _, op := range []string{"LT", "LTE", "GT", "GTE"} {
+		raw := q.Get(key + "[" + op + "]")
+		if raw == "" {
+			continue
+		}
+		bound, parseErr := time.Parse(time.RFC3339, raw)
+		if parseErr != nil || t == nil {
+			return false
+		}
+		switch op {
+		case "LT":
+			if !t.Before(bound) {
+				return false
+			}
+		case "LTE":
+			if t.After(bound) {
+				return false
+			}
+		case "GT":
+			if !t.After(bound) {
+				return false
+			}
+		case "GTE":
+			if t.Before(bound) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// addInterval advances t by a billing cycle, defaulting to one month.
+func addInterval(t time.Time, cycle paddle.TimeInterval) time.Time {
+	n := max(cycle.Frequency, 1)
+	switch cycle.Interval {
+	case paddle.TimePeriodIntervalDay:
+		return t.AddDate(0, 0, n)
+	case paddle.TimePeriodIntervalWeek:
+		return t.AddDate(0, 0, 7*n)
+	case paddle.TimePeriodIntervalYear:
+		return t.AddDate(n, 0, 0)
+	}
+	return t.AddDate(0, n, 0)
+}
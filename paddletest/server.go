@@ -0,0 +1,399 @@
+// Package paddletest provides an in-memory fake of the Paddle API for
+// testing code which uses *paddle.Client.
+//
+//	srv := paddletest.NewServer()
+//	defer srv.Close()
+//	client := srv.PaddleClient(nil)
+//
+// The fake stores customers and their addresses, products, prices,
+// subscriptions and transactions, paginates list responses and returns
+// Paddle-shaped errors. Mutations are delivered as signed webhooks to any
+// registered URL. The fake does not calculate prorations: subscription
+// update previews return the response set with SetUpdatePreview.
+package paddletest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/texm/go-paddle"
+)
+
+const (
+	DefaultAPIKey  = "pdl_test_apikey"
+	DefaultPerPage = 50
+)
+
+type Server struct {
+	*httptest.Server
+
+	// APIKey is the bearer token requests must present.
+	APIKey string
+	// PerPage is the default page size for list endpoints.
+	PerPage int
+	// Now returns the time used for created_at, updated_at and webhooks.
+	Now func() time.Time
+
+	mu            sync.Mutex
+	seq           int
+	customers     *store[paddle.Customer]
+	addresses     *store[paddle.Address]
+	products      *store[paddle.Product]
+	prices        *store[paddle.Price]
+	subscriptions *store[paddle.Subscription]
+	transactions  *store[paddle.Transaction]
+	previews      map[string]*paddle.SubscriptionUpdatePreview
+	webhooks      []*webhookEndpoint
+	deliveries    []Delivery
+}
+
+func NewServer() *Server {
+	s := &Server{
+		APIKey:        DefaultAPIKey,
+		PerPage:       DefaultPerPage,
+		Now:           time.Now,
+		customers:     newStore(func(c *paddle.Customer) string { return c.Id }),
+		addresses:     newStore(func(a *paddle.Address) string { return a.Id }),
+		products:      newStore(func(p *paddle.Product) string { return p.Id }),
+		prices:        newStore(func(p *paddle.Price) string { return p.Id }),
+		subscriptions: newStore(func(s *paddle.Subscription) string { return s.Id }),
+		transactions:  newStore(func(t *paddle.Transaction) string { return t.Id }),
+		previews:      map[string]*paddle.SubscriptionUpdatePreview{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// PaddleClient returns a client configured to use the fake. cfg may be nil;
+// its BaseURL and APIKey are overwritten.
+func (s *Server) PaddleClient(cfg *paddle.Config) *paddle.Client {
+	if cfg == nil {
+		cfg = &paddle.Config{}
+	}
+	cfg.BaseURL = s.URL
+	cfg.APIKey = s.APIKey
+	if cfg.HttpClient == nil {
+		cfg.HttpClient = s.Server.Client()
+	}
+	return paddle.NewClient(cfg)
+}
+
+func (s *Server) newId(prefix string) string {
+	s.seq++
+	return fmt.Sprintf("%s_%026d", prefix, s.seq)
+}
+
+func (s *Server) AddCustomer(c paddle.Customer) *paddle.Customer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c.Id == "" {
+		c.Id = s.newId("ctm")
+	}
+	if c.Status == "" {
+		c.Status = paddle.StatusActive
+	}
+	s.stamp(&c.CreatedAt, &c.UpdatedAt)
+	return s.customers.put(&c)
+}
+
+func (s *Server) AddAddress(a paddle.Address) *paddle.Address {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if a.Id == "" {
+		a.Id = s.newId("add")
+	}
+	if a.Status == "" {
+		a.Status = paddle.StatusActive
+	}
+	s.stamp(&a.CreatedAt, &a.UpdatedAt)
+	return s.addresses.put(&a)
+}
+
+func (s *Server) AddProduct(p paddle.Product) *paddle.Product {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p.Id == "" {
+		p.Id = s.newId("pro")
+	}
+	if p.Status == "" {
+		p.Status = paddle.StatusActive
+	}
+	s.stamp(&p.CreatedAt, &p.UpdatedAt)
+	return s.products.put(&p)
+}
+
+func (s *Server) AddPrice(p paddle.Price) *paddle.Price {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p.Id == "" {
+		p.Id = s.newId("pri")
+	}
+	if p.Status == "" {
+		p.Status = paddle.StatusActive
+	}
+	s.stamp(&p.CreatedAt, &p.UpdatedAt)
+	return s.prices.put(&p)
+}
+
+func (s *Server) AddSubscription(sub paddle.Subscription) *paddle.Subscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sub.Id == "" {
+		sub.Id = s.newId("sub")
+	}
+	if sub.Status == "" {
+		sub.Status = paddle.SubscriptionStatusActive
+	}
+	s.stamp(&sub.CreatedAt, &sub.UpdatedAt)
+	return s.subscriptions.put(&sub)
+}
+
+func (s *Server) AddTransaction(t paddle.Transaction) *paddle.Transaction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t.Id == "" {
+		t.Id = s.newId("txn")
+	}
+	if t.Status == "" {
+		t.Status = paddle.TransactionStatusDraft
+	}
+	s.stamp(&t.CreatedAt, &t.UpdatedAt)
+	return s.transactions.put(&t)
+}
+
+// SetUpdatePreview sets the response to previewing an update of the
+// subscription. Without one, a preview has the updated subscription's next
+// billing date and a zero update summary.
+func (s *Server) SetUpdatePreview(subscriptionId string, preview paddle.SubscriptionUpdatePreview) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.previews[subscriptionId] = clone(&preview)
+}
+
+func (s *Server) stamp(createdAt *time.Time, updatedAt *time.Time) {
+	now := s.Now().UTC()
+	if createdAt.IsZero() {
+		*createdAt = now
+	}
+	if updatedAt.IsZero() {
+		*updatedAt = now
+	}
+}
+
+type apiError struct {
+	status int
+	body   paddle.ApiError
+}
+
+func newApiError(status int, code paddle.ErrorCode, detail string, fields ...paddle.ApiFieldError) *apiError {
+	errType := paddle.ErrorTypeRequest
+	if status >= http.StatusInternalServerError {
+		errType = paddle.ErrorTypeApi
+	}
+	return &apiError{status: status, body: paddle.ApiError{
+		Type:             errType,
+		Code:             code,
+		Detail:           detail,
+		DocumentationUrl: "https://developer.paddle.com/v1/errors/shared/" + string(code),
+		Errors:           fields,
+	}}
+}
+
+func errNotFound(kind string, id string) *apiError {
+	return newApiError(http.StatusNotFound, paddle.ErrorCodeNotFound,
+		fmt.Sprintf("%s %s not found", kind, id))
+}
+
+type response struct {
+	Data  any                    `json:"data,omitempty"`
+	Error *paddle.ApiError       `json:"error,omitempty"`
+	Meta  paddle.ApiResponseMeta `json:"meta"`
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, status int, res *response) {
+	s.mu.Lock()
+	res.Meta.RequestId = s.newId("req")
+	s.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+func (s *Server) writeError(w http.ResponseWriter, err *apiError) {
+	s.writeJSON(w, err.status, &response{Error: &err.body})
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	switch auth := r.Header.Get("Authorization"); {
+	case auth == "":
+		s.writeError(w, newApiError(http.StatusUnauthorized, paddle.ErrorCodeAuthenticationMissing,
+			"Authentication header missing"))
+		return
+	case auth != "Bearer "+s.APIKey:
+		s.writeError(w, newApiError(http.StatusForbidden, paddle.ErrorCodeForbidden,
+			"You aren't permitted to perform this request"))
+		return
+	}
+
+	data, meta, apiErr, events := s.route(r)
+	for _, ev := range events {
+		s.deliver(ev)
+	}
+	if apiErr != nil {
+		s.writeError(w, apiErr)
+		return
+	}
+	res := &response{Data: data}
+	if meta != nil {
+		res.Meta = *meta
+	}
+	s.writeJSON(w, http.StatusOK, res)
+}
+
+type routeResult struct {
+	data   any
+	meta   *paddle.ApiResponseMeta
+	err    *apiError
+	events []event
+}
+
+func (s *Server) route(r *http.Request) (any, *paddle.ApiResponseMeta, *apiError, []event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	q := r.URL.Query()
+	res := routeResult{}
+	switch {
+	case r.Method == http.MethodGet && parts[0] == "event-types" && len(parts) == 1:
+		res.data = []any{}
+	case parts[0] == "customers":
+		res = s.routeCustomers(r, parts[1:], q)
+	case parts[0] == "products":
+		res = s.routeProducts(r, parts[1:], q)
+	case parts[0] == "prices":
+		res = s.routePrices(r, parts[1:], q)
+	case parts[0] == "subscriptions":
+		res = s.routeSubscriptions(r, parts[1:], q)
+	case parts[0] == "transactions":
+		res = s.routeTransactions(r, parts[1:], q)
+	default:
+		res.err = newApiError(http.StatusNotFound, paddle.ErrorCodeInvalidUrl,
+			fmt.Sprintf("%s %s is not a recognised endpoint", r.Method, r.URL.Path))
+	}
+	return res.data, res.meta, res.err, res.events
+}
+
+func methodNotAllowed(r *http.Request) routeResult {
+	return routeResult{err: newApiError(http.StatusMethodNotAllowed, paddle.ErrorCodeMethodNotAllowed,
+		fmt.Sprintf("%s is not allowed on %s", r.Method, r.URL.Path))}
+}
+
+func decodeBody(r *http.Request, v any) *apiError {
+	if jsonErr := json.NewDecoder(r.Body).Decode(v); jsonErr != nil {
+		return newApiError(http.StatusBadRequest, paddle.ErrorCodeInvalidJson,
+			"Invalid request body: "+jsonErr.Error())
+	}
+	return nil
+}
+
+func invalidField(field string, message string) *apiError {
+	return newApiError(http.StatusBadRequest, paddle.ErrorCodeInvalidField,
+		"Invalid request", paddle.ApiFieldError{Field: field, Message: message})
+}
+
+// page applies id filtering, ordering by id and cursor pagination to a
+// filtered list. The after cursor must be the ID of a stored resource, as in
+// Paddle, though it need not match the filter.
+func page[T any](s *Server, r *http.Request, q url.Values, st *store[T], match func(*T) bool) routeResult {
+	after := q.Get("after")
+	if _, ok := st.get(after); after != "" && !ok {
+		return routeResult{err: invalidField("after", fmt.Sprintf("%s is not a valid cursor", after))}
+	}
+
+	all := st.list()
+	switch orderBy := q.Get("order_by"); orderBy {
+	case "", "id[ASC]":
+	case "id[DESC]":
+		for i, j := 0, len(all)-1; i < j; i, j = i+1, j-1 {
+			all[i], all[j] = all[j], all[i]
+		}
+	default:
+		return routeResult{err: invalidField("order_by", fmt.Sprintf("paddletest only orders by id, not %s", orderBy))}
+	}
+
+	ids := splitList(q.Get("id"))
+	var items []*T
+	total, pastCursor := 0, after == ""
+	for _, item := range all {
+		id := st.id(item)
+		if (len(ids) > 0 && !contains(ids, id)) || (match != nil && !match(item)) {
+			pastCursor = pastCursor || id == after
+			continue
+		}
+		total++
+		if pastCursor {
+			items = append(items, item)
+		}
+		pastCursor = pastCursor || id == after
+	}
+
+	perPage := s.PerPage
+	if pp, convErr := strconv.Atoi(q.Get("per_page")); convErr == nil && pp > 0 {
+		perPage = pp
+	}
+	hasMore := len(items) > perPage
+	if hasMore {
+		items = items[:perPage]
+	}
+
+	meta := &paddle.ApiResponseMeta{Pagination: paddle.ApiResponseMetaPagination{
+		PerPage:        perPage,
+		HasMore:        hasMore,
+		EstimatedTotal: total,
+	}}
+	if hasMore {
+		next := url.Values{}
+		for key, values := range q {
+			next[key] = values
+		}
+		next.Set("after", st.id(items[len(items)-1]))
+		meta.Pagination.Next = s.URL + r.URL.Path + "?" + next.Encode()
+	}
+	if items == nil {
+		items = []*T{}
+	}
+	return routeResult{data: items, meta: meta}
+}
+
+func splitList(v string) []string {
+	if v == "" {
+		return nil
+	}
+	return strings.Split(v, ",")
+}
+
+func contains[T ~string](list []string, v T) bool {
+	for _, item := range list {
+		if item == string(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchList[T ~string](q url.Values, key string, v T) bool {
+	values := splitList(q.Get(key))
+	return len(values) == 0 || contains(values, v)
+}
+
+func hasInclude(q url.Values, name string) bool {
+	return contains(splitList(q.Get("include")), name)
+}
@@ -0,0 +1,610 @@
+package paddletest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/texm/go-paddle"
+)
+
+var testNow = time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+func newTestServer(t *testing.T) (*Server, *paddle.Client) {
+	t.Helper()
+	srv := NewServer()
+	srv.Now = func() time.Time { return testNow }
+	t.Cleanup(srv.Close)
+	return srv, srv.PaddleClient(nil)
+}
+
+func wantCode(t *testing.T, err error, code paddle.ErrorCode) {
+	t.Helper()
+	if !errors.Is(err, &paddle.ApiError{Code: code}) {
+		t.Fatalf("error = %v, want code %s", err, code)
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	srv, client := newTestServer(t)
+	if err := client.TestAuthentication(context.Background()); err != nil {
+		t.Fatalf("TestAuthentication() = %v", err)
+	}
+
+	wrongKey := paddle.NewClient(&paddle.Config{BaseURL: srv.URL, APIKey: "wrong"})
+	wantCode(t, wrongKey.TestAuthentication(context.Background()), paddle.ErrorCodeForbidden)
+
+	res, resErr := http.Get(srv.URL + "/customers")
+	if resErr != nil {
+		t.Fatal(resErr)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusUnauthorized {
+		t.Errorf("unauthenticated request status = %d, want %d", res.StatusCode, http.StatusUnauthorized)
+	}
+}
+
+func TestRouteErrors(t *testing.T) {
+	srv, client := newTestServer(t)
+	ctx := context.Background()
+
+	_, getErr := client.Customers.Get(ctx, "ctm_missing")
+	wantCode(t, getErr, paddle.ErrorCodeNotFound)
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+		code   paddle.ErrorCode
+	}{
+		{http.MethodGet, "/unknown", http.StatusNotFound, paddle.ErrorCodeInvalidUrl},
+		{http.MethodDelete, "/customers", http.StatusMethodNotAllowed, paddle.ErrorCodeMethodNotAllowed},
+		{http.MethodPost, "/transactions", http.StatusMethodNotAllowed, paddle.ErrorCodeMethodNotAllowed},
+		{http.MethodPost, "/customers", http.StatusBadRequest, paddle.ErrorCodeInvalidJson},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(tt.method, srv.URL+tt.path, nil)
+		req.Header.Set("Authorization", "Bearer "+srv.APIKey)
+		res, resErr := http.DefaultClient.Do(req)
+		if resErr != nil {
+			t.Fatal(resErr)
+		}
+		var body struct {
+			Error paddle.ApiError `json:"error"`
+		}
+		decodeErr := json.NewDecoder(res.Body).Decode(&body)
+		res.Body.Close()
+		if decodeErr != nil {
+			t.Fatalf("%s %s: %v", tt.method, tt.path, decodeErr)
+		}
+		if res.StatusCode != tt.status || body.Error.Code != tt.code {
+			t.Errorf("%s %s = %d %s, want %d %s", tt.method, tt.path, res.StatusCode, body.Error.Code, tt.status, tt.code)
+		}
+	}
+}
+
+func TestCustomers(t *testing.T) {
+	_, client := newTestServer(t)
+	ctx := context.Background()
+
+	created, createErr := client.Customers.Create(ctx, &paddle.CreateCustomerParams{Email: "ada@example.com"})
+	if createErr != nil {
+		t.Fatal(createErr)
+	}
+	if created.Status != paddle.StatusActive || !created.CreatedAt.Equal(testNow) {
+		t.Errorf("Create() = %+v, want an active customer created now", created)
+	}
+
+	_, conflictErr := client.Customers.Create(ctx, &paddle.CreateCustomerParams{Email: "ADA@example.com"})
+	wantCode(t, conflictErr, paddle.ErrorCodeConflict)
+	_, missingErr := client.Customers.Create(ctx, &paddle.CreateCustomerParams{})
+	wantCode(t, missingErr, paddle.ErrorCodeInvalidField)
+
+	name := "Ada"
+	updated, updateErr := client.Customers.Update(ctx, created.Id, &paddle.UpdateCustomerParams{Name: &name})
+	if updateErr != nil {
+		t.Fatal(updateErr)
+	}
+	if updated.Name == nil || *updated.Name != name || updated.Email != created.Email {
+		t.Errorf("Update() = %+v, want only the name changed", updated)
+	}
+
+	found, listErr := client.Customers.List(ctx, &paddle.ListCustomersParams{Search: "ada"})
+	if listErr != nil {
+		t.Fatal(listErr)
+	}
+	if len(found) != 1 || found[0].Id != created.Id {
+		t.Errorf("List(search) = %v, want %s", found, created.Id)
+	}
+}
+
+func TestPagination(t *testing.T) {
+	srv, client := newTestServer(t)
+	srv.PerPage = 2
+	var ids []string
+	for i := 0; i < 5; i++ {
+		status := paddle.StatusActive
+		if i == 3 {
+			status = paddle.StatusArchived
+		}
+		ids = append(ids, srv.AddCustomer(paddle.Customer{Status: status}).Id)
+	}
+
+	tests := []struct {
+		name   string
+		params *paddle.ListCustomersParams
+		want   []string
+	}{
+		{"all pages", nil, ids},
+		{"filtered across pages", &paddle.ListCustomersParams{Status: []paddle.Status{paddle.StatusActive}},
+			[]string{ids[0], ids[1], ids[2], ids[4]}},
+		{"ids", &paddle.ListCustomersParams{Ids: []string{ids[4], ids[1]}}, []string{ids[1], ids[4]}},
+		{"no match", &paddle.ListCustomersParams{Email: []string{"none@example.com"}}, nil},
+		{"after", &paddle.ListCustomersParams{After: ids[1]}, ids[2:]},
+		{"per page", &paddle.ListCustomersParams{PerPage: 1}, ids},
+		{"descending", &paddle.ListCustomersParams{OrderBy: "id[DESC]"}, []string{ids[4], ids[3], ids[2], ids[1], ids[0]}},
+		{"descending after", &paddle.ListCustomersParams{OrderBy: "id[DESC]", After: ids[3]}, []string{ids[2], ids[1], ids[0]}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			customers, err := client.Customers.List(context.Background(), tt.params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, c := range customers {
+				got = append(got, c.Id)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("List() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("List() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	if _, err := client.Customers.List(context.Background(), &paddle.ListCustomersParams{OrderBy: "email[ASC]"}); err == nil {
+		t.Error("List() ordered by email succeeded, want an error")
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/customers?per_page=3", nil)
+	req.Header.Set("Authorization", "Bearer "+srv.APIKey)
+	res, resErr := http.DefaultClient.Do(req)
+	if resErr != nil {
+		t.Fatal(resErr)
+	}
+	defer res.Body.Close()
+	var body struct {
+		Data []paddle.Customer      `json:"data"`
+		Meta paddle.ApiResponseMeta `json:"meta"`
+	}
+	if decodeErr := json.NewDecoder(res.Body).Decode(&body); decodeErr != nil {
+		t.Fatal(decodeErr)
+	}
+	pagination := body.Meta.Pagination
+	if len(body.Data) != 3 || !pagination.HasMore || pagination.PerPage != 3 || pagination.EstimatedTotal != 5 ||
+		pagination.Next != srv.URL+"/customers?after="+ids[2]+"&per_page=3" {
+		t.Errorf("first page = %d items, %+v", len(body.Data), pagination)
+	}
+
+	cursors := []struct {
+		after      string
+		wantStatus int
+		wantIds    []string
+	}{
+		{ids[1], http.StatusOK, []string{ids[2], ids[4]}},
+		// A cursor outside the filter still marks a position in the list.
+		{ids[3], http.StatusOK, []string{ids[4]}},
+		{"ctm_unknown", http.StatusBadRequest, nil},
+	}
+	for _, tt := range cursors {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/customers?status=active&after="+tt.after, nil)
+		req.Header.Set("Authorization", "Bearer "+srv.APIKey)
+		res, resErr := http.DefaultClient.Do(req)
+		if resErr != nil {
+			t.Fatal(resErr)
+		}
+		var page struct {
+			Data  []paddle.Customer `json:"data"`
+			Error *paddle.ApiError  `json:"error"`
+		}
+		decodeErr := json.NewDecoder(res.Body).Decode(&page)
+		res.Body.Close()
+		if decodeErr != nil {
+			t.Fatal(decodeErr)
+		}
+		if res.StatusCode != tt.wantStatus {
+			t.Fatalf("after=%s status = %d, want %d", tt.after, res.StatusCode, tt.wantStatus)
+		}
+		if tt.wantStatus != http.StatusOK {
+			if page.Error == nil || page.Error.Code != paddle.ErrorCodeInvalidField {
+				t.Errorf("after=%s error = %+v, want invalid_field", tt.after, page.Error)
+			}
+			continue
+		}
+		var got []string
+		for _, c := range page.Data {
+			got = append(got, c.Id)
+		}
+		if len(got) != len(tt.wantIds) || (len(got) > 0 && (got[0] != tt.wantIds[0] || got[len(got)-1] != tt.wantIds[len(tt.wantIds)-1])) {
+			t.Errorf("after=%s = %v, want %v", tt.after, got, tt.wantIds)
+		}
+	}
+}
+
+func TestCatalogIncludes(t *testing.T) {
+	srv, client := newTestServer(t)
+	ctx := context.Background()
+	product := srv.AddProduct(paddle.Product{Name: "Pro", TaxCategory: paddle.TaxCategorySaas})
+	price := srv.AddPrice(paddle.Price{ProductId: product.Id, BillingCycle: &paddle.TimeInterval{Frequency: 1, Interval: paddle.TimePeriodIntervalMonth}})
+	srv.AddPrice(paddle.Price{ProductId: product.Id})
+
+	withPrices, getErr := client.Products.Get(ctx, product.Id, true)
+	if getErr != nil {
+		t.Fatal(getErr)
+	}
+	if withPrices.Prices == nil || len(*withPrices.Prices) != 2 {
+		t.Errorf("Get(includePrices).Prices = %v, want 2 prices", withPrices.Prices)
+	}
+	withoutPrices, _ := client.Products.Get(ctx, product.Id, false)
+	if withoutPrices.Prices != nil {
+		t.Errorf("Get().Prices = %v, want nil", withoutPrices.Prices)
+	}
+
+	recurring := true
+	prices, listErr := client.Prices.List(ctx, &paddle.ListPricesParams{IncludeProduct: true, Recurring: &recurring})
+	if listErr != nil {
+		t.Fatal(listErr)
+	}
+	if len(prices) != 1 || prices[0].Id != price.Id || prices[0].Product == nil || prices[0].Product.Id != product.Id {
+		t.Errorf("List(recurring, include product) = %+v", prices)
+	}
+
+	_, createErr := client.Prices.Create(ctx, &paddle.CreatePriceParams{ProductId: "pro_missing", Description: "x"})
+	wantCode(t, createErr, paddle.ErrorCodeInvalidField)
+}
+
+func TestTransactionFilters(t *testing.T) {
+	srv, client := newTestServer(t)
+	ctx := context.Background()
+	customer := srv.AddCustomer(paddle.Customer{Email: "ada@example.com"})
+	early, late := testNow.AddDate(0, -1, 0), testNow.AddDate(0, 0, -1)
+	srv.AddTransaction(paddle.Transaction{Status: paddle.TransactionStatusCompleted, BilledAt: &early, CustomerId: &customer.Id})
+	recent := srv.AddTransaction(paddle.Transaction{Status: paddle.TransactionStatusCompleted, BilledAt: &late, CustomerId: &customer.Id})
+	srv.AddTransaction(paddle.Transaction{Status: paddle.TransactionStatusDraft})
+
+	txns, err := client.Transactions.List(ctx, &paddle.ListTransactionsParams{
+		Include:     &paddle.TransactionIncludeParam{Customer: true},
+		CustomerIds: []string{customer.Id},
+		Status:      []paddle.TransactionStatus{paddle.TransactionStatusCompleted},
+		BilledAt:    paddle.After(testNow.AddDate(0, 0, -7)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txns) != 1 || txns[0].Id != recent.Id || txns[0].Customer == nil || txns[0].Customer.Email != customer.Email {
+		t.Errorf("List() = %+v, want %s with its customer", txns, recent.Id)
+	}
+}
+
+func TestSubscriptionActions(t *testing.T) {
+	periodEnd := testNow.AddDate(0, 0, 20)
+	resumeBeforeEnd, resumeAfterEnd := periodEnd.AddDate(0, 0, -1), periodEnd.AddDate(0, 0, 7)
+	const (
+		immediately = paddle.SubscriptionEffectFromOptionImmediately
+		nextPeriod  = paddle.SubscriptionEffectFromOptionNextBillingPeriod
+		cancel      = paddle.SubscriptionScheduledChangeActionCancel
+		pause       = paddle.SubscriptionScheduledChangeActionPause
+	)
+
+	tests := []struct {
+		name          string
+		status        paddle.SubscriptionStatus
+		scheduled     paddle.SubscriptionScheduledChangeAction
+		action        paddle.SubscriptionAction
+		effectiveFrom paddle.SubscriptionEffectFromOption
+		resumeAt      *time.Time
+		wantCode      paddle.ErrorCode
+		wantStatus    paddle.SubscriptionStatus
+		wantScheduled paddle.SubscriptionScheduledChangeAction
+	}{
+		{name: "cancel active by default", status: "active", action: "cancel", wantStatus: "active", wantScheduled: cancel},
+		{name: "cancel active immediately", status: "active", action: "cancel", effectiveFrom: immediately, wantStatus: "canceled"},
+		{name: "cancel trialing at period end", status: "trialing", action: "cancel", effectiveFrom: nextPeriod, wantStatus: "trialing", wantScheduled: cancel},
+		{name: "cancel past due immediately", status: "past_due", action: "cancel", effectiveFrom: immediately, wantStatus: "canceled"},
+		{name: "cancel past due at period end", status: "past_due", action: "cancel", wantCode: paddle.ErrorCodeInvalidField},
+		{name: "cancel paused immediately", status: "paused", action: "cancel", effectiveFrom: immediately, wantStatus: "canceled"},
+		{name: "cancel paused at period end", status: "paused", action: "cancel", wantCode: paddle.ErrorCodeInvalidField},
+		{name: "cancel with scheduled pause", status: "active", scheduled: pause, action: "cancel", wantCode: errorCodeSubscriptionPendingChanges},
+		{name: "cancel with scheduled pause immediately", status: "active", scheduled: pause, action: "cancel", effectiveFrom: immediately, wantStatus: "canceled"},
+		{name: "cancel canceled", status: "canceled", action: "cancel", effectiveFrom: immediately, wantCode: errorCodeSubscriptionCanceled},
+		{name: "cancel unknown effective from", status: "active", action: "cancel", effectiveFrom: "tomorrow", wantCode: paddle.ErrorCodeInvalidField},
+
+		{name: "pause active by default", status: "active", action: "pause", wantStatus: "active", wantScheduled: pause},
+		{name: "pause active immediately", status: "active", action: "pause", effectiveFrom: immediately, wantStatus: "paused"},
+		{name: "pause immediately with resume date", status: "active", action: "pause", effectiveFrom: immediately, resumeAt: &resumeBeforeEnd,
+			wantStatus: "paused", wantScheduled: paddle.SubscriptionScheduledChangeActionResume},
+		{name: "pause resuming before it takes effect", status: "active", action: "pause", resumeAt: &resumeBeforeEnd, wantCode: paddle.ErrorCodeInvalidField},
+		{name: "pause resuming after it takes effect", status: "active", action: "pause", resumeAt: &resumeAfterEnd, wantStatus: "active", wantScheduled: pause},
+		{name: "pause trialing", status: "trialing", action: "pause", wantCode: errorCodeSubscriptionNotActive},
+		{name: "pause past due", status: "past_due", action: "pause", wantCode: errorCodeSubscriptionNotActive},
+		{name: "pause paused", status: "paused", action: "pause", wantCode: errorCodeSubscriptionNotActive},
+		{name: "pause with scheduled cancel", status: "active", scheduled: cancel, action: "pause", effectiveFrom: immediately, wantCode: errorCodeSubscriptionPendingChanges},
+		{name: "pause canceled", status: "canceled", action: "pause", wantCode: errorCodeSubscriptionCanceled},
+
+		{name: "resume paused", status: "paused", action: "resume", wantStatus: "active"},
+		{name: "resume paused immediately", status: "paused", action: "resume", effectiveFrom: immediately, wantStatus: "active"},
+		{name: "resume paused at period end", status: "paused", action: "resume", effectiveFrom: nextPeriod, wantCode: paddle.ErrorCodeInvalidField},
+		{name: "resume active", status: "active", action: "resume", wantCode: errorCodeSubscriptionMustBePaused},
+		{name: "resume active with scheduled pause", status: "active", scheduled: pause, action: "resume", wantCode: errorCodeSubscriptionMustBePaused},
+		{name: "resume trialing", status: "trialing", action: "resume", wantCode: errorCodeSubscriptionMustBePaused},
+		{name: "resume canceled", status: "canceled", action: "resume", wantCode: errorCodeSubscriptionCanceled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, client := newTestServer(t)
+			sub := paddle.Subscription{
+				Status:               tt.status,
+				CurrentBillingPeriod: &paddle.TimePeriod{StartsAt: testNow.AddDate(0, 0, -10), EndsAt: periodEnd},
+				BillingCycle:         paddle.TimeInterval{Frequency: 1, Interval: paddle.TimePeriodIntervalMonth},
+			}
+			if tt.scheduled != "" {
+				sub.ScheduledChange = &paddle.SubscriptionScheduledChange{Action: tt.scheduled, EffectiveAt: periodEnd}
+			}
+			id := srv.AddSubscription(sub).Id
+
+			// Send unknown values so that the fake's validation is exercised.
+			ctx := paddle.WithUnknownEnums(context.Background())
+			var got *paddle.Subscription
+			var err error
+			switch tt.action {
+			case paddle.SubscriptionActionCancel:
+				got, err = client.Subscriptions.Cancel(ctx, id, &paddle.CancelSubscriptionParams{EffectiveFrom: tt.effectiveFrom})
+			case paddle.SubscriptionActionPause:
+				got, err = client.Subscriptions.Pause(ctx, id, &paddle.PauseSubscriptionParams{EffectiveFrom: tt.effectiveFrom, ResumeAt: tt.resumeAt})
+			case paddle.SubscriptionActionResume:
+				got, err = client.Subscriptions.Resume(ctx, id, &paddle.ResumeSubscriptionParams{EffectiveFrom: tt.effectiveFrom})
+			}
+
+			if tt.wantCode != "" {
+				wantCode(t, err, tt.wantCode)
+				stored, _ := client.Subscriptions.Get(ctx, id)
+				if stored.Status != tt.status {
+					t.Errorf("status after a rejected %s = %s, want it unchanged", tt.action, stored.Status)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s: %v", tt.action, err)
+			}
+			var scheduled paddle.SubscriptionScheduledChangeAction
+			if got.ScheduledChange != nil {
+				scheduled = got.ScheduledChange.Action
+			}
+			if got.Status != tt.wantStatus || scheduled != tt.wantScheduled {
+				t.Errorf("%s = %s with scheduled %q, want %s with scheduled %q", tt.action, got.Status, scheduled, tt.wantStatus, tt.wantScheduled)
+			}
+			if scheduled == pause || scheduled == cancel {
+				if !got.ScheduledChange.EffectiveAt.Equal(periodEnd) {
+					t.Errorf("scheduled %s effective at %v, want the end of the billing period %v", scheduled, got.ScheduledChange.EffectiveAt, periodEnd)
+				}
+			}
+		})
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// newCountingClient returns a client for srv and a count of the mutating
+// requests it has sent.
+func newCountingClient(srv *Server) (*paddle.Client, *int) {
+	var mutations int
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.Method != http.MethodGet {
+			mutations++
+		}
+		return http.DefaultTransport.RoundTrip(r)
+	})
+	return srv.PaddleClient(&paddle.Config{HttpClient: &http.Client{Transport: transport}}), &mutations
+}
+
+// TestTransitionCheck performs every cancel, pause and resume against the
+// fake. paddle.Subscription.Transition must allow exactly what the fake
+// accepts, with the same outcome, and a call made with
+// paddle.WithTransitionCheck which it rejects must not be sent.
+func TestTransitionCheck(t *testing.T) {
+	statuses := []paddle.SubscriptionStatus{
+		paddle.SubscriptionStatusActive,
+		paddle.SubscriptionStatusTrialing,
+		paddle.SubscriptionStatusPastDue,
+		paddle.SubscriptionStatusPaused,
+		paddle.SubscriptionStatusCanceled,
+	}
+	scheduled := []paddle.SubscriptionScheduledChangeAction{
+		"",
+		paddle.SubscriptionScheduledChangeActionCancel,
+		paddle.SubscriptionScheduledChangeActionPause,
+		paddle.SubscriptionScheduledChangeActionResume,
+	}
+	actions := []paddle.SubscriptionAction{paddle.SubscriptionActionCancel, paddle.SubscriptionActionPause, paddle.SubscriptionActionResume}
+	froms := []paddle.SubscriptionEffectFromOption{"", paddle.SubscriptionEffectFromOptionImmediately, paddle.SubscriptionEffectFromOptionNextBillingPeriod}
+
+	for _, status := range statuses {
+		for _, change := range scheduled {
+			for _, action := range actions {
+				for _, from := range froms {
+					name := string(status) + "/" + string(change) + "/" + string(action) + "/" + string(from)
+					t.Run(name, func(t *testing.T) {
+						srv, _ := newTestServer(t)
+						client, mutations := newCountingClient(srv)
+						sub := paddle.Subscription{
+							Status:               status,
+							CurrentBillingPeriod: &paddle.TimePeriod{StartsAt: testNow.AddDate(0, 0, -10), EndsAt: testNow.AddDate(0, 0, 20)},
+							BillingCycle:         paddle.TimeInterval{Frequency: 1, Interval: paddle.TimePeriodIntervalMonth},
+						}
+						if change != "" {
+							sub.ScheduledChange = &paddle.SubscriptionScheduledChange{Action: change, EffectiveAt: testNow.AddDate(0, 0, 20)}
+						}
+						added := srv.AddSubscription(sub)
+						transition, transitionErr := added.Transition(action, from)
+
+						perform := func(ctx context.Context) (*paddle.Subscription, error) {
+							switch action {
+							case paddle.SubscriptionActionCancel:
+								return client.Subscriptions.Cancel(ctx, added.Id, &paddle.CancelSubscriptionParams{EffectiveFrom: from})
+							case paddle.SubscriptionActionPause:
+								return client.Subscriptions.Pause(ctx, added.Id, &paddle.PauseSubscriptionParams{EffectiveFrom: from})
+							default:
+								return client.Subscriptions.Resume(ctx, added.Id, &paddle.ResumeSubscriptionParams{EffectiveFrom: from})
+							}
+						}
+
+						ctx := context.Background()
+						got, err := perform(paddle.WithTransitionCheck(ctx))
+						if transitionErr != nil {
+							if !errors.Is(err, paddle.ErrInvalidTransition) || *mutations != 0 {
+								t.Fatalf("checked %s = %v after %d requests, want ErrInvalidTransition without a request", action, err, *mutations)
+							}
+							var apiErr *paddle.ApiError
+							if _, err := perform(ctx); !errors.As(err, &apiErr) {
+								t.Errorf("unchecked %s = %v, want the fake to reject it as Transition does: %v", action, err, transitionErr)
+							}
+							return
+						}
+						if err != nil {
+							t.Fatalf("checked %s = %v, want it allowed", action, err)
+						}
+						var gotScheduled, wantScheduled paddle.SubscriptionScheduledChangeAction
+						if got.ScheduledChange != nil {
+							gotScheduled = got.ScheduledChange.Action
+						}
+						if transition.ScheduledChange != nil {
+							wantScheduled = *transition.ScheduledChange
+						}
+						if got.Status != transition.Status || gotScheduled != wantScheduled {
+							t.Errorf("%s = %s with scheduled %q, want %s with scheduled %q", action, got.Status, gotScheduled, transition.Status, wantScheduled)
+						}
+					})
+				}
+			}
+		}
+	}
+}
+
+func TestTransitionCheckUpdates(t *testing.T) {
+	srv, _ := newTestServer(t)
+	client, mutations := newCountingClient(srv)
+	price := srv.AddPrice(paddle.Price{UnitPrice: paddle.Money{Amount: paddle.NewAmount(1000), CurrencyCode: paddle.CurrencyCodeUSD}})
+	items := &[]paddle.UpdateSubscriptionItem{{PriceId: price.Id, Quantity: 2}}
+	canceled := srv.AddSubscription(paddle.Subscription{Status: paddle.SubscriptionStatusCanceled}).Id
+	paused := srv.AddSubscription(paddle.Subscription{Status: paddle.SubscriptionStatusPaused}).Id
+	scheduled := srv.AddSubscription(paddle.Subscription{
+		Status:          paddle.SubscriptionStatusActive,
+		ScheduledChange: &paddle.SubscriptionScheduledChange{Action: paddle.SubscriptionScheduledChangeActionCancel, EffectiveAt: testNow.AddDate(0, 0, 1)},
+	}).Id
+	ctx := paddle.WithTransitionCheck(context.Background())
+
+	tests := []struct {
+		name          string
+		call          func() error
+		wantErr       error
+		wantMutations int
+	}{
+		{"update items of a canceled subscription", func() error {
+			_, err := client.Subscriptions.Update(ctx, canceled, &paddle.UpdateSubscriptionParams{Items: items, ProrationBillingMode: paddle.ProrationBillingModeDoNotBill})
+			return err
+		}, paddle.ErrInvalidTransition, 0},
+		{"preview items of a paused subscription", func() error {
+			_, err := client.Subscriptions.PreviewUpdate(ctx, paused, &paddle.UpdateSubscriptionParams{Items: items, ProrationBillingMode: paddle.ProrationBillingModeDoNotBill})
+			return err
+		}, paddle.ErrInvalidTransition, 0},
+		{"update without items is not checked", func() error {
+			_, err := client.Subscriptions.Update(ctx, paused, &paddle.UpdateSubscriptionParams{CustomData: &paddle.CustomData{"a": "b"}})
+			return err
+		}, nil, 1},
+		{"remove a missing scheduled change", func() error {
+			_, err := client.Subscriptions.RemoveScheduledCancellation(ctx, paused)
+			return err
+		}, paddle.ErrInvalidTransition, 0},
+		{"remove a scheduled cancel", func() error {
+			_, err := client.Subscriptions.RemoveScheduledCancellation(ctx, scheduled)
+			return err
+		}, nil, 1},
+		{"missing subscription", func() error {
+			_, err := client.Subscriptions.Cancel(ctx, "sub_missing", nil)
+			return err
+		}, &paddle.ApiError{Code: paddle.ErrorCodeNotFound}, 0},
+	}
+	for _, tt := range tests {
+		*mutations = 0
+		err := tt.call()
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+		if *mutations != tt.wantMutations {
+			t.Errorf("%s: sent %d mutating requests, want %d", tt.name, *mutations, tt.wantMutations)
+		}
+	}
+}
+
+func TestUpdatePreview(t *testing.T) {
+	srv, client := newTestServer(t)
+	ctx := context.Background()
+	price := srv.AddPrice(paddle.Price{UnitPrice: paddle.Money{Amount: paddle.NewAmount(1000), CurrencyCode: paddle.CurrencyCodeUSD}})
+	nextBilledAt := testNow.AddDate(0, 0, 20)
+	sub := srv.AddSubscription(paddle.Subscription{
+		CurrencyCode: paddle.CurrencyCodeUSD,
+		NextBilledAt: &nextBilledAt,
+		Items:        []paddle.SubscriptionItem{{Status: paddle.SubscriptionItemStatusActive, Quantity: 1, Recurring: true, Price: *price}},
+	})
+	params := &paddle.UpdateSubscriptionParams{
+		Items:                &[]paddle.UpdateSubscriptionItem{{PriceId: price.Id, Quantity: 3}},
+		ProrationBillingMode: paddle.ProrationBillingModeProratedImmediately,
+	}
+
+	preview, previewErr := client.Subscriptions.PreviewUpdate(ctx, sub.Id, params)
+	if previewErr != nil {
+		t.Fatal(previewErr)
+	}
+	summary := preview.UpdateSummary
+	if !summary.Credit.IsZero() || !summary.Charge.IsZero() || summary.Result.Amount.Sign() != 0 ||
+		preview.NextBilledAt == nil || !preview.NextBilledAt.Equal(nextBilledAt) {
+		t.Errorf("default preview = %+v, want a zero summary billed at %v", preview, nextBilledAt)
+	}
+
+	want := paddle.SubscriptionUpdatePreview{
+		NextBilledAt: &nextBilledAt,
+		UpdateSummary: paddle.SubscriptionUpdatePreviewSummary{
+			Credit: paddle.NewMoney(paddle.NewAmount(650), paddle.CurrencyCodeUSD),
+			Charge: paddle.NewMoney(paddle.NewAmount(1950), paddle.CurrencyCodeUSD),
+			Result: paddle.CurrencyPriceAction{Amount: paddle.NewAmount(1300), CurrencyCode: paddle.CurrencyCodeUSD, Action: paddle.SubscriptionChangeResultActionCharge},
+		},
+	}
+	srv.SetUpdatePreview(sub.Id, want)
+	preview, previewErr = client.Subscriptions.PreviewUpdate(ctx, sub.Id, params)
+	if previewErr != nil {
+		t.Fatal(previewErr)
+	}
+	gotJSON, _ := json.Marshal(preview)
+	wantJSON, _ := json.Marshal(want)
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("preview = %s, want %s", gotJSON, wantJSON)
+	}
+
+	stored, _ := client.Subscriptions.Get(ctx, sub.Id)
+	if stored.Items[0].Quantity != 1 {
+		t.Errorf("quantity after a preview = %d, want it unchanged", stored.Items[0].Quantity)
+	}
+	_, invalidErr := client.Subscriptions.PreviewUpdate(ctx, sub.Id, &paddle.UpdateSubscriptionParams{Items: params.Items})
+	wantCode(t, invalidErr, paddle.ErrorCodeInvalidField)
+}
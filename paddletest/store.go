@@ -0,0 +1,47 @@
+package paddletest
+
+import "encoding/json"
+
+// store keeps resources in insertion order, returning copies so callers
+// cannot mutate stored state without going through the server.
+type store[T any] struct {
+	id    func(*T) string
+	items map[string]*T
+	order []string
+}
+
+func newStore[T any](id func(*T) string) *store[T] {
+	return &store[T]{id: id, items: map[string]*T{}}
+}
+
+func clone[T any](v *T) *T {
+	data, _ := json.Marshal(v)
+	var c T
+	_ = json.Unmarshal(data, &c)
+	return &c
+}
+
+func (s *store[T]) put(v *T) *T {
+	id := s.id(v)
+	if _, exists := s.items[id]; !exists {
+		s.order = append(s.order, id)
+	}
+	s.items[id] = clone(v)
+	return clone(v)
+}
+
+func (s *store[T]) get(id string) (*T, bool) {
+	v, ok := s.items[id]
+	if !ok {
+		return nil, false
+	}
+	return clone(v), true
+}
+
+func (s *store[T]) list() []*T {
+	items := make([]*T, 0, len(s.order))
+	for _, id := range s.order {
+		items = append(items, clone(s.items[id]))
+	}
+	return items
+}
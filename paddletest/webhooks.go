@@ -0,0 +1,96 @@
+package paddletest
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/texm/go-paddle"
+)
+
+type event struct {
+	eventType string
+	data      any
+}
+
+type webhookEndpoint struct {
+	url    string
+	secret string
+}
+
+// Delivery records an attempt to deliver a webhook to a registered URL.
+type Delivery struct {
+	URL            string
+	EventId        string
+	EventType      string
+	NotificationId string
+	StatusCode     int
+	Err            error
+}
+
+// RegisterWebhook delivers every subsequent event to url, signed with
+// secret as the Paddle-Signature header.
+func (s *Server) RegisterWebhook(url string, secret string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.webhooks = append(s.webhooks, &webhookEndpoint{url: url, secret: secret})
+}
+
+// Deliveries returns every webhook delivery attempted so far.
+func (s *Server) Deliveries() []Delivery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Delivery(nil), s.deliveries...)
+}
+
+// Emit delivers an event of the given type to every registered webhook,
+// e.g. to simulate a subscription.past_due notification.
+func (s *Server) Emit(eventType string, data any) {
+	s.deliver(event{eventType: eventType, data: data})
+}
+
+func (s *Server) deliver(ev event) {
+	s.mu.Lock()
+	endpoints := append([]*webhookEndpoint(nil), s.webhooks...)
+	eventId := s.newId("evt")
+	occurredAt := s.Now().UTC()
+	notificationIds := make([]string, len(endpoints))
+	for i := range endpoints {
+		notificationIds[i] = s.newId("ntf")
+	}
+	s.mu.Unlock()
+
+	for i, endpoint := range endpoints {
+		delivery := Delivery{
+			URL:            endpoint.url,
+			EventId:        eventId,
+			EventType:      ev.eventType,
+			NotificationId: notificationIds[i],
+		}
+		req, reqErr := (&paddle.WebhookRequest{
+			Secret:         endpoint.secret,
+			EventType:      ev.eventType,
+			Data:           ev.data,
+			EventId:        eventId,
+			NotificationId: notificationIds[i],
+			OccurredAt:     occurredAt,
+		}).Build(context.Background(), endpoint.url)
+		if reqErr != nil {
+			delivery.Err = reqErr
+		} else {
+			delivery.StatusCode, delivery.Err = post(req)
+		}
+
+		s.mu.Lock()
+		s.deliveries = append(s.deliveries, delivery)
+		s.mu.Unlock()
+	}
+}
+
+func post(req *http.Request) (int, error) {
+	res, resErr := http.DefaultClient.Do(req)
+	if resErr != nil {
+		return 0, resErr
+	}
+	defer res.Body.Close()
+	return res.StatusCode, nil
+}
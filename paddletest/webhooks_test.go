@@ -0,0 +1,96 @@
+package paddletest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/texm/go-paddle"
+)
+
+// receiver is a webhook endpoint verifying deliveries with ParseWebhook.
+type receiver struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	events []*paddle.WebhookEvent
+}
+
+func newReceiver(t *testing.T, secret string) *receiver {
+	t.Helper()
+	client := paddle.NewClient(&paddle.Config{WebhookSecretKey: secret})
+	r := &receiver{}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		event, err := client.ParseWebhook(req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		r.mu.Lock()
+		r.events = append(r.events, event)
+		r.mu.Unlock()
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func TestWebhookDeliveries(t *testing.T) {
+	const secret = "pdl_ntfset_test"
+	srv, client := newTestServer(t)
+	good := newReceiver(t, secret)
+	bad := newReceiver(t, "pdl_ntfset_other")
+	srv.RegisterWebhook(good.URL, secret)
+	srv.RegisterWebhook(bad.URL, secret)
+
+	customer, createErr := client.Customers.Create(context.Background(), &paddle.CreateCustomerParams{Email: "ada@example.com"})
+	if createErr != nil {
+		t.Fatal(createErr)
+	}
+	srv.Emit(paddle.EventTypeSubscriptionPastDue, paddle.Subscription{Id: "sub_1"})
+
+	deliveries := srv.Deliveries()
+	want := []struct {
+		url        string
+		eventType  string
+		statusCode int
+	}{
+		{good.URL, paddle.EventTypeCustomerCreated, http.StatusOK},
+		{bad.URL, paddle.EventTypeCustomerCreated, http.StatusBadRequest},
+		{good.URL, paddle.EventTypeSubscriptionPastDue, http.StatusOK},
+		{bad.URL, paddle.EventTypeSubscriptionPastDue, http.StatusBadRequest},
+	}
+	if len(deliveries) != len(want) {
+		t.Fatalf("Deliveries() = %+v, want %d", deliveries, len(want))
+	}
+	for i, d := range deliveries {
+		if d.URL != want[i].url || d.EventType != want[i].eventType || d.StatusCode != want[i].statusCode || d.Err != nil {
+			t.Errorf("delivery %d = %+v, want %s to %s with status %d", i, d, want[i].eventType, want[i].url, want[i].statusCode)
+		}
+	}
+	if deliveries[0].EventId != deliveries[1].EventId || deliveries[0].NotificationId == deliveries[1].NotificationId {
+		t.Errorf("deliveries of one event = %+v and %+v, want a shared event ID and distinct notification IDs", deliveries[0], deliveries[1])
+	}
+
+	if len(good.events) != 2 || len(bad.events) != 0 {
+		t.Fatalf("verified %d and %d events, want 2 and 0", len(good.events), len(bad.events))
+	}
+	for i, event := range good.events {
+		d := deliveries[2*i]
+		if event.Id != d.EventId || event.NotificationId != d.NotificationId || event.Type != d.EventType {
+			t.Errorf("event %d = %+v, want delivery %+v", i, event, d)
+		}
+		if !event.OccurredAt.Equal(testNow) {
+			t.Errorf("event %d occurred at %s, want %s", i, event.OccurredAt, testNow)
+		}
+	}
+	var got paddle.Customer
+	if err := json.Unmarshal(good.events[0].Data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Id != customer.Id || got.Email != customer.Email {
+		t.Errorf("customer.created data = %+v, want %+v", got, customer)
+	}
+}
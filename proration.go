@@ -0,0 +1,135 @@
+package paddle
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// SubscriptionUpdateEstimate is a local estimate of the summary
+// PreviewUpdate returns for an item change. It is computed from list prices
+// alone, so it excludes tax, discounts and any rounding Paddle applies to
+// billing periods; use PreviewUpdate for the amount a customer will pay.
+type SubscriptionUpdateEstimate struct {
+	UpdateSummary SubscriptionUpdatePreviewSummary
+	// BilledAt is when the result is billed: immediately, at the next billing
+	// date, or nil when nothing is billed.
+	BilledAt *time.Time
+	// Remaining is the fraction of the current billing period left at the
+	// time of the change, which prorated amounts are scaled by.
+	Remaining float64
+}
+
+// EstimateUpdate estimates the credit, charge and result of replacing the
+// subscription's items with items at the given time, following the
+// semantics of mode. Prices not already on the subscription must be passed
+// in prices.
+//
+// Items whose quantity is unchanged are not billed. Changed and removed
+// items are credited for the unused part of the billing period when the
+// mode prorates, and changed and added items are charged for the rest of
+// it, or for a full period when the mode bills in full.
+func (s *Subscription) EstimateUpdate(items []UpdateSubscriptionItem, mode ProrationBillingMode, at time.Time, prices ...*Price) (*SubscriptionUpdateEstimate, error) {
+	if !mode.IsKnown() {
+		return nil, fmt.Errorf("%w: unknown proration_billing_mode %q", ErrInvalidParams, mode)
+	}
+	if _, transitionErr := s.Transition(SubscriptionActionUpdateItems, ""); transitionErr != nil {
+		return nil, transitionErr
+	}
+
+	known := map[string]*Price{}
+	current := map[string]int{}
+	for i := range s.Items {
+		item := &s.Items[i]
+		if item.Status == SubscriptionItemStatusInactive || !item.Recurring {
+			continue
+		}
+		known[item.Price.Id] = &item.Price
+		current[item.Price.Id] += item.Quantity
+	}
+	for _, p := range prices {
+		known[p.Id] = p
+	}
+	proposed := map[string]int{}
+	for i, item := range items {
+		if _, ok := known[item.PriceId]; !ok {
+			return nil, fmt.Errorf("%w: items[%d]: price %s is not on the subscription and was not given",
+				ErrInvalidParams, i, item.PriceId)
+		}
+		proposed[item.PriceId] += item.Quantity
+	}
+
+	remaining := s.remaining(at)
+	estimate := &SubscriptionUpdateEstimate{}
+	estimate.Remaining, _ = remaining.Float64()
+	credit := NewMoney(Amount{}, s.CurrencyCode)
+	charge := NewMoney(Amount{}, s.CurrencyCode)
+
+	// Trials are not billed for item changes.
+	billed := mode != ProrationBillingModeDoNotBill && s.Status != SubscriptionStatusTrialing
+	prorated := mode == ProrationBillingModeProratedImmediately || mode == ProrationBillingModeProratedNextBillingPeriod
+	for id, price := range known {
+		before, after := current[id], proposed[id]
+		if before == after || !billed {
+			continue
+		}
+		if price.UnitPrice.CurrencyCode != s.CurrencyCode {
+			return nil, fmt.Errorf("%w: price %s is in %s, the subscription in %s",
+				ErrCurrencyMismatch, id, price.UnitPrice.CurrencyCode, s.CurrencyCode)
+		}
+		if prorated && before > 0 {
+			credit.Amount = credit.Amount.Add(scale(price.UnitPrice.Amount.Mul(int64(before)), remaining))
+		}
+		if after > 0 {
+			full := price.UnitPrice.Amount.Mul(int64(after))
+			// One-time prices are always charged in full.
+			if prorated && price.BillingCycle != nil {
+				full = scale(full, remaining)
+			}
+			charge.Amount = charge.Amount.Add(full)
+		}
+	}
+
+	result := CurrencyPriceAction{
+		Amount:       charge.Amount.Sub(credit.Amount),
+		CurrencyCode: s.CurrencyCode,
+		Action:       SubscriptionChangeResultActionCharge,
+	}
+	if result.Amount.Sign() < 0 {
+		result.Amount = result.Amount.Neg()
+		result.Action = SubscriptionChangeResultActionCredit
+	}
+	estimate.UpdateSummary = SubscriptionUpdatePreviewSummary{Credit: credit, Charge: charge, Result: result}
+
+	if billed && !(credit.IsZero() && charge.IsZero()) {
+		switch mode {
+		case ProrationBillingModeProratedImmediately, ProrationBillingModeFullImmediately:
+			billedAt := at
+			estimate.BilledAt = &billedAt
+		default:
+			estimate.BilledAt = s.NextBilledAt
+		}
+	}
+	return estimate, nil
+}
+
+// remaining returns the fraction of the current billing period left at t,
+// between 0 and 1.
+func (s *Subscription) remaining(t time.Time) *big.Rat {
+	period := s.CurrentBillingPeriod
+	if period == nil || !period.EndsAt.After(period.StartsAt) {
+		return new(big.Rat)
+	}
+	switch {
+	case !t.After(period.StartsAt):
+		return big.NewRat(1, 1)
+	case !t.Before(period.EndsAt):
+		return new(big.Rat)
+	}
+	return big.NewRat(int64(period.EndsAt.Sub(t)), int64(period.EndsAt.Sub(period.StartsAt)))
+}
+
+// scale multiplies a by f, rounded to whole minor units.
+func scale(a Amount, f *big.Rat) Amount {
+	return Amount{r: new(big.Rat).Mul(a.rat(), f)}.Round()
+}
@@ -0,0 +1,390 @@
+package paddle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/texm/go-paddle/paddlerecord"
+)
+
+func TestEstimateUpdate(t *testing.T) {
+	periodStart := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := periodStart.AddDate(0, 0, 30)
+	midCycle := periodStart.AddDate(0, 0, 15)
+	monthly := &TimeInterval{Frequency: 1, Interval: TimePeriodIntervalMonth}
+	price := func(id string, amount int64, cycle *TimeInterval) *Price {
+		return &Price{Id: id, BillingCycle: cycle, UnitPrice: Money{Amount: NewAmount(amount), CurrencyCode: CurrencyCodeUSD}}
+	}
+	basic, pro, starter := price("pri_basic", 1000, monthly), price("pri_pro", 3000, monthly), price("pri_starter", 400, monthly)
+	setup := price("pri_setup", 500, nil)
+
+	newSubscription := func() *Subscription {
+		return &Subscription{
+			Id:                   "sub_1",
+			Status:               SubscriptionStatusActive,
+			CurrencyCode:         CurrencyCodeUSD,
+			CurrentBillingPeriod: &TimePeriod{StartsAt: periodStart, EndsAt: periodEnd},
+			NextBilledAt:         &periodEnd,
+			Items:                []SubscriptionItem{{Status: SubscriptionItemStatusActive, Quantity: 2, Recurring: true, Price: *basic}},
+		}
+	}
+	scenarios := map[string][]UpdateSubscriptionItem{
+		"upgrade":           {{PriceId: pro.Id, Quantity: 2}},
+		"downgrade":         {{PriceId: starter.Id, Quantity: 2}},
+		"quantity increase": {{PriceId: basic.Id, Quantity: 5}},
+		"quantity decrease": {{PriceId: basic.Id, Quantity: 1}},
+		"unchanged":         {{PriceId: basic.Id, Quantity: 2}},
+		"one-time addition": {{PriceId: basic.Id, Quantity: 2}, {PriceId: setup.Id, Quantity: 1}},
+	}
+
+	const (
+		prorated     = ProrationBillingModeProratedImmediately
+		proratedNext = ProrationBillingModeProratedNextBillingPeriod
+		full         = ProrationBillingModeFullImmediately
+		fullNext     = ProrationBillingModeFullNextBillingPeriod
+		doNotBill    = ProrationBillingModeDoNotBill
+	)
+	// Amounts are in cents with half of the billing period remaining. A
+	// positive result is charged and a negative one credited.
+	tests := []struct {
+		scenario string
+		mode     ProrationBillingMode
+		credit   int64
+		charge   int64
+		result   int64
+	}{
+		{"upgrade", prorated, 1000, 3000, 2000},
+		{"upgrade", proratedNext, 1000, 3000, 2000},
+		{"upgrade", full, 0, 6000, 6000},
+		{"upgrade", fullNext, 0, 6000, 6000},
+		{"upgrade", doNotBill, 0, 0, 0},
+
+		{"downgrade", prorated, 1000, 400, -600},
+		{"downgrade", proratedNext, 1000, 400, -600},
+		{"downgrade", full, 0, 800, 800},
+		{"downgrade", fullNext, 0, 800, 800},
+		{"downgrade", doNotBill, 0, 0, 0},
+
+		{"quantity increase", prorated, 1000, 2500, 1500},
+		{"quantity increase", proratedNext, 1000, 2500, 1500},
+		{"quantity increase", full, 0, 5000, 5000},
+		{"quantity increase", fullNext, 0, 5000, 5000},
+		{"quantity increase", doNotBill, 0, 0, 0},
+
+		{"quantity decrease", prorated, 1000, 500, -500},
+		{"quantity decrease", proratedNext, 1000, 500, -500},
+		{"quantity decrease", full, 0, 1000, 1000},
+		{"quantity decrease", doNotBill, 0, 0, 0},
+
+		{"unchanged", prorated, 0, 0, 0},
+		{"unchanged", full, 0, 0, 0},
+
+		{"one-time addition", prorated, 0, 500, 500},
+		{"one-time addition", full, 0, 500, 500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.scenario+"/"+string(tt.mode), func(t *testing.T) {
+			got, err := newSubscription().EstimateUpdate(scenarios[tt.scenario], tt.mode, midCycle, pro, starter, setup)
+			if err != nil {
+				t.Fatalf("EstimateUpdate() error = %v", err)
+			}
+			summary := got.UpdateSummary
+			if summary.Credit.Amount.Cmp(NewAmount(tt.credit)) != 0 || summary.Charge.Amount.Cmp(NewAmount(tt.charge)) != 0 {
+				t.Errorf("credit, charge = %s, %s, want %d, %d", summary.Credit.Amount, summary.Charge.Amount, tt.credit, tt.charge)
+			}
+			wantAction, wantAmount := SubscriptionChangeResultActionCharge, tt.result
+			if tt.result < 0 {
+				wantAction, wantAmount = SubscriptionChangeResultActionCredit, -tt.result
+			}
+			if summary.Result.Action != wantAction || summary.Result.Amount.Cmp(NewAmount(wantAmount)) != 0 {
+				t.Errorf("result = %s %s, want %s %d", summary.Result.Action, summary.Result.Amount, wantAction, wantAmount)
+			}
+			if got.Remaining != 0.5 {
+				t.Errorf("Remaining = %v, want 0.5", got.Remaining)
+			}
+
+			var wantBilledAt *time.Time
+			switch {
+			case tt.credit == 0 && tt.charge == 0:
+			case tt.mode == prorated || tt.mode == full:
+				wantBilledAt = &midCycle
+			default:
+				wantBilledAt = &periodEnd
+			}
+			if (got.BilledAt == nil) != (wantBilledAt == nil) || got.BilledAt != nil && !got.BilledAt.Equal(*wantBilledAt) {
+				t.Errorf("BilledAt = %v, want %v", got.BilledAt, wantBilledAt)
+			}
+		})
+	}
+}
+
+func TestEstimateUpdateRemaining(t *testing.T) {
+	periodStart := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := periodStart.AddDate(0, 0, 30)
+	sub := &Subscription{
+		Status:               SubscriptionStatusActive,
+		CurrencyCode:         CurrencyCodeUSD,
+		CurrentBillingPeriod: &TimePeriod{StartsAt: periodStart, EndsAt: periodEnd},
+		Items: []SubscriptionItem{{
+			Status: SubscriptionItemStatusActive, Quantity: 1, Recurring: true,
+			Price: Price{Id: "pri_basic", BillingCycle: &TimeInterval{Frequency: 1, Interval: TimePeriodIntervalMonth}, UnitPrice: Money{Amount: NewAmount(1000), CurrencyCode: CurrencyCodeUSD}},
+		}},
+	}
+	tests := []struct {
+		name   string
+		at     time.Time
+		credit int64
+	}{
+		{"before the period", periodStart.Add(-time.Hour), 1000},
+		{"at the start", periodStart, 1000},
+		{"a third remaining rounds", periodStart.AddDate(0, 0, 20), 333},
+		{"two thirds remaining rounds", periodStart.AddDate(0, 0, 10), 667},
+		{"at the end", periodEnd, 0},
+		{"after the period", periodEnd.Add(time.Hour), 0},
+	}
+	for _, tt := range tests {
+		got, err := sub.EstimateUpdate(nil, ProrationBillingModeProratedImmediately, tt.at)
+		if err != nil {
+			t.Fatalf("%s: EstimateUpdate() error = %v", tt.name, err)
+		}
+		if got.UpdateSummary.Credit.Amount.Cmp(NewAmount(tt.credit)) != 0 {
+			t.Errorf("%s: credit = %s, want %d", tt.name, got.UpdateSummary.Credit.Amount, tt.credit)
+		}
+	}
+}
+
+func TestEstimateUpdateErrors(t *testing.T) {
+	sub := &Subscription{
+		Status:       SubscriptionStatusActive,
+		CurrencyCode: CurrencyCodeUSD,
+		Items: []SubscriptionItem{{
+			Status: SubscriptionItemStatusActive, Quantity: 1, Recurring: true,
+			Price: Price{Id: "pri_basic", UnitPrice: Money{Amount: NewAmount(1000), CurrencyCode: CurrencyCodeUSD}},
+		}},
+	}
+	euro := &Price{Id: "pri_euro", UnitPrice: Money{Amount: NewAmount(900), CurrencyCode: CurrencyCodeEUR}}
+	tests := []struct {
+		name   string
+		sub    *Subscription
+		items  []UpdateSubscriptionItem
+		mode   ProrationBillingMode
+		target error
+	}{
+		{"unknown mode", sub, nil, "prorated_later", ErrInvalidParams},
+		{"price not given", sub, []UpdateSubscriptionItem{{PriceId: "pri_other", Quantity: 1}}, ProrationBillingModeFullImmediately, ErrInvalidParams},
+		{"currency mismatch", sub, []UpdateSubscriptionItem{{PriceId: euro.Id, Quantity: 1}}, ProrationBillingModeFullImmediately, ErrCurrencyMismatch},
+		{"canceled", &Subscription{Status: SubscriptionStatusCanceled}, nil, ProrationBillingModeFullImmediately, ErrInvalidTransition},
+		{"paused", &Subscription{Status: SubscriptionStatusPaused}, nil, ProrationBillingModeFullImmediately, ErrInvalidTransition},
+	}
+	for _, tt := range tests {
+		if _, err := tt.sub.EstimateUpdate(tt.items, tt.mode, time.Now(), euro); !errors.Is(err, tt.target) {
+			t.Errorf("%s: EstimateUpdate() error = %v, want %v", tt.name, err, tt.target)
+		}
+	}
+}
+
+func TestEstimateUpdateTrial(t *testing.T) {
+	sub := &Subscription{
+		Status:       SubscriptionStatusTrialing,
+		CurrencyCode: CurrencyCodeUSD,
+		Items: []SubscriptionItem{{
+			Status: SubscriptionItemStatusTrialing, Quantity: 1, Recurring: true,
+			Price: Price{Id: "pri_basic", UnitPrice: Money{Amount: NewAmount(1000), CurrencyCode: CurrencyCodeUSD}},
+		}},
+	}
+	got, err := sub.EstimateUpdate([]UpdateSubscriptionItem{{PriceId: "pri_basic", Quantity: 4}}, ProrationBillingModeFullImmediately, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.UpdateSummary.Charge.IsZero() || got.BilledAt != nil {
+		t.Errorf("trial estimate = %+v, want nothing billed", got)
+	}
+}
+
+// previewCassettes holds PreviewUpdate responses recorded from the sandbox,
+// which TestEstimateUpdateMatchesPreviews compares EstimateUpdate against.
+// Recording is a separate step:
+//
+//	PADDLE_SANDBOX_API_KEY=... PADDLE_SANDBOX_SUBSCRIPTION_ID=... \
+//	PADDLE_SANDBOX_UPGRADE_PRICE_ID=... PADDLE_SANDBOX_DOWNGRADE_PRICE_ID=... \
+//	go test -run TestRecordPreviews -record-previews
+//
+// The subscription must be active and its customer not charged tax, as
+// estimates exclude it. The subscription is not changed, and paddlerecord
+// redacts credentials and contact details from the cassette.
+const previewCassettes = "testdata/previews"
+
+var recordPreviewsFlag = flag.Bool("record-previews", false, "record sandbox PreviewUpdate responses into "+previewCassettes)
+
+// previewTolerance allows for Paddle measuring the billing period to the
+// millisecond while a cassette records the time of a preview to the second.
+var previewTolerance = NewAmount(2)
+
+func TestEstimateUpdateMatchesPreviews(t *testing.T) {
+	paths, _ := filepath.Glob(filepath.Join(previewCassettes, "*.json"))
+	if len(paths) == 0 {
+		t.Skipf("no recorded previews in %s; record them with -record-previews", previewCassettes)
+	}
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			data, readErr := os.ReadFile(path)
+			if readErr != nil {
+				t.Fatal(readErr)
+			}
+			var cassette paddlerecord.Cassette
+			if jsonErr := json.Unmarshal(data, &cassette); jsonErr != nil {
+				t.Fatal(jsonErr)
+			}
+			comparePreviews(t, &cassette)
+		})
+	}
+}
+
+func TestRecordPreviews(t *testing.T) {
+	if !*recordPreviewsFlag {
+		t.Skip("recording is enabled with -record-previews")
+	}
+	for _, key := range []string{"PADDLE_SANDBOX_API_KEY", "PADDLE_SANDBOX_SUBSCRIPTION_ID", "PADDLE_SANDBOX_UPGRADE_PRICE_ID", "PADDLE_SANDBOX_DOWNGRADE_PRICE_ID"} {
+		if os.Getenv(key) == "" {
+			t.Fatalf("%s is required to record previews", key)
+		}
+	}
+	if mkdirErr := os.MkdirAll(previewCassettes, 0o755); mkdirErr != nil {
+		t.Fatal(mkdirErr)
+	}
+	recordPreviews(t, filepath.Join(previewCassettes, os.Getenv("PADDLE_SANDBOX_SUBSCRIPTION_ID")+".json"))
+}
+
+func recordPreviews(t *testing.T, path string) {
+	rec, recErr := paddlerecord.New(path, paddlerecord.ModeRecord)
+	if recErr != nil {
+		t.Fatal(recErr)
+	}
+	defer func() {
+		if stopErr := rec.Stop(); stopErr != nil {
+			t.Fatal(stopErr)
+		}
+	}()
+	client := NewClient(&Config{Sandbox: true, APIKey: os.Getenv("PADDLE_SANDBOX_API_KEY"), HttpClient: rec.Client()})
+	ctx := context.Background()
+
+	sub, subErr := client.Subscriptions.Get(ctx, os.Getenv("PADDLE_SANDBOX_SUBSCRIPTION_ID"))
+	if subErr != nil {
+		t.Fatal(subErr)
+	}
+	if len(sub.Items) == 0 {
+		t.Fatalf("subscription %s has no items", sub.Id)
+	}
+	var priceIds []string
+	for _, key := range []string{"PADDLE_SANDBOX_UPGRADE_PRICE_ID", "PADDLE_SANDBOX_DOWNGRADE_PRICE_ID"} {
+		price, priceErr := client.Prices.Get(ctx, os.Getenv(key), false)
+		if priceErr != nil {
+			t.Fatalf("%s: %v", key, priceErr)
+		}
+		priceIds = append(priceIds, price.Id)
+	}
+
+	// Each scenario replaces the first item, keeping any others.
+	first := sub.Items[0]
+	scenarios := [][]UpdateSubscriptionItem{
+		{{PriceId: priceIds[0], Quantity: first.Quantity}},
+		{{PriceId: priceIds[1], Quantity: first.Quantity}},
+		{{PriceId: first.Price.Id, Quantity: first.Quantity + 1}},
+	}
+	for _, items := range scenarios {
+		for _, item := range sub.Items[1:] {
+			items = append(items, UpdateSubscriptionItem{PriceId: item.Price.Id, Quantity: item.Quantity})
+		}
+		for _, mode := range []ProrationBillingMode{
+			ProrationBillingModeProratedImmediately,
+			ProrationBillingModeProratedNextBillingPeriod,
+			ProrationBillingModeFullImmediately,
+			ProrationBillingModeFullNextBillingPeriod,
+			ProrationBillingModeDoNotBill,
+		} {
+			params := &UpdateSubscriptionParams{Items: &items, ProrationBillingMode: mode}
+			if _, previewErr := client.Subscriptions.PreviewUpdate(ctx, sub.Id, params); previewErr != nil {
+				t.Fatalf("preview %s: %v", mode, previewErr)
+			}
+		}
+	}
+}
+
+// comparePreviews estimates each preview in the cassette from the recorded
+// subscription and prices, at the time the preview was returned.
+func comparePreviews(t *testing.T, cassette *paddlerecord.Cassette) {
+	var sub *Subscription
+	var prices []*Price
+	decode := func(body json.RawMessage, v any) {
+		t.Helper()
+		envelope := struct {
+			Data any `json:"data"`
+		}{Data: v}
+		if jsonErr := json.Unmarshal(body, &envelope); jsonErr != nil {
+			t.Fatal(jsonErr)
+		}
+	}
+	previews := 0
+	for _, interaction := range cassette.Interactions {
+		req, res := interaction.Request, interaction.Response
+		switch {
+		case req.Method == http.MethodGet && strings.HasPrefix(req.Path, "/subscriptions/"):
+			decode(res.Body, &sub)
+		case req.Method == http.MethodGet && strings.HasPrefix(req.Path, "/prices/"):
+			var price *Price
+			decode(res.Body, &price)
+			prices = append(prices, price)
+		case req.Method == http.MethodPatch && strings.HasSuffix(req.Path, "/preview"):
+			previews++
+			if sub == nil {
+				t.Fatal("preview recorded before its subscription")
+			}
+			if res.StatusCode != http.StatusOK {
+				t.Fatalf("recorded preview failed with %d: %s", res.StatusCode, res.Body)
+			}
+			var params UpdateSubscriptionParams
+			if jsonErr := json.Unmarshal(req.Body, &params); jsonErr != nil || params.Items == nil {
+				t.Fatalf("recorded preview request %s: %v", req.Body, jsonErr)
+			}
+			var preview *SubscriptionUpdatePreview
+			decode(res.Body, &preview)
+			at, dateErr := http.ParseTime(res.Headers.Get("Date"))
+			if dateErr != nil {
+				t.Fatalf("recorded preview has no Date: %v", dateErr)
+			}
+
+			estimate, estimateErr := sub.EstimateUpdate(*params.Items, params.ProrationBillingMode, at, prices...)
+			if estimateErr != nil {
+				t.Fatalf("%s: EstimateUpdate() error = %v", params.ProrationBillingMode, estimateErr)
+			}
+			got, want := estimate.UpdateSummary, preview.UpdateSummary
+			if !near(got.Credit.Amount, want.Credit.Amount) || !near(got.Charge.Amount, want.Charge.Amount) ||
+				!near(got.Result.Amount, want.Result.Amount) ||
+				(want.Result.Amount.Sign() != 0 && got.Result.Action != want.Result.Action) {
+				t.Errorf("%s %s: estimated credit %s, charge %s, %s %s; Paddle previewed credit %s, charge %s, %s %s",
+					params.ProrationBillingMode, req.Body,
+					got.Credit.Amount, got.Charge.Amount, got.Result.Action, got.Result.Amount,
+					want.Credit.Amount, want.Charge.Amount, want.Result.Action, want.Result.Amount)
+			}
+		}
+	}
+	if previews == 0 {
+		t.Error("cassette has no previews")
+	}
+}
+
+func near(a Amount, b Amount) bool {
+	diff := a.Sub(b)
+	if diff.Sign() < 0 {
+		diff = diff.Neg()
+	}
+	return diff.Cmp(previewTolerance) <= 0
+}
@@ -0,0 +1,168 @@
+package paddle
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// queryEncoder is implemented by parameter types which encode to one or
+// more query parameters derived from their field's key.
+type queryEncoder interface {
+	encodeQuery(key string, q url.Values)
+}
+
+var queryEncoderType = reflect.TypeOf((*queryEncoder)(nil)).Elem()
+
+// encodeQuery encodes params, a struct or pointer to a struct, using its
+// `query` field tags:
+//
+//	Ids           []string `query:"id"`                    // id=a,b
+//	Status        []Status `query:"status"`                // status=active
+//	IncludePrices bool     `query:"include,value=prices"`  // include=prices
+//
+// Empty values are omitted unless set through a pointer, slices are
+// comma-joined and bool fields with a value option are appended to a
+// comma-joined list. Untagged struct fields are flattened into the parent.
+// Enum values are checked with checkEnum.
+func encodeQuery(params any, allowUnknown bool) (url.Values, error) {
+	q := url.Values{}
+	lists := map[string][]string{}
+	if err := encodeQueryStruct(reflect.ValueOf(params), q, lists, allowUnknown); err != nil {
+		return nil, err
+	}
+	for key, values := range lists {
+		q.Set(key, strings.Join(values, ","))
+	}
+	return q, nil
+}
+
+// withQuery appends the encoded params to endpoint, allowing unknown enum
+// values if the context was made with WithUnknownEnums.
+func withQuery(ctx context.Context, endpoint string, params any) (string, error) {
+	q, err := encodeQuery(params, unknownEnumsAllowed(ctx))
+	if err != nil {
+		return "", err
+	}
+	if len(q) == 0 {
+		return endpoint, nil
+	}
+	return endpoint + "?" + q.Encode(), nil
+}
+
+func encodeQueryStruct(v reflect.Value, q url.Values, lists map[string][]string, allowUnknown bool) error {
+	if !v.IsValid() {
+		return nil
+	}
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("query params must be a struct, got %s", v.Type())
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag, hasTag := field.Tag.Lookup("query")
+		if !hasTag {
+			if indirectKind(field.Type) == reflect.Struct && !field.Type.Implements(queryEncoderType) {
+				if err := encodeQueryStruct(v.Field(i), q, lists, allowUnknown); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		key, opts, _ := strings.Cut(tag, ",")
+		if key == "-" {
+			continue
+		}
+		if err := encodeQueryField(key, opts, v.Field(i), q, lists, allowUnknown); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func indirectKind(t reflect.Type) reflect.Kind {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind()
+}
+
+func encodeQueryField(key string, opts string, v reflect.Value, q url.Values, lists map[string][]string, allowUnknown bool) error {
+	// A non-nil pointer is sent even when it points to a zero value, e.g. a
+	// *bool filter set to false.
+	explicit := v.Kind() == reflect.Pointer
+	if explicit {
+		if v.IsNil() {
+			return nil
+		}
+		if encoder, ok := v.Interface().(queryEncoder); ok {
+			encoder.encodeQuery(key, q)
+			return nil
+		}
+		v = v.Elem()
+	}
+	if encoder, ok := v.Interface().(queryEncoder); ok {
+		encoder.encodeQuery(key, q)
+		return nil
+	}
+
+	value, hasValue := strings.CutPrefix(opts, "value=")
+	switch v.Kind() {
+	case reflect.Bool:
+		if hasValue {
+			if v.Bool() {
+				lists[key] = append(lists[key], value)
+			}
+			return nil
+		}
+		if v.Bool() || explicit {
+			q.Set(key, strconv.FormatBool(v.Bool()))
+		}
+	case reflect.String:
+		if v.Len() == 0 {
+			return nil
+		}
+		if err := checkQueryEnum(key, v, allowUnknown); err != nil {
+			return err
+		}
+		q.Set(key, v.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Int() != 0 {
+			q.Set(key, strconv.FormatInt(v.Int(), 10))
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			elem := v.Index(i)
+			if elem.Kind() != reflect.String {
+				return fmt.Errorf("unsupported query slice type %s for %s", v.Type(), key)
+			}
+			if err := checkQueryEnum(key, elem, allowUnknown); err != nil {
+				return err
+			}
+			lists[key] = append(lists[key], elem.String())
+		}
+	default:
+		return fmt.Errorf("unsupported query type %s for %s", v.Type(), key)
+	}
+	return nil
+}
+
+func checkQueryEnum(key string, v reflect.Value, allowUnknown bool) error {
+	if e, ok := v.Interface().(Enum); ok {
+		return checkEnum(key, e, allowUnknown)
+	}
+	return nil
+}
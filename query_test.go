@@ -0,0 +1,158 @@
+package paddle
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type QueryPage struct {
+	Search string `query:"search"`
+}
+
+type queryParams struct {
+	QueryPage
+	Page     *QueryPage
+	Ids      []string             `query:"id"`
+	Status   []Status             `query:"status"`
+	Mode     ProrationBillingMode `query:"mode"`
+	Name     *string              `query:"name"`
+	Count    int                  `query:"count"`
+	Archived bool                 `query:"archived"`
+	Flag     *bool                `query:"flag"`
+	Prices   bool                 `query:"include,value=prices"`
+	Product  bool                 `query:"include,value=product"`
+	Ignored  string               `query:"-"`
+	After    *TimeFilter          `query:"created_at"`
+	Until    *TimeFilter          `query:"updated_at"`
+	private  string
+}
+
+func TestEncodeQuery(t *testing.T) {
+	at := time.Date(2024, 3, 1, 10, 30, 0, 0, time.FixedZone("CET", 3600))
+	end := at.AddDate(0, 1, 0)
+	name := "a b"
+	yes, no := true, false
+
+	tests := []struct {
+		name   string
+		params any
+		want   string
+	}{
+		{"nil", (*queryParams)(nil), ""},
+		{"empty", &queryParams{}, ""},
+		{"value struct", queryParams{Ids: []string{"a"}}, "id=a"},
+		{"comma-joined slice", &queryParams{Ids: []string{"a", "b", "c"}}, "id=a%2Cb%2Cc"},
+		{"enum slice", &queryParams{Status: []Status{StatusActive, StatusArchived}}, "status=active%2Carchived"},
+		{"enum", &queryParams{Mode: ProrationBillingModeFullImmediately}, "mode=full_immediately"},
+		{"pointer string", &queryParams{Name: &name}, "name=a+b"},
+		{"int", &queryParams{Count: 5}, "count=5"},
+		{"bool", &queryParams{Archived: true}, "archived=true"},
+		{"pointer bool true", &queryParams{Flag: &yes}, "flag=true"},
+		{"pointer bool false", &queryParams{Flag: &no}, "flag=false"},
+		{"value option", &queryParams{Prices: true}, "include=prices"},
+		{"value options joined", &queryParams{Prices: true, Product: true}, "include=prices%2Cproduct"},
+		{"ignored field", &queryParams{Ignored: "x", private: "y"}, ""},
+		{"embedded struct", &queryParams{QueryPage: QueryPage{Search: "bob"}}, "search=bob"},
+		{"untagged struct pointer", &queryParams{Page: &QueryPage{Search: "bob"}}, "search=bob"},
+		{"before", &queryParams{After: Before(at)}, "created_at%5BLT%5D=2024-03-01T09%3A30%3A00Z"},
+		{"on or before", &queryParams{After: OnOrBefore(at)}, "created_at%5BLTE%5D=2024-03-01T09%3A30%3A00Z"},
+		{"after", &queryParams{After: After(at)}, "created_at%5BGT%5D=2024-03-01T09%3A30%3A00Z"},
+		{"on or after", &queryParams{After: OnOrAfter(at)}, "created_at%5BGTE%5D=2024-03-01T09%3A30%3A00Z"},
+		{"between", &queryParams{Until: Between(at, end)},
+			"updated_at%5BGTE%5D=2024-03-01T09%3A30%3A00Z&updated_at%5BLT%5D=2024-04-01T09%3A30%3A00Z"},
+		{"transaction includes", &TransactionIncludeParam{Address: true, Adjustment: true, AdjustmentTotals: true, Business: true, Customer: true, Discount: true},
+			"include=address%2Cadjustments%2Cadjustments_totals%2Cbusiness%2Ccustomer%2Cdiscount"},
+		{"list transactions", &ListTransactionsParams{
+			Include:         &TransactionIncludeParam{Customer: true},
+			CollectionMode:  PaymentCollectionModeAutomatic,
+			SubscriptionIds: []string{"sub_1", "sub_2"},
+			Status:          []TransactionStatus{TransactionStatusCompleted},
+			BilledAt:        OnOrAfter(at),
+		}, "billed_at%5BGTE%5D=2024-03-01T09%3A30%3A00Z&collection_mode=automatic&include=customer&status=completed&subscription_id=sub_1%2Csub_2"},
+		{"list prices", &ListPricesParams{IncludeProduct: true, ProductIds: []string{"pro_1"}, Recurring: &no},
+			"include=product&product_id=pro_1&recurring=false"},
+		{"pagination", &ListCustomersParams{After: "ctm_1", PerPage: 50, OrderBy: "id[DESC]"},
+			"after=ctm_1&order_by=id%5BDESC%5D&per_page=50"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := encodeQuery(tt.params, false)
+			if err != nil {
+				t.Fatalf("encodeQuery() error = %v", err)
+			}
+			if got := q.Encode(); got != tt.want {
+				t.Errorf("encodeQuery() = %q, want %q", got, tt.want)
+			}
+			parsed, parseErr := url.ParseQuery(tt.want)
+			if parseErr != nil {
+				t.Fatal(parseErr)
+			}
+			if len(q) > 0 && !reflect.DeepEqual(q, parsed) {
+				t.Errorf("encodeQuery() does not round-trip: got %v, want %v", q, parsed)
+			}
+		})
+	}
+}
+
+func TestEncodeQueryErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		params any
+		target error
+	}{
+		{"malformed enum", &queryParams{Mode: "full immediately"}, ErrInvalidParams},
+		{"malformed enum in slice", &queryParams{Status: []Status{StatusActive, "no/pe"}}, ErrInvalidParams},
+		{"unknown enum", &queryParams{Mode: "prorated_immediatly"}, ErrInvalidParams},
+		{"unknown enum in slice", &queryParams{Status: []Status{StatusActive, "archive"}}, ErrInvalidParams},
+		{"not a struct", []string{"a"}, nil},
+		{"unsupported field", &struct {
+			Ratio float64 `query:"ratio"`
+		}{Ratio: 1}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := encodeQuery(tt.params, false)
+			if err == nil {
+				t.Fatal("encodeQuery() error = nil")
+			}
+			if tt.target != nil && !errors.Is(err, tt.target) {
+				t.Errorf("encodeQuery() error = %v, want %v", err, tt.target)
+			}
+		})
+	}
+}
+
+func TestWithQuery(t *testing.T) {
+	tests := []struct {
+		params any
+		want   string
+	}{
+		{nil, "transactions"},
+		{(*ListTransactionsParams)(nil), "transactions"},
+		{&ListTransactionsParams{}, "transactions"},
+		{&ListTransactionsParams{Ids: []string{"txn_1"}, Include: &TransactionIncludeParam{Adjustment: true}},
+			"transactions?id=txn_1&include=adjustments"},
+	}
+	for _, tt := range tests {
+		got, err := withQuery(context.Background(), "transactions", tt.params)
+		if err != nil {
+			t.Fatalf("withQuery(%#v) error = %v", tt.params, err)
+		}
+		if got != tt.want {
+			t.Errorf("withQuery(%#v) = %q, want %q", tt.params, got, tt.want)
+		}
+	}
+
+	newer := &ListTransactionsParams{Status: []TransactionStatus{"refunded"}}
+	if _, err := withQuery(context.Background(), "transactions", newer); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("withQuery() with an unknown status = %v, want %v", err, ErrInvalidParams)
+	}
+	got, err := withQuery(WithUnknownEnums(context.Background()), "transactions", newer)
+	if err != nil || got != "transactions?status=refunded" {
+		t.Errorf("withQuery() with WithUnknownEnums = %q, %v, want transactions?status=refunded", got, err)
+	}
+}
@@ -0,0 +1,89 @@
+package paddle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = 30 * time.Second
+)
+
+func shouldRetry(ctx context.Context, req *http.Request, res *ApiResponse, err error) bool {
+	if err == nil || ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if isMutating(req.Method) && req.Header.Get(IdempotencyKeyHeader) == "" {
+		return false
+	}
+	var apiErr *ApiError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= http.StatusInternalServerError
+	}
+	return isTransportError(err)
+}
+
+// isTransportError reports whether err is a network failure which may
+// succeed if repeated, as opposed to e.g. a response which failed to decode.
+func isTransportError(err error) bool {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF)
+}
+
+// retryDelay returns how long to wait before the given retry, honouring a
+// Retry-After header sent with err.
+func retryDelay(attempt int, err error) time.Duration {
+	var apiErr *ApiError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter
+	}
+	delay := retryBaseDelay << attempt
+	if delay <= 0 || delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)))
+}
+
+// RetryMiddleware retries requests which failed due to a network error, rate
+// limiting or a server error, with exponential backoff or after the delay
+// given by a Retry-After header. Mutating requests are only retried when
+// they carry an idempotency key, and cancelled or timed out requests are
+// never retried.
+func RetryMiddleware(maxRetries int) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *http.Request) (*ApiResponse, error) {
+			res, err := next(ctx, req)
+			for attempt := 0; attempt < maxRetries && shouldRetry(ctx, req, res, err); attempt++ {
+				if req.GetBody != nil {
+					body, bodyErr := req.GetBody()
+					if bodyErr != nil {
+						return res, errors.Join(err, bodyErr)
+					}
+					req.Body = body
+				}
+
+				timer := time.NewTimer(retryDelay(attempt, err))
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return res, errors.Join(err, ctx.Err())
+				case <-timer.C:
+				}
+
+				res, err = next(ctx, req)
+			}
+			return res, err
+		}
+	}
+}
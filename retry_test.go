@@ -0,0 +1,117 @@
+package paddle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShouldRetry(t *testing.T) {
+	get, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	post, _ := http.NewRequest(http.MethodPost, "https://example.com", nil)
+	keyed, _ := http.NewRequest(http.MethodPost, "https://example.com", nil)
+	keyed.Header.Set(IdempotencyKeyHeader, "key")
+
+	dialErr := &url.Error{Op: "Get", URL: "https://example.com", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}
+	tests := []struct {
+		name string
+		req  *http.Request
+		err  error
+		want bool
+	}{
+		{"success", get, nil, false},
+		{"dial error", get, dialErr, true},
+		{"unexpected eof", get, io.ErrUnexpectedEOF, true},
+		{"eof from transport", get, &url.Error{Op: "Get", Err: io.EOF}, true},
+		{"rate limited", get, &ApiError{StatusCode: http.StatusTooManyRequests}, true},
+		{"server error", get, &ApiError{StatusCode: http.StatusBadGateway}, true},
+		{"client error", get, &ApiError{StatusCode: http.StatusBadRequest}, false},
+		{"decode error", get, fmt.Errorf("http 200: failed to read response: %w", &json.SyntaxError{}), false},
+		{"transport rejected request", get, &url.Error{Op: "Get", Err: errors.New("unsupported protocol scheme")}, false},
+		{"canceled", get, &url.Error{Op: "Get", Err: context.Canceled}, false},
+		{"deadline exceeded", get, errors.Join(context.DeadlineExceeded, dialErr), false},
+		{"mutation without key", post, dialErr, false},
+		{"mutation with key", keyed, dialErr, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shouldRetry(context.Background(), tt.req, nil, tt.err); got != tt.want {
+				t.Errorf("shouldRetry(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if shouldRetry(ctx, get, nil, dialErr) {
+		t.Error("shouldRetry after the context was cancelled = true, want false")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"", 0},
+		{"3", 3 * time.Second},
+		{"-1", 0},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"soon", 0},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.header, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	if got := retryDelay(0, &ApiError{StatusCode: http.StatusTooManyRequests, RetryAfter: 7 * time.Second}); got != 7*time.Second {
+		t.Errorf("retryDelay with Retry-After = %v, want 7s", got)
+	}
+	for attempt := 0; attempt < 10; attempt++ {
+		base := min(retryBaseDelay<<attempt, retryMaxDelay)
+		if got := retryDelay(attempt, errors.New("failed")); got < base/2 || got > base {
+			t.Errorf("retryDelay(%d) = %v, want between %v and %v", attempt, got, base/2, base)
+		}
+	}
+}
+
+func TestRetryMiddleware(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch calls.Add(1) {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"error":{"type":"request_error","code":"too_many_requests","detail":"slow down"}}`)
+		case 2:
+			fmt.Fprint(w, `not json`)
+		default:
+			fmt.Fprint(w, `{"data":{}}`)
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClient(&Config{BaseURL: srv.URL, MaxRetries: 3})
+	err := client.TestAuthentication(context.Background())
+	if err == nil || !errors.As(err, new(*json.SyntaxError)) {
+		t.Fatalf("TestAuthentication() = %v, want the decode error of the second response", err)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("server called %d times, want 2: the decode error must not be retried", n)
+	}
+}
@@ -0,0 +1,57 @@
+package paddle
+
+import (
+	"net/url"
+	"time"
+)
+
+type timeFilterOp string
+
+const (
+	timeFilterLT  = timeFilterOp("LT")
+	timeFilterLTE = timeFilterOp("LTE")
+	timeFilterGT  = timeFilterOp("GT")
+	timeFilterGTE = timeFilterOp("GTE")
+)
+
+type timeBound struct {
+	op timeFilterOp
+	t  time.Time
+}
+
+// TimeFilter filters a list by a timestamp field, encoding as Paddle's
+// operator syntax, e.g. created_at[GT]=2024-01-01T00:00:00Z.
+type TimeFilter struct {
+	bounds []timeBound
+}
+
+// Before matches times strictly before t.
+func Before(t time.Time) *TimeFilter {
+	return &TimeFilter{bounds: []timeBound{{timeFilterLT, t}}}
+}
+
+// OnOrBefore matches times before or equal to t.
+func OnOrBefore(t time.Time) *TimeFilter {
+	return &TimeFilter{bounds: []timeBound{{timeFilterLTE, t}}}
+}
+
+// After matches times strictly after t.
+func After(t time.Time) *TimeFilter {
+	return &TimeFilter{bounds: []timeBound{{timeFilterGT, t}}}
+}
+
+// OnOrAfter matches times after or equal to t.
+func OnOrAfter(t time.Time) *TimeFilter {
+	return &TimeFilter{bounds: []timeBound{{timeFilterGTE, t}}}
+}
+
+// Between matches times from start (inclusive) up to end (exclusive).
+func Between(start time.Time, end time.Time) *TimeFilter {
+	return &TimeFilter{bounds: []timeBound{{timeFilterGTE, start}, {timeFilterLT, end}}}
+}
+
+func (f *TimeFilter) encodeQuery(key string, q url.Values) {
+	for _, b := range f.bounds {
+		q.Set(key+"["+string(b.op)+"]", b.t.UTC().Format(time.RFC3339))
+	}
+}
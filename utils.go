@@ -3,16 +3,38 @@ package paddle
 import (
 	"context"
 	"encoding/json"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"strings"
 )
 
+type paramsValidator interface {
+	validate(allowUnknown bool) error
+}
+
 func makeApiRequest[T any](ctx context.Context, c *Client, method string, endpoint string, body any) (*T, *ApiResponse, error) {
+	if v, ok := body.(paramsValidator); ok {
+		if validErr := v.validate(unknownEnumsAllowed(ctx)); validErr != nil {
+			return nil, nil, validErr
+		}
+	}
+	if isMutating(method) {
+		if checkErr := c.checkTransition(ctx, body); checkErr != nil {
+			return nil, nil, checkErr
+		}
+	}
 	req, reqErr := c.NewRequest(method, endpoint, body)
 	if reqErr != nil {
 		return nil, nil, reqErr
 	}
-	res, resErr := c.Do(ctx, req)
+	if keyErr := c.setIdempotencyKey(ctx, req); keyErr != nil {
+		return nil, nil, keyErr
+	}
+	if version, ok := APIVersionFromContext(ctx); ok {
+		setAPIVersion(req, version)
+	}
+	res, resErr := c.handler(ctx, req)
 	if resErr != nil {
 		return nil, res, resErr
 	}
@@ -20,29 +42,73 @@ func makeApiRequest[T any](ctx context.Context, c *Client, method string, endpoi
 	if jsonErr := json.Unmarshal(res.Data, &item); jsonErr != nil {
 		return nil, res, jsonErr
 	}
+	if c.logger.Enabled(ctx, slog.LevelWarn) {
+		for _, unknown := range UnknownEnumValues(item) {
+			c.logger.WarnContext(ctx, "paddle: unknown enum value",
+				slog.String("path", req.URL.Path),
+				slog.String("field", unknown.Path),
+				slog.String("type", unknown.Type),
+				slog.String("value", unknown.Value))
+		}
+	}
 	return item, res, nil
 }
 
-func postItem[T any](ctx context.Context, c *Client, endpoint string, body any) (*T, error) {
+func postItem[T any](ctx context.Context, c *Client, op Operation, endpoint string, body any) (*T, error) {
+	ctx, end := c.startOperation(ctx, op)
 	item, _, err := makeApiRequest[T](ctx, c, http.MethodPost, endpoint, body)
+	end(err)
 	return item, err
 }
 
-func patchItem[T any](ctx context.Context, c *Client, endpoint string, body any) (*T, error) {
+func patchItem[T any](ctx context.Context, c *Client, op Operation, endpoint string, body any) (*T, error) {
+	ctx, end := c.startOperation(ctx, op)
 	item, _, err := makeApiRequest[T](ctx, c, http.MethodPatch, endpoint, body)
+	end(err)
 	return item, err
 }
 
-func getItem[T any](ctx context.Context, c *Client, endpoint string) (*T, error) {
+func getItem[T any](ctx context.Context, c *Client, op Operation, endpoint string) (*T, error) {
+	ctx, end := c.startOperation(ctx, op)
 	item, _, err := makeApiRequest[T](ctx, c, http.MethodGet, endpoint, nil)
+	end(err)
 	return item, err
 }
 
-func listItems[T any](ctx context.Context, c *Client, basePath string) ([]*T, error) {
+func listItems[T any](ctx context.Context, c *Client, op Operation, basePath string) ([]*T, error) {
+	ctx, end := c.startOperation(ctx, op)
+	items, err := listPages[T](ctx, c, basePath)
+	end(err)
+	return items, err
+}
+
+// relativePath converts an absolute URL returned by Paddle, such as the next
+// page of a list, to a path relative to the client's base URL. The base URL
+// may include a path prefix which Paddle itself is unaware of.
+func (c *Client) relativePath(absolute string) string {
+	if rel, ok := strings.CutPrefix(absolute, c.baseURL); ok {
+		return rel
+	}
+	u, parseErr := url.Parse(absolute)
+	if parseErr != nil {
+		return absolute
+	}
+	rel := u.EscapedPath()
+	if base, baseErr := url.Parse(c.baseURL); baseErr == nil {
+		rel = strings.TrimPrefix(rel, base.EscapedPath())
+	}
+	rel = strings.TrimPrefix(rel, "/")
+	if u.RawQuery != "" {
+		rel += "?" + u.RawQuery
+	}
+	return rel
+}
+
+func listPages[T any](ctx context.Context, c *Client, basePath string) ([]*T, error) {
 	curPath := basePath
 	hasMore := true
 	var items []*T
-	for hasMore {
+	for page := 1; hasMore; page++ {
 		resItems, res, resErr := makeApiRequest[[]T](ctx, c, http.MethodGet, curPath, nil)
 		if resErr != nil {
 			return nil, resErr
@@ -56,8 +122,14 @@ func listItems[T any](ctx context.Context, c *Client, basePath string) ([]*T, er
 		}
 
 		hasMore = res.Meta.Pagination.HasMore
+		c.logger.DebugContext(ctx, "paddle: fetched page",
+			slog.String("path", curPath),
+			slog.Int("page", page),
+			slog.Int("items", len(items)),
+			slog.Int("estimated_total", res.Meta.Pagination.EstimatedTotal),
+			slog.Bool("has_more", hasMore))
 		if hasMore {
-			curPath = strings.TrimPrefix(res.Meta.Pagination.Next, c.baseURL)
+			curPath = c.relativePath(res.Meta.Pagination.Next)
 		}
 	}
 	return items, nil
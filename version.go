@@ -0,0 +1,37 @@
+package paddle
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+// Version is the version of this library, reported in the User-Agent header.
+const Version = "0.1.0"
+
+const APIVersionHeader = "Paddle-Version"
+
+const (
+	APIVersion1 = 1
+
+	// DefaultAPIVersion is the version the models in this package are
+	// written against, sent when Config.APIVersion is not set.
+	DefaultAPIVersion = APIVersion1
+)
+
+type apiVersionKey struct{}
+
+// WithAPIVersion overrides the Paddle API version for requests made with the
+// returned context.
+func WithAPIVersion(ctx context.Context, version int) context.Context {
+	return context.WithValue(ctx, apiVersionKey{}, version)
+}
+
+func APIVersionFromContext(ctx context.Context) (int, bool) {
+	version, ok := ctx.Value(apiVersionKey{}).(int)
+	return version, ok && version > 0
+}
+
+func setAPIVersion(req *http.Request, version int) {
+	req.Header.Set(APIVersionHeader, strconv.Itoa(version))
+}
@@ -1,14 +1,19 @@
 package paddle
 
 import (
+	"bytes"
+	"context"
 	"crypto/hmac"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -16,10 +21,45 @@ import (
 var (
 	ErrInvalidHeader    = errors.New("invalid header")
 	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrWebhookExpired   = errors.New("webhook signature timestamp outside tolerance")
+	ErrWebhookTooLarge  = errors.New("webhook body too large")
 )
 
 const (
-	MaxWebhookBodyBytes = int64(65536)
+	MaxWebhookBodyBytes    = int64(65536)
+	WebhookSignatureHeader = "Paddle-Signature"
+
+	// DefaultWebhookTolerance is how far a signature timestamp may be from
+	// the current time when Config.WebhookTolerance is not set.
+	DefaultWebhookTolerance = 5 * time.Second
+)
+
+const (
+	EventTypeCustomerCreated          = "customer.created"
+	EventTypeCustomerUpdated          = "customer.updated"
+	EventTypeAddressCreated           = "address.created"
+	EventTypeAddressUpdated           = "address.updated"
+	EventTypeProductCreated           = "product.created"
+	EventTypeProductUpdated           = "product.updated"
+	EventTypePriceCreated             = "price.created"
+	EventTypePriceUpdated             = "price.updated"
+	EventTypeSubscriptionCreated      = "subscription.created"
+	EventTypeSubscriptionActivated    = "subscription.activated"
+	EventTypeSubscriptionUpdated      = "subscription.updated"
+	EventTypeSubscriptionTrialing     = "subscription.trialing"
+	EventTypeSubscriptionPastDue      = "subscription.past_due"
+	EventTypeSubscriptionPaused       = "subscription.paused"
+	EventTypeSubscriptionResumed      = "subscription.resumed"
+	EventTypeSubscriptionCanceled     = "subscription.canceled"
+	EventTypeTransactionCreated       = "transaction.created"
+	EventTypeTransactionUpdated       = "transaction.updated"
+	EventTypeTransactionReady         = "transaction.ready"
+	EventTypeTransactionBilled        = "transaction.billed"
+	EventTypeTransactionPaid          = "transaction.paid"
+	EventTypeTransactionCompleted     = "transaction.completed"
+	EventTypeTransactionCanceled      = "transaction.canceled"
+	EventTypeTransactionPastDue       = "transaction.past_due"
+	EventTypeTransactionPaymentFailed = "transaction.payment_failed"
 )
 
 type WebhookEvent struct {
@@ -31,21 +71,38 @@ type WebhookEvent struct {
 }
 
 func (c *Client) ParseWebhook(req *http.Request) (*WebhookEvent, error) {
-	sigHeader := req.Header.Get("Paddle-Signature")
+	event, err := c.parseWebhook(req)
+	if err != nil {
+		c.logger.WarnContext(req.Context(), "paddle: webhook verification failed",
+			slog.String("remote_addr", req.RemoteAddr),
+			slog.Any("error", err))
+		return nil, err
+	}
+	return event, nil
+}
+
+func (c *Client) parseWebhook(req *http.Request) (*WebhookEvent, error) {
+	sigHeader := req.Header.Get(WebhookSignatureHeader)
 	sig, providedErr := getWebhookSignature(sigHeader)
 	if providedErr != nil {
 		return nil, providedErr
 	}
 
-	bodyReader := io.LimitReader(req.Body, MaxWebhookBodyBytes)
+	bodyReader := io.LimitReader(req.Body, MaxWebhookBodyBytes+1)
 	body, readErr := io.ReadAll(bodyReader)
 	if readErr != nil {
 		return nil, fmt.Errorf("failed to read body: %w", readErr)
 	}
+	if int64(len(body)) > MaxWebhookBodyBytes {
+		return nil, fmt.Errorf("%w: exceeds %d bytes", ErrWebhookTooLarge, MaxWebhookBodyBytes)
+	}
 
 	if validationErr := sig.validate(c.webhookKey, body); validationErr != nil {
 		return nil, fmt.Errorf("failed to validate request: %w", validationErr)
 	}
+	if expiredErr := sig.checkTimestamp(time.Now(), c.webhookTolerance); expiredErr != nil {
+		return nil, expiredErr
+	}
 
 	var event WebhookEvent
 	if jsonErr := json.Unmarshal(body, &event); jsonErr != nil {
@@ -61,21 +118,133 @@ type signature struct {
 }
 
 func (w *signature) validate(key []byte, body []byte) error {
-	hash := hmac.New(sha256.New, key)
-	prefix := []byte(w.timestamp + ":")
-	if _, pfxErr := hash.Write(prefix); pfxErr != nil {
-		return fmt.Errorf("failed to write hash prefix: %w", pfxErr)
-	}
-	if _, bodyErr := hash.Write(body); bodyErr != nil {
-		return fmt.Errorf("failed to write hash body: %w", bodyErr)
+	provided, decodeErr := hex.DecodeString(w.providedSignature)
+	if decodeErr != nil {
+		return ErrInvalidSignature
 	}
-	sum := hash.Sum(nil)
-	if hex.EncodeToString(sum) != w.providedSignature {
+	if !hmac.Equal(signatureSum(key, w.timestamp, body), provided) {
 		return ErrInvalidSignature
 	}
 	return nil
 }
 
+// checkTimestamp rejects a signature made more than tolerance from now. A
+// negative tolerance disables the check.
+func (w *signature) checkTimestamp(now time.Time, tolerance time.Duration) error {
+	if tolerance < 0 {
+		return nil
+	}
+	unix, parseErr := strconv.ParseInt(w.timestamp, 10, 64)
+	if parseErr != nil {
+		return ErrInvalidHeader
+	}
+	signedAt := time.Unix(unix, 0)
+	if skew := now.Sub(signedAt).Abs(); skew > tolerance {
+		return fmt.Errorf("%w: signed at %s", ErrWebhookExpired, signedAt.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+func signatureSum(key []byte, timestamp string, body []byte) []byte {
+	hash := hmac.New(sha256.New, key)
+	hash.Write([]byte(timestamp + ":"))
+	hash.Write(body)
+	return hash.Sum(nil)
+}
+
+// SignWebhook returns the Paddle-Signature header value Paddle would send
+// for body, signed with the endpoint's secret key at timestamp.
+func SignWebhook(secret string, timestamp time.Time, body []byte) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	return "ts=" + ts + ";h1=" + hex.EncodeToString(signatureSum([]byte(secret), ts, body))
+}
+
+// WebhookRequest builds a signed webhook delivery, as Paddle would send it,
+// for exercising handlers through ParseWebhook.
+//
+//	req, err := (&paddle.WebhookRequest{
+//		Secret:    "pdl_ntfset_...",
+//		EventType: paddle.EventTypeSubscriptionCanceled,
+//		Data:      sub,
+//	}).Build(ctx, "http://localhost:8080/webhooks")
+type WebhookRequest struct {
+	Secret    string
+	EventType string
+	Data      any
+
+	// EventId and NotificationId are generated when empty.
+	EventId        string
+	NotificationId string
+	// OccurredAt defaults to the current time.
+	OccurredAt time.Time
+	// SignedAt is the signature timestamp and defaults to the current time,
+	// as ParseWebhook rejects signatures outside Config.WebhookTolerance.
+	SignedAt time.Time
+}
+
+// Event returns the event the request delivers.
+func (w *WebhookRequest) Event() (*WebhookEvent, error) {
+	data, jsonErr := json.Marshal(w.Data)
+	if jsonErr != nil {
+		return nil, fmt.Errorf("failed to marshal webhook data: %w", jsonErr)
+	}
+	event := &WebhookEvent{
+		Id:             w.EventId,
+		Type:           w.EventType,
+		OccurredAt:     w.OccurredAt,
+		NotificationId: w.NotificationId,
+		Data:           data,
+	}
+	if event.Id == "" {
+		event.Id = newResourceId("evt")
+	}
+	if event.NotificationId == "" {
+		event.NotificationId = newResourceId("ntf")
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+	return event, nil
+}
+
+// Build returns a POST request to url carrying the event and a valid
+// Paddle-Signature header.
+func (w *WebhookRequest) Build(ctx context.Context, url string) (*http.Request, error) {
+	event, eventErr := w.Event()
+	if eventErr != nil {
+		return nil, eventErr
+	}
+	body, jsonErr := json.Marshal(event)
+	if jsonErr != nil {
+		return nil, jsonErr
+	}
+	if int64(len(body)) > MaxWebhookBodyBytes {
+		return nil, fmt.Errorf("webhook body of %d bytes exceeds %d", len(body), MaxWebhookBodyBytes)
+	}
+
+	req, reqErr := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if reqErr != nil {
+		return nil, reqErr
+	}
+	signedAt := w.SignedAt
+	if signedAt.IsZero() {
+		signedAt = time.Now()
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(w.Secret, signedAt, body))
+	return req, nil
+}
+
+func newResourceId(prefix string) string {
+	const alphabet = "0123456789abcdefghijklmnopqrstuvwxyz"
+	b := make([]byte, 26)
+	_, _ = rand.Read(b)
+	for i := range b {
+		b[i] = alphabet[int(b[i])%len(alphabet)]
+	}
+	return prefix + "_" + string(b)
+}
+
 func getWebhookSignature(raw string) (*signature, error) {
 	elements := strings.Split(raw, ";")
 	if len(elements) != 2 {
@@ -0,0 +1,116 @@
+package paddle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseWebhook(t *testing.T) {
+	const secret = "pdl_ntfset_test"
+	now := time.Now()
+	customer := Customer{Id: "ctm_1", Email: "ada@example.com"}
+
+	tests := []struct {
+		name      string
+		cfg       Config
+		req       WebhookRequest
+		oversized bool
+		header    string
+		wantErr   error
+	}{
+		{name: "round trip", req: WebhookRequest{Secret: secret}},
+		{name: "wrong secret", req: WebhookRequest{Secret: "pdl_ntfset_other"}, wantErr: ErrInvalidSignature},
+		{name: "expired timestamp", req: WebhookRequest{Secret: secret, SignedAt: now.Add(-time.Minute)}, wantErr: ErrWebhookExpired},
+		{name: "future timestamp", req: WebhookRequest{Secret: secret, SignedAt: now.Add(time.Minute)}, wantErr: ErrWebhookExpired},
+		{
+			name: "within configured tolerance",
+			cfg:  Config{WebhookTolerance: time.Hour},
+			req:  WebhookRequest{Secret: secret, SignedAt: now.Add(-time.Minute)},
+		},
+		{
+			name: "tolerance disabled",
+			cfg:  Config{WebhookTolerance: -1},
+			req:  WebhookRequest{Secret: secret, SignedAt: now.AddDate(-1, 0, 0)},
+		},
+		{name: "oversized body", req: WebhookRequest{Secret: secret}, oversized: true, wantErr: ErrWebhookTooLarge},
+		{name: "missing header", req: WebhookRequest{Secret: secret}, header: "", wantErr: ErrInvalidHeader},
+		{name: "malformed header", req: WebhookRequest{Secret: secret}, header: "ts=1;h1", wantErr: ErrInvalidHeader},
+		{name: "non-hex signature", req: WebhookRequest{Secret: secret}, header: "ts=1;h1=zz", wantErr: ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			cfg.WebhookSecretKey = secret
+			client := NewClient(&cfg)
+
+			tt.req.EventType = EventTypeCustomerCreated
+			tt.req.Data = customer
+			req, buildErr := tt.req.Build(context.Background(), "http://localhost/webhooks")
+			if buildErr != nil {
+				t.Fatal(buildErr)
+			}
+			if tt.oversized {
+				// Build refuses oversized bodies, so sign one directly.
+				body := []byte(`{"event_type":"` + strings.Repeat("x", int(MaxWebhookBodyBytes)) + `"}`)
+				req, _ = http.NewRequest(http.MethodPost, "http://localhost/webhooks", bytes.NewReader(body))
+				req.Header.Set(WebhookSignatureHeader, SignWebhook(secret, now, body))
+			}
+			if tt.header != "" || tt.wantErr == ErrInvalidHeader {
+				req.Header.Set(WebhookSignatureHeader, tt.header)
+			}
+
+			event, err := client.ParseWebhook(req)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseWebhook() = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseWebhook() = %v", err)
+			}
+			if event.Type != EventTypeCustomerCreated || event.Id == "" || event.NotificationId == "" {
+				t.Errorf("ParseWebhook() = %+v, want a customer.created event with IDs", event)
+			}
+			var got Customer
+			if err := json.Unmarshal(event.Data, &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Id != customer.Id || got.Email != customer.Email {
+				t.Errorf("event data = %+v, want %+v", got, customer)
+			}
+		})
+	}
+}
+
+func TestWebhookRequestBuildTooLarge(t *testing.T) {
+	_, err := (&WebhookRequest{
+		Secret:    "pdl_ntfset_test",
+		EventType: EventTypeCustomerCreated,
+		Data:      strings.Repeat("x", int(MaxWebhookBodyBytes)),
+	}).Build(context.Background(), "http://localhost/webhooks")
+	if err == nil {
+		t.Fatal("Build() of an oversized event succeeded, want an error")
+	}
+}
+
+func TestSignWebhook(t *testing.T) {
+	body := []byte(`{"event_id":"evt_1"}`)
+	at := time.Unix(1700000000, 0)
+	got := SignWebhook("secret", at, body)
+	if !strings.HasPrefix(got, "ts=1700000000;h1=") || len(got) != len("ts=1700000000;h1=")+64 {
+		t.Fatalf("SignWebhook() = %q, want ts=1700000000 and a hex SHA-256", got)
+	}
+	if SignWebhook("secret", at, body) != got {
+		t.Error("SignWebhook() is not deterministic")
+	}
+	if SignWebhook("other", at, body) == got || SignWebhook("secret", at.Add(time.Second), body) == got {
+		t.Error("SignWebhook() ignores the secret or timestamp")
+	}
+}
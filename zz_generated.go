@@ -0,0 +1,1351 @@
+// Code generated by paddlegen from openapi/paddle.json. DO NOT EDIT.
+
+package paddle
+
+import (
+	"context"
+	"time"
+)
+
+// A customer address used for tax calculation and invoicing.
+type Address struct {
+	Id          string     `json:"id"`
+	CustomerId  string     `json:"customer_id"`
+	Description *string    `json:"description"`
+	FirstLine   *string    `json:"first_line"`
+	SecondLine  *string    `json:"second_line"`
+	City        *string    `json:"city"`
+	PostalCode  *string    `json:"postal_code"`
+	Region      *string    `json:"region"`
+	CountryCode string     `json:"country_code"`
+	CustomData  CustomData `json:"custom_data"`
+	Status      Status     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// A credit, refund or chargeback against a transaction.
+type Adjustment struct {
+	Id                     string                  `json:"id"`
+	Action                 AdjustmentAction        `json:"action"`
+	TransactionId          string                  `json:"transaction_id"`
+	SubscriptionId         *string                 `json:"subscription_id"`
+	CustomerId             string                  `json:"customer_id"`
+	Reason                 string                  `json:"reason"`
+	CreditAppliedToBalance *bool                   `json:"credit_applied_to_balance"`
+	CurrencyCode           CurrencyCode            `json:"currency_code"`
+	Status                 AdjustmentStatus        `json:"status"`
+	Items                  []AdjustmentItem        `json:"items"`
+	Totals                 AdjustmentTotals        `json:"totals"`
+	PayoutTotals           *AdjustmentPayoutTotals `json:"payout_totals"`
+	CreatedAt              time.Time               `json:"created_at"`
+	UpdatedAt              time.Time               `json:"updated_at"`
+}
+
+// The kind of adjustment made to a billed or completed transaction.
+type AdjustmentAction string
+
+const (
+	AdjustmentActionCredit            = AdjustmentAction("credit")
+	AdjustmentActionCreditReverse     = AdjustmentAction("credit_reverse")
+	AdjustmentActionRefund            = AdjustmentAction("refund")
+	AdjustmentActionChargeback        = AdjustmentAction("chargeback")
+	AdjustmentActionChargebackReverse = AdjustmentAction("chargeback_reverse")
+	AdjustmentActionChargebackWarning = AdjustmentAction("chargeback_warning")
+)
+
+func (v AdjustmentAction) IsValid() bool { return isValidEnum(v) }
+func (v AdjustmentAction) IsKnown() bool {
+	return isKnownEnum(v, AdjustmentActionCredit, AdjustmentActionCreditReverse, AdjustmentActionRefund, AdjustmentActionChargeback, AdjustmentActionChargebackReverse, AdjustmentActionChargebackWarning)
+}
+
+type AdjustmentItem struct {
+	Id        string               `json:"id"`
+	ItemId    string               `json:"item_id"`
+	Type      AdjustmentItemType   `json:"type"`
+	Amount    Amount               `json:"amount"`
+	Proration *Proration           `json:"proration"`
+	Totals    AdjustmentItemTotals `json:"totals"`
+}
+
+type AdjustmentItemTotals struct {
+	Subtotal Amount `json:"subtotal"`
+	Tax      Amount `json:"tax"`
+	Total    Amount `json:"total"`
+}
+
+// How much of a transaction item is adjusted.
+type AdjustmentItemType string
+
+const (
+	AdjustmentItemTypeFull      = AdjustmentItemType("full")
+	AdjustmentItemTypePartial   = AdjustmentItemType("partial")
+	AdjustmentItemTypeTax       = AdjustmentItemType("tax")
+	AdjustmentItemTypeProration = AdjustmentItemType("proration")
+)
+
+func (v AdjustmentItemType) IsValid() bool { return isValidEnum(v) }
+func (v AdjustmentItemType) IsKnown() bool {
+	return isKnownEnum(v, AdjustmentItemTypeFull, AdjustmentItemTypePartial, AdjustmentItemTypeTax, AdjustmentItemTypeProration)
+}
+
+type AdjustmentPayoutTotals struct {
+	Subtotal      Amount                                       `json:"subtotal"`
+	Tax           Amount                                       `json:"tax"`
+	Total         Amount                                       `json:"total"`
+	Fee           Amount                                       `json:"fee"`
+	ChargebackFee TransactionAdjustedPayoutTotalsChargebackFee `json:"chargeback_fee"`
+	Earnings      Amount                                       `json:"earnings"`
+	CurrencyCode  CurrencyCode                                 `json:"currency_code"`
+}
+
+// Whether an adjustment has been approved.
+type AdjustmentStatus string
+
+const (
+	AdjustmentStatusPendingApproval = AdjustmentStatus("pending_approval")
+	AdjustmentStatusApproved        = AdjustmentStatus("approved")
+	AdjustmentStatusRejected        = AdjustmentStatus("rejected")
+	AdjustmentStatusReversed        = AdjustmentStatus("reversed")
+)
+
+func (v AdjustmentStatus) IsValid() bool { return isValidEnum(v) }
+func (v AdjustmentStatus) IsKnown() bool {
+	return isKnownEnum(v, AdjustmentStatusPendingApproval, AdjustmentStatusApproved, AdjustmentStatusRejected, AdjustmentStatusReversed)
+}
+
+type AdjustmentTotals struct {
+	Subtotal     Amount       `json:"subtotal"`
+	Tax          Amount       `json:"tax"`
+	Total        Amount       `json:"total"`
+	Fee          Amount       `json:"fee"`
+	Earnings     Amount       `json:"earnings"`
+	CurrencyCode CurrencyCode `json:"currency_code"`
+}
+
+// A customer business used for tax calculation and invoicing.
+type Business struct {
+	Id            string            `json:"id"`
+	CustomerId    string            `json:"customer_id"`
+	Name          string            `json:"name"`
+	CompanyNumber *string           `json:"company_number"`
+	TaxIdentifier *string           `json:"tax_identifier"`
+	Status        Status            `json:"status"`
+	Contacts      []BusinessContact `json:"contacts"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+	CustomData    CustomData        `json:"custom_data"`
+}
+
+type BusinessContact struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type CancelSubscriptionParams struct {
+	EffectiveFrom SubscriptionEffectFromOption `json:"effective_from"`
+}
+
+type CardDetails struct {
+	Type           CardType `json:"type"`
+	Last4          string   `json:"last4"`
+	ExpiryMonth    int      `json:"expiry_month"`
+	ExpiryYear     int      `json:"expiry_year"`
+	CardholderName string   `json:"cardholder_name"`
+}
+
+// The scheme of a card.
+type CardType string
+
+const (
+	CardTypeAmericanExpress = CardType("american_express")
+	CardTypeDinersClub      = CardType("diners_club")
+	CardTypeDiscover        = CardType("discover")
+	CardTypeJcb             = CardType("jcb")
+	CardTypeMada            = CardType("mada")
+	CardTypeMaestro         = CardType("maestro")
+	CardTypeMastercard      = CardType("mastercard")
+	CardTypeUnionPay        = CardType("union_pay")
+	CardTypeUnknown         = CardType("unknown")
+	CardTypeVisa            = CardType("visa")
+)
+
+func (v CardType) IsValid() bool { return isValidEnum(v) }
+func (v CardType) IsKnown() bool {
+	return isKnownEnum(v, CardTypeAmericanExpress, CardTypeDinersClub, CardTypeDiscover, CardTypeJcb, CardTypeMada, CardTypeMaestro, CardTypeMastercard, CardTypeUnionPay, CardTypeUnknown, CardTypeVisa)
+}
+
+type Checkout struct {
+	Url *string `json:"url"`
+}
+
+type CreateCustomerParams struct {
+	Email      string      `json:"email"`
+	Name       *string     `json:"name,omitempty"`
+	CustomData *CustomData `json:"custom_data,omitempty"`
+	Locale     *string     `json:"locale,omitempty"`
+}
+
+type CreatePriceParams struct {
+	ProductId          string                  `json:"product_id"`
+	Description        string                  `json:"description"`
+	Name               *string                 `json:"name,omitempty"`
+	BillingCycle       *TimeInterval           `json:"billing_cycle,omitempty"`
+	TrialPeriod        *TimeInterval           `json:"trial_period,omitempty"`
+	TaxMode            TaxMode                 `json:"tax_mode,omitempty"`
+	UnitPrice          CurrencyPrice           `json:"unit_price"`
+	UnitPriceOverrides []CurrencyPriceOverride `json:"unit_price_overrides,omitempty"`
+	Quantity           *MinMax                 `json:"quantity,omitempty"`
+	CustomData         *CustomData             `json:"custom_data,omitempty"`
+}
+
+type CreateProductParams struct {
+	Name        string      `json:"name"`
+	TaxCategory TaxCategory `json:"tax_category"`
+	Description *string     `json:"description,omitempty"`
+	ImageUrl    *string     `json:"image_url,omitempty"`
+	CustomData  *CustomData `json:"custom_data,omitempty"`
+}
+
+type CurrencyPriceAction struct {
+	Amount       Amount                         `json:"amount"`
+	CurrencyCode CurrencyCode                   `json:"currency_code"`
+	Action       SubscriptionChangeResultAction `json:"action"`
+}
+
+type CurrencyPriceOverride struct {
+	CountryCodes []string      `json:"country_codes"`
+	UnitPrice    CurrencyPrice `json:"unit_price"`
+}
+
+// A customer who can be billed for transactions and subscriptions.
+type Customer struct {
+	Id               string     `json:"id"`
+	Status           Status     `json:"status"`
+	CustomData       CustomData `json:"custom_data"`
+	Name             *string    `json:"name"`
+	Email            string     `json:"email"`
+	MarketingConsent bool       `json:"marketing_consent"`
+	Locale           string     `json:"locale"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// A discount applied to a checkout, transaction or subscription.
+type Discount struct {
+	Id                        string         `json:"id"`
+	Status                    DiscountStatus `json:"status"`
+	Description               string         `json:"description"`
+	EnabledForCheckout        bool           `json:"enabled_for_checkout"`
+	Code                      *string        `json:"code"`
+	Type                      DiscountType   `json:"type"`
+	Amount                    Amount         `json:"amount"`
+	CurrencyCode              *CurrencyCode  `json:"currency_code"`
+	Recurring                 bool           `json:"recurring"`
+	MaximumRecurringIntervals *int           `json:"maximum_recurring_intervals"`
+	UsageLimit                *int           `json:"usage_limit"`
+	RestrictTo                []string       `json:"restrict_to"`
+	ExpiresAt                 *time.Time     `json:"expires_at"`
+	CustomData                CustomData     `json:"custom_data"`
+	TimesUsed                 int            `json:"times_used"`
+	CreatedAt                 time.Time      `json:"created_at"`
+	UpdatedAt                 time.Time      `json:"updated_at"`
+}
+
+// Whether a discount can be applied.
+type DiscountStatus string
+
+const (
+	DiscountStatusActive   = DiscountStatus("active")
+	DiscountStatusArchived = DiscountStatus("archived")
+	DiscountStatusExpired  = DiscountStatus("expired")
+	DiscountStatusUsed     = DiscountStatus("used")
+)
+
+func (v DiscountStatus) IsValid() bool { return isValidEnum(v) }
+func (v DiscountStatus) IsKnown() bool {
+	return isKnownEnum(v, DiscountStatusActive, DiscountStatusArchived, DiscountStatusExpired, DiscountStatusUsed)
+}
+
+// How a discount amount is applied.
+type DiscountType string
+
+const (
+	DiscountTypeFlat        = DiscountType("flat")
+	DiscountTypeFlatPerSeat = DiscountType("flat_per_seat")
+	DiscountTypePercentage  = DiscountType("percentage")
+)
+
+func (v DiscountType) IsValid() bool { return isValidEnum(v) }
+func (v DiscountType) IsKnown() bool {
+	return isKnownEnum(v, DiscountTypeFlat, DiscountTypeFlatPerSeat, DiscountTypePercentage)
+}
+
+type MinMax struct {
+	Minimum int `json:"minimum"`
+	Maximum int `json:"maximum"`
+}
+
+type PauseSubscriptionParams struct {
+	EffectiveFrom SubscriptionEffectFromOption `json:"effective_from"`
+	ResumeAt      *time.Time                   `json:"resume_at,omitempty"`
+}
+
+// The status of a payment attempt.
+type PaymentAttemptStatus string
+
+const (
+	PaymentAttemptStatusActionRequired          = PaymentAttemptStatus("action_required")
+	PaymentAttemptStatusAuthorized              = PaymentAttemptStatus("authorized")
+	PaymentAttemptStatusAuthorizedFlagged       = PaymentAttemptStatus("authorized_flagged")
+	PaymentAttemptStatusCanceled                = PaymentAttemptStatus("canceled")
+	PaymentAttemptStatusCaptured                = PaymentAttemptStatus("captured")
+	PaymentAttemptStatusCreated                 = PaymentAttemptStatus("created")
+	PaymentAttemptStatusDropped                 = PaymentAttemptStatus("dropped")
+	PaymentAttemptStatusError                   = PaymentAttemptStatus("error")
+	PaymentAttemptStatusPendingNoActionRequired = PaymentAttemptStatus("pending_no_action_required")
+	PaymentAttemptStatusUnknown                 = PaymentAttemptStatus("unknown")
+)
+
+func (v PaymentAttemptStatus) IsValid() bool { return isValidEnum(v) }
+func (v PaymentAttemptStatus) IsKnown() bool {
+	return isKnownEnum(v, PaymentAttemptStatusActionRequired, PaymentAttemptStatusAuthorized, PaymentAttemptStatusAuthorizedFlagged, PaymentAttemptStatusCanceled, PaymentAttemptStatusCaptured, PaymentAttemptStatusCreated, PaymentAttemptStatusDropped, PaymentAttemptStatusError, PaymentAttemptStatusPendingNoActionRequired, PaymentAttemptStatusUnknown)
+}
+
+// How payment is collected: automatically from a saved payment method, or manually by invoice.
+type PaymentCollectionMode string
+
+const (
+	PaymentCollectionModeAutomatic = PaymentCollectionMode("automatic")
+	PaymentCollectionModeManual    = PaymentCollectionMode("manual")
+)
+
+func (v PaymentCollectionMode) IsValid() bool { return isValidEnum(v) }
+func (v PaymentCollectionMode) IsKnown() bool {
+	return isKnownEnum(v, PaymentCollectionModeAutomatic, PaymentCollectionModeManual)
+}
+
+type PaymentMethod struct {
+	Type PaymentMethodType `json:"type"`
+	Card *CardDetails      `json:"card"`
+}
+
+// The type of payment method used for a payment attempt.
+type PaymentMethodType string
+
+const (
+	PaymentMethodTypeAlipay       = PaymentMethodType("alipay")
+	PaymentMethodTypeApplePay     = PaymentMethodType("apple_pay")
+	PaymentMethodTypeBancontact   = PaymentMethodType("bancontact")
+	PaymentMethodTypeCard         = PaymentMethodType("card")
+	PaymentMethodTypeGooglePay    = PaymentMethodType("google_pay")
+	PaymentMethodTypeIdeal        = PaymentMethodType("ideal")
+	PaymentMethodTypeOffline      = PaymentMethodType("offline")
+	PaymentMethodTypePaypal       = PaymentMethodType("paypal")
+	PaymentMethodTypeUnknown      = PaymentMethodType("unknown")
+	PaymentMethodTypeWireTransfer = PaymentMethodType("wire_transfer")
+)
+
+func (v PaymentMethodType) IsValid() bool { return isValidEnum(v) }
+func (v PaymentMethodType) IsKnown() bool {
+	return isKnownEnum(v, PaymentMethodTypeAlipay, PaymentMethodTypeApplePay, PaymentMethodTypeBancontact, PaymentMethodTypeCard, PaymentMethodTypeGooglePay, PaymentMethodTypeIdeal, PaymentMethodTypeOffline, PaymentMethodTypePaypal, PaymentMethodTypeUnknown, PaymentMethodTypeWireTransfer)
+}
+
+// How much and how often a product is charged for.
+type Price struct {
+	Id                 string                  `json:"id"`
+	ProductId          string                  `json:"product_id"`
+	Description        string                  `json:"description"`
+	Name               string                  `json:"name"`
+	BillingCycle       *TimeInterval           `json:"billing_cycle"`
+	TrialPeriod        *TimeInterval           `json:"trial_period"`
+	TaxMode            TaxMode                 `json:"tax_mode"`
+	UnitPrice          CurrencyPrice           `json:"unit_price"`
+	UnitPriceOverrides []CurrencyPriceOverride `json:"unit_price_overrides"`
+	CustomData         CustomData              `json:"custom_data"`
+	Status             Status                  `json:"status"`
+	Quantity           MinMax                  `json:"quantity"`
+	CreatedAt          time.Time               `json:"created_at"`
+	UpdatedAt          time.Time               `json:"updated_at"`
+	Product            *Product                `json:"product"`
+}
+
+// An item that customers can buy, with one or more prices.
+type Product struct {
+	Id          string      `json:"id"`
+	Name        string      `json:"name"`
+	Description *string     `json:"description"`
+	TaxCategory TaxCategory `json:"tax_category"`
+	ImageUrl    *string     `json:"image_url"`
+	CustomData  CustomData  `json:"custom_data"`
+	Status      Status      `json:"status"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+	Prices      *[]*Price   `json:"prices"`
+}
+
+type Proration struct {
+	Rate          string     `json:"rate"`
+	BillingPeriod TimePeriod `json:"billing_period"`
+}
+
+// How Paddle bills for changes made to a subscription.
+type ProrationBillingMode string
+
+const (
+	ProrationBillingModeProratedImmediately       = ProrationBillingMode("prorated_immediately")
+	ProrationBillingModeProratedNextBillingPeriod = ProrationBillingMode("prorated_next_billing_period")
+	ProrationBillingModeFullImmediately           = ProrationBillingMode("full_immediately")
+	ProrationBillingModeFullNextBillingPeriod     = ProrationBillingMode("full_next_billing_period")
+	ProrationBillingModeDoNotBill                 = ProrationBillingMode("do_not_bill")
+)
+
+func (v ProrationBillingMode) IsValid() bool { return isValidEnum(v) }
+func (v ProrationBillingMode) IsKnown() bool {
+	return isKnownEnum(v, ProrationBillingModeProratedImmediately, ProrationBillingModeProratedNextBillingPeriod, ProrationBillingModeFullImmediately, ProrationBillingModeFullNextBillingPeriod, ProrationBillingModeDoNotBill)
+}
+
+type ResumeSubscriptionParams struct {
+	EffectiveFrom SubscriptionEffectFromOption `json:"effective_from"`
+}
+
+// Whether an entity can be used.
+type Status string
+
+const (
+	StatusActive   = Status("active")
+	StatusArchived = Status("archived")
+)
+
+func (v Status) IsValid() bool { return isValidEnum(v) }
+func (v Status) IsKnown() bool {
+	return isKnownEnum(v, StatusActive, StatusArchived)
+}
+
+// A recurring billing agreement between a customer and the seller.
+type Subscription struct {
+	Id                   string                       `json:"id"`
+	Status               SubscriptionStatus           `json:"status"`
+	CustomerId           string                       `json:"customer_id"`
+	AddressId            string                       `json:"address_id"`
+	BusinessId           *string                      `json:"business_id"`
+	CurrencyCode         CurrencyCode                 `json:"currency_code"`
+	CreatedAt            time.Time                    `json:"created_at"`
+	UpdatedAt            time.Time                    `json:"updated_at"`
+	StartedAt            *time.Time                   `json:"started_at"`
+	FirstBilledAt        *time.Time                   `json:"first_billed_at"`
+	NextBilledAt         *time.Time                   `json:"next_billed_at"`
+	PausedAt             *time.Time                   `json:"paused_at"`
+	CanceledAt           *time.Time                   `json:"canceled_at"`
+	Discount             *SubscriptionDiscount        `json:"discount"`
+	CollectionMode       PaymentCollectionMode        `json:"collection_mode"`
+	BillingDetails       *SubscriptionBillingDetails  `json:"billing_details"`
+	CurrentBillingPeriod *TimePeriod                  `json:"current_billing_period"`
+	BillingCycle         TimeInterval                 `json:"billing_cycle"`
+	ScheduledChange      *SubscriptionScheduledChange `json:"scheduled_change"`
+	Items                []SubscriptionItem           `json:"items"`
+	CustomData           CustomData                   `json:"custom_data"`
+	ManagementUrls       SubscriptionManagementUrls   `json:"management_urls"`
+}
+
+type SubscriptionBillingDetails struct {
+	EnableCheckout        bool         `json:"enable_checkout"`
+	PurchaseOrderNumber   string       `json:"purchase_order_number"`
+	AdditionalInformation *string      `json:"additional_information"`
+	PaymentTerms          TimeInterval `json:"payment_terms"`
+}
+
+// Whether a subscription change results in a credit or a charge.
+type SubscriptionChangeResultAction string
+
+const (
+	SubscriptionChangeResultActionCredit = SubscriptionChangeResultAction("credit")
+	SubscriptionChangeResultActionCharge = SubscriptionChangeResultAction("charge")
+)
+
+func (v SubscriptionChangeResultAction) IsValid() bool { return isValidEnum(v) }
+func (v SubscriptionChangeResultAction) IsKnown() bool {
+	return isKnownEnum(v, SubscriptionChangeResultActionCredit, SubscriptionChangeResultActionCharge)
+}
+
+type SubscriptionDiscount struct {
+	Id       string     `json:"id"`
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+}
+
+// When a subscription change takes effect.
+type SubscriptionEffectFromOption string
+
+const (
+	SubscriptionEffectFromOptionImmediately       = SubscriptionEffectFromOption("immediately")
+	SubscriptionEffectFromOptionNextBillingPeriod = SubscriptionEffectFromOption("next_billing_period")
+)
+
+func (v SubscriptionEffectFromOption) IsValid() bool { return isValidEnum(v) }
+func (v SubscriptionEffectFromOption) IsKnown() bool {
+	return isKnownEnum(v, SubscriptionEffectFromOptionImmediately, SubscriptionEffectFromOptionNextBillingPeriod)
+}
+
+type SubscriptionItem struct {
+	Status             SubscriptionItemStatus `json:"status"`
+	Quantity           int                    `json:"quantity"`
+	Recurring          bool                   `json:"recurring"`
+	CreatedAt          time.Time              `json:"created_at"`
+	UpdatedAt          time.Time              `json:"updated_at"`
+	PreviouslyBilledAt *time.Time             `json:"previously_billed_at"`
+	NextBilledAt       *time.Time             `json:"next_billed_at"`
+	TrialDates         *TimePeriod            `json:"trial_dates"`
+	Price              Price                  `json:"price"`
+}
+
+// The status of a subscription item.
+type SubscriptionItemStatus string
+
+const (
+	SubscriptionItemStatusActive   = SubscriptionItemStatus("active")
+	SubscriptionItemStatusInactive = SubscriptionItemStatus("inactive")
+	SubscriptionItemStatusTrialing = SubscriptionItemStatus("trialing")
+)
+
+func (v SubscriptionItemStatus) IsValid() bool { return isValidEnum(v) }
+func (v SubscriptionItemStatus) IsKnown() bool {
+	return isKnownEnum(v, SubscriptionItemStatusActive, SubscriptionItemStatusInactive, SubscriptionItemStatusTrialing)
+}
+
+type SubscriptionManagementUrls struct {
+	UpdatePaymentMethod string `json:"update_payment_method"`
+	Cancel              string `json:"cancel"`
+}
+
+type SubscriptionScheduledChange struct {
+	Action      SubscriptionScheduledChangeAction `json:"action"`
+	EffectiveAt time.Time                         `json:"effective_at"`
+	ResumeAt    *time.Time                        `json:"resume_at"`
+}
+
+// The kind of change scheduled for a subscription.
+type SubscriptionScheduledChangeAction string
+
+const (
+	SubscriptionScheduledChangeActionCancel = SubscriptionScheduledChangeAction("cancel")
+	SubscriptionScheduledChangeActionPause  = SubscriptionScheduledChangeAction("pause")
+	SubscriptionScheduledChangeActionResume = SubscriptionScheduledChangeAction("resume")
+)
+
+func (v SubscriptionScheduledChangeAction) IsValid() bool { return isValidEnum(v) }
+func (v SubscriptionScheduledChangeAction) IsKnown() bool {
+	return isKnownEnum(v, SubscriptionScheduledChangeActionCancel, SubscriptionScheduledChangeActionPause, SubscriptionScheduledChangeActionResume)
+}
+
+// The status of a subscription.
+type SubscriptionStatus string
+
+const (
+	SubscriptionStatusActive   = SubscriptionStatus("active")
+	SubscriptionStatusCanceled = SubscriptionStatus("canceled")
+	SubscriptionStatusPastDue  = SubscriptionStatus("past_due")
+	SubscriptionStatusPaused   = SubscriptionStatus("paused")
+	SubscriptionStatusTrialing = SubscriptionStatus("trialing")
+)
+
+func (v SubscriptionStatus) IsValid() bool { return isValidEnum(v) }
+func (v SubscriptionStatus) IsKnown() bool {
+	return isKnownEnum(v, SubscriptionStatusActive, SubscriptionStatusCanceled, SubscriptionStatusPastDue, SubscriptionStatusPaused, SubscriptionStatusTrialing)
+}
+
+// The transactions Paddle would create if a subscription update were applied.
+type SubscriptionUpdatePreview struct {
+	NextBilledAt                *time.Time                            `json:"next_billed_at"`
+	UpdateSummary               SubscriptionUpdatePreviewSummary      `json:"update_summary"`
+	RecurringTransactionDetails TransactionDetails                    `json:"recurring_transaction_details"`
+	NextTransaction             *SubscriptionUpdateTransactionPreview `json:"next_transaction"`
+	ImmediateTransaction        *SubscriptionUpdateTransactionPreview `json:"immediate_transaction"`
+}
+
+type SubscriptionUpdatePreviewSummary struct {
+	Credit CurrencyPrice       `json:"credit"`
+	Charge CurrencyPrice       `json:"charge"`
+	Result CurrencyPriceAction `json:"result"`
+}
+
+type SubscriptionUpdateTransactionPreview struct {
+	BillingPeriod TimePeriod         `json:"billing_period"`
+	Details       TransactionDetails `json:"details"`
+	Adjustments   []any              `json:"adjustments"`
+}
+
+// The tax category of a product.
+type TaxCategory string
+
+const (
+	TaxCategoryDigitalGoods                = TaxCategory("digital-goods")
+	TaxCategoryEbooks                      = TaxCategory("ebooks")
+	TaxCategoryImplementationServices      = TaxCategory("implementation-services")
+	TaxCategoryProfessionalServices        = TaxCategory("professional-services")
+	TaxCategorySaas                        = TaxCategory("saas")
+	TaxCategorySoftwareProgrammingServices = TaxCategory("software-programming-services")
+	TaxCategoryStandard                    = TaxCategory("standard")
+	TaxCategoryTrainingServices            = TaxCategory("training-services")
+	TaxCategoryWebsiteHosting              = TaxCategory("website-hosting")
+)
+
+func (v TaxCategory) IsValid() bool { return isValidEnum(v) }
+func (v TaxCategory) IsKnown() bool {
+	return isKnownEnum(v, TaxCategoryDigitalGoods, TaxCategoryEbooks, TaxCategoryImplementationServices, TaxCategoryProfessionalServices, TaxCategorySaas, TaxCategorySoftwareProgrammingServices, TaxCategoryStandard, TaxCategoryTrainingServices, TaxCategoryWebsiteHosting)
+}
+
+// How tax is calculated for a price.
+type TaxMode string
+
+const (
+	TaxModeAccountSetting = TaxMode("account_setting")
+	TaxModeExternal       = TaxMode("external")
+	TaxModeInternal       = TaxMode("internal")
+)
+
+func (v TaxMode) IsValid() bool { return isValidEnum(v) }
+func (v TaxMode) IsKnown() bool {
+	return isKnownEnum(v, TaxModeAccountSetting, TaxModeExternal, TaxModeInternal)
+}
+
+type TaxRate struct {
+	TaxRate string        `json:"tax_rate"`
+	Totals  TaxRateTotals `json:"totals"`
+}
+
+type TaxRateTotals struct {
+	Subtotal Amount `json:"subtotal"`
+	Discount Amount `json:"discount"`
+	Tax      Amount `json:"tax"`
+	Total    Amount `json:"total"`
+}
+
+type TimeInterval struct {
+	Frequency int                `json:"frequency"`
+	Interval  TimePeriodInterval `json:"interval"`
+}
+
+type TimePeriod struct {
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+}
+
+// A unit of time for billing cycles, trial periods and payment terms.
+type TimePeriodInterval string
+
+const (
+	TimePeriodIntervalDay   = TimePeriodInterval("day")
+	TimePeriodIntervalWeek  = TimePeriodInterval("week")
+	TimePeriodIntervalMonth = TimePeriodInterval("month")
+	TimePeriodIntervalYear  = TimePeriodInterval("year")
+)
+
+func (v TimePeriodInterval) IsValid() bool { return isValidEnum(v) }
+func (v TimePeriodInterval) IsKnown() bool {
+	return isKnownEnum(v, TimePeriodIntervalDay, TimePeriodIntervalWeek, TimePeriodIntervalMonth, TimePeriodIntervalYear)
+}
+
+// A charge to a customer, created by checkout, a subscription or the API.
+type Transaction struct {
+	Id                string                        `json:"id"`
+	Status            TransactionStatus             `json:"status"`
+	CustomerId        *string                       `json:"customer_id"`
+	AddressId         *string                       `json:"address_id"`
+	BusinessId        *string                       `json:"business_id"`
+	SubscriptionId    *string                       `json:"subscription_id"`
+	DiscountId        *string                       `json:"discount_id"`
+	InvoiceId         *string                       `json:"invoice_id"`
+	InvoiceNumber     *string                       `json:"invoice_number"`
+	CurrencyCode      CurrencyCode                  `json:"currency_code"`
+	Origin            TransactionOrigin             `json:"origin"`
+	CollectionMode    PaymentCollectionMode         `json:"collection_mode"`
+	BillingDetails    *TransactionBillingDetails    `json:"billing_details"`
+	BillingPeriod     *TimePeriod                   `json:"billing_period"`
+	Items             []TransactionItem             `json:"items"`
+	Details           TransactionDetails            `json:"details"`
+	Payments          []TransactionPaymentAttempt   `json:"payments"`
+	Checkout          *Checkout                     `json:"checkout"`
+	CreatedAt         time.Time                     `json:"created_at"`
+	UpdatedAt         time.Time                     `json:"updated_at"`
+	BilledAt          *time.Time                    `json:"billed_at"`
+	CustomData        CustomData                    `json:"custom_data"`
+	Adjustments       []Adjustment                  `json:"adjustments"`
+	AdjustmentsTotals *TransactionAdjustmentsTotals `json:"adjustments_totals"`
+	Address           *Address                      `json:"address"`
+	Business          *Business                     `json:"business"`
+	Customer          *Customer                     `json:"customer"`
+	Discount          *Discount                     `json:"discount"`
+}
+
+type TransactionAdjustedPayoutTotals struct {
+	Subtotal      Amount                                       `json:"subtotal"`
+	Tax           Amount                                       `json:"tax"`
+	Total         Amount                                       `json:"total"`
+	Fee           Amount                                       `json:"fee"`
+	ChargebackFee TransactionAdjustedPayoutTotalsChargebackFee `json:"chargeback_fee"`
+	Earnings      Amount                                       `json:"earnings"`
+	CurrencyCode  CurrencyCode                                 `json:"currency_code"`
+}
+
+type TransactionAdjustedPayoutTotalsChargebackFee struct {
+	Amount   Amount         `json:"amount"`
+	Original *CurrencyPrice `json:"original"`
+}
+
+type TransactionAdjustedTotals struct {
+	Subtotal     Amount       `json:"subtotal"`
+	Tax          Amount       `json:"tax"`
+	Total        Amount       `json:"total"`
+	GrandTotal   Amount       `json:"grand_total"`
+	Fee          *Amount      `json:"fee"`
+	Earnings     *Amount      `json:"earnings"`
+	CurrencyCode CurrencyCode `json:"currency_code"`
+}
+
+// Totals of all adjustments made to a transaction.
+type TransactionAdjustmentsTotals struct {
+	Subtotal     Amount                                `json:"subtotal"`
+	Tax          Amount                                `json:"tax"`
+	Total        Amount                                `json:"total"`
+	Fee          Amount                                `json:"fee"`
+	Earnings     Amount                                `json:"earnings"`
+	Breakdown    TransactionAdjustmentsTotalsBreakdown `json:"breakdown"`
+	CurrencyCode CurrencyCode                          `json:"currency_code"`
+}
+
+type TransactionAdjustmentsTotalsBreakdown struct {
+	Credit     Amount `json:"credit"`
+	Refund     Amount `json:"refund"`
+	Chargeback Amount `json:"chargeback"`
+}
+
+type TransactionBillingDetails struct {
+	EnableCheckout        bool         `json:"enable_checkout"`
+	PaymentTerms          TimeInterval `json:"payment_terms"`
+	PurchaseOrderNumber   string       `json:"purchase_order_number"`
+	AdditionalInformation *string      `json:"additional_information"`
+}
+
+type TransactionDetails struct {
+	TaxRatesUsed         []TaxRate                        `json:"tax_rates_used"`
+	Totals               TransactionTotals                `json:"totals"`
+	AdjustedTotals       *TransactionAdjustedTotals       `json:"adjusted_totals"`
+	PayoutTotals         *TransactionPayoutTotals         `json:"payout_totals"`
+	AdjustedPayoutTotals *TransactionAdjustedPayoutTotals `json:"adjusted_payout_totals"`
+	LineItems            []TransactionLineItem            `json:"line_items"`
+}
+
+type TransactionItem struct {
+	Price     Price      `json:"price"`
+	Quantity  int        `json:"quantity"`
+	Proration *Proration `json:"proration"`
+}
+
+type TransactionLineItem struct {
+	Id         string                   `json:"id"`
+	PriceId    string                   `json:"price_id"`
+	Quantity   int                      `json:"quantity"`
+	Proration  *Proration               `json:"proration"`
+	TaxRate    string                   `json:"tax_rate"`
+	Totals     TransactionLineItemTotal `json:"totals"`
+	UnitTotals TransactionLineItemTotal `json:"unit_totals"`
+	Product    Product                  `json:"product"`
+}
+
+type TransactionLineItemTotal struct {
+	Subtotal Amount `json:"subtotal"`
+	Tax      Amount `json:"tax"`
+	Discount Amount `json:"discount"`
+	Total    Amount `json:"total"`
+}
+
+// How a transaction was created.
+type TransactionOrigin string
+
+const (
+	TransactionOriginApi                             = TransactionOrigin("api")
+	TransactionOriginSubscriptionCharge              = TransactionOrigin("subscription_charge")
+	TransactionOriginSubscriptionPaymentMethodChange = TransactionOrigin("subscription_payment_method_change")
+	TransactionOriginSubscriptionRecurring           = TransactionOrigin("subscription_recurring")
+	TransactionOriginSubscriptionUpdate              = TransactionOrigin("subscription_update")
+	TransactionOriginWeb                             = TransactionOrigin("web")
+)
+
+func (v TransactionOrigin) IsValid() bool { return isValidEnum(v) }
+func (v TransactionOrigin) IsKnown() bool {
+	return isKnownEnum(v, TransactionOriginApi, TransactionOriginSubscriptionCharge, TransactionOriginSubscriptionPaymentMethodChange, TransactionOriginSubscriptionRecurring, TransactionOriginSubscriptionUpdate, TransactionOriginWeb)
+}
+
+type TransactionPaymentAttempt struct {
+	PaymentAttemptId      string               `json:"payment_attempt_id"`
+	StoredPaymentMethodId string               `json:"stored_payment_method_id"`
+	Amount                Amount               `json:"amount"`
+	Status                PaymentAttemptStatus `json:"status"`
+	ErrorCode             *string              `json:"error_code"`
+	MethodDetails         PaymentMethod        `json:"method_details"`
+	CreatedAt             time.Time            `json:"created_at"`
+	CapturedAt            *time.Time           `json:"captured_at"`
+}
+
+type TransactionPayoutTotals struct {
+	Subtotal     Amount       `json:"subtotal"`
+	Discount     Amount       `json:"discount"`
+	Tax          Amount       `json:"tax"`
+	Total        Amount       `json:"total"`
+	Credit       Amount       `json:"credit"`
+	Balance      Amount       `json:"balance"`
+	GrandTotal   Amount       `json:"grand_total"`
+	Fee          Amount       `json:"fee"`
+	Earnings     Amount       `json:"earnings"`
+	CurrencyCode CurrencyCode `json:"currency_code"`
+}
+
+// The status of a transaction.
+type TransactionStatus string
+
+const (
+	TransactionStatusDraft     = TransactionStatus("draft")
+	TransactionStatusReady     = TransactionStatus("ready")
+	TransactionStatusBilled    = TransactionStatus("billed")
+	TransactionStatusPaid      = TransactionStatus("paid")
+	TransactionStatusCompleted = TransactionStatus("completed")
+	TransactionStatusCanceled  = TransactionStatus("canceled")
+	TransactionStatusPastDue   = TransactionStatus("past_due")
+)
+
+func (v TransactionStatus) IsValid() bool { return isValidEnum(v) }
+func (v TransactionStatus) IsKnown() bool {
+	return isKnownEnum(v, TransactionStatusDraft, TransactionStatusReady, TransactionStatusBilled, TransactionStatusPaid, TransactionStatusCompleted, TransactionStatusCanceled, TransactionStatusPastDue)
+}
+
+type TransactionTotals struct {
+	Subtotal     Amount       `json:"subtotal"`
+	Discount     Amount       `json:"discount"`
+	Tax          Amount       `json:"tax"`
+	Total        Amount       `json:"total"`
+	Credit       Amount       `json:"credit"`
+	Balance      Amount       `json:"balance"`
+	GrandTotal   Amount       `json:"grand_total"`
+	Fee          *Amount      `json:"fee"`
+	Earnings     *Amount      `json:"earnings"`
+	CurrencyCode CurrencyCode `json:"currency_code"`
+}
+
+type UpdateCustomerParams struct {
+	Email      *string     `json:"email,omitempty"`
+	Name       *string     `json:"name,omitempty"`
+	CustomData *CustomData `json:"custom_data,omitempty"`
+	Locale     *string     `json:"locale,omitempty"`
+}
+
+type UpdatePriceParams struct {
+	Description        *string                  `json:"description,omitempty"`
+	Name               *string                  `json:"name,omitempty"`
+	BillingCycle       *TimeInterval            `json:"billing_cycle,omitempty"`
+	TrialPeriod        *TimeInterval            `json:"trial_period,omitempty"`
+	TaxMode            *TaxMode                 `json:"tax_mode,omitempty"`
+	UnitPrice          *CurrencyPrice           `json:"unit_price,omitempty"`
+	UnitPriceOverrides *[]CurrencyPriceOverride `json:"unit_price_overrides,omitempty"`
+	Quantity           *MinMax                  `json:"quantity,omitempty"`
+	CustomData         *CustomData              `json:"custom_data,omitempty"`
+	Status             *Status                  `json:"status,omitempty"`
+}
+
+type UpdateProductParams struct {
+	Name        *string      `json:"name,omitempty"`
+	TaxCategory *TaxCategory `json:"tax_category,omitempty"`
+	Description *string      `json:"description,omitempty"`
+	ImageUrl    *string      `json:"image_url,omitempty"`
+	CustomData  *CustomData  `json:"custom_data,omitempty"`
+	Status      *Status      `json:"status,omitempty"`
+}
+
+type UpdateSubscriptionDiscount struct {
+	DiscountId    string                       `json:"id"`
+	EffectiveFrom SubscriptionEffectFromOption `json:"effective_from"`
+}
+
+type UpdateSubscriptionItem struct {
+	PriceId  string `json:"price_id"`
+	Quantity int    `json:"quantity"`
+}
+
+type UpdateSubscriptionParams struct {
+	CustomerId           *string                            `json:"customer_id,omitempty"`
+	AddressId            *string                            `json:"address_id,omitempty"`
+	BusinessId           *string                            `json:"business_id,omitempty"`
+	CurrencyCode         *CurrencyCode                      `json:"currency_code,omitempty"`
+	NextBilledAt         *time.Time                         `json:"next_billed_at,omitempty"`
+	Discount             *UpdateSubscriptionDiscount        `json:"discount,omitempty"`
+	CollectionMode       *PaymentCollectionMode             `json:"collection_mode,omitempty"`
+	BillingDetails       *SubscriptionBillingDetails        `json:"billing_details,omitempty"`
+	ScheduledChange      *UpdateSubscriptionScheduledChange `json:"scheduled_change,omitempty"`
+	Items                *[]UpdateSubscriptionItem          `json:"items,omitempty"`
+	CustomData           *CustomData                        `json:"custom_data,omitempty"`
+	ProrationBillingMode ProrationBillingMode               `json:"proration_billing_mode"`
+}
+
+type UpdateSubscriptionScheduledChange struct {
+	Action      SubscriptionScheduledChangeAction `json:"action"`
+	EffectiveAt *time.Time                        `json:"effective_at,omitempty"`
+	ResumeAt    *time.Time                        `json:"resume_at,omitempty"`
+}
+
+type AddressesService service
+
+// List addresses for a customer.
+func (s *AddressesService) List(ctx context.Context, customerId string, params *ListAddressesParams) ([]*Address, error) {
+	endpoint, queryErr := withQuery(ctx, "customers/"+customerId+"/addresses", params)
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	return listItems[Address](ctx, s.client, operation("addresses", "list", ""), endpoint)
+}
+
+type ListAddressesParams struct {
+	Ids     []string `query:"id"`
+	Status  []Status `query:"status"`
+	Search  string   `query:"search"`
+	After   string   `query:"after"`
+	PerPage int      `query:"per_page"`
+	OrderBy string   `query:"order_by"`
+}
+
+// Get an address for a customer.
+func (s *AddressesService) Get(ctx context.Context, customerId string, id string) (*Address, error) {
+	return getItem[Address](ctx, s.client, operation("addresses", "get", id), "customers/"+customerId+"/addresses/"+id)
+}
+
+type AdjustmentsService service
+
+// List adjustments.
+func (s *AdjustmentsService) List(ctx context.Context, params *ListAdjustmentsParams) ([]*Adjustment, error) {
+	endpoint, queryErr := withQuery(ctx, "adjustments", params)
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	return listItems[Adjustment](ctx, s.client, operation("adjustments", "list", ""), endpoint)
+}
+
+type ListAdjustmentsParams struct {
+	Ids             []string           `query:"id"`
+	Action          []AdjustmentAction `query:"action"`
+	CustomerIds     []string           `query:"customer_id"`
+	Status          []AdjustmentStatus `query:"status"`
+	SubscriptionIds []string           `query:"subscription_id"`
+	TransactionIds  []string           `query:"transaction_id"`
+	After           string             `query:"after"`
+	PerPage         int                `query:"per_page"`
+	OrderBy         string             `query:"order_by"`
+}
+
+type BusinessesService service
+
+// List businesses for a customer.
+func (s *BusinessesService) List(ctx context.Context, customerId string, params *ListBusinessesParams) ([]*Business, error) {
+	endpoint, queryErr := withQuery(ctx, "customers/"+customerId+"/businesses", params)
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	return listItems[Business](ctx, s.client, operation("businesses", "list", ""), endpoint)
+}
+
+type ListBusinessesParams struct {
+	Ids     []string `query:"id"`
+	Status  []Status `query:"status"`
+	Search  string   `query:"search"`
+	After   string   `query:"after"`
+	PerPage int      `query:"per_page"`
+	OrderBy string   `query:"order_by"`
+}
+
+// Get a business for a customer.
+func (s *BusinessesService) Get(ctx context.Context, customerId string, id string) (*Business, error) {
+	return getItem[Business](ctx, s.client, operation("businesses", "get", id), "customers/"+customerId+"/businesses/"+id)
+}
+
+type CustomersService service
+
+// List customers.
+func (s *CustomersService) List(ctx context.Context, params *ListCustomersParams) ([]*Customer, error) {
+	endpoint, queryErr := withQuery(ctx, "customers", params)
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	return listItems[Customer](ctx, s.client, operation("customers", "list", ""), endpoint)
+}
+
+// ListCustomersParams filters Customers.List. Paddle cannot filter customers
+// by created_at or updated_at; compare CreatedAt and UpdatedAt after listing
+// instead.
+type ListCustomersParams struct {
+	Ids     []string `query:"id"`
+	Email   []string `query:"email"`
+	Status  []Status `query:"status"`
+	Search  string   `query:"search"`
+	After   string   `query:"after"`
+	PerPage int      `query:"per_page"`
+	OrderBy string   `query:"order_by"`
+}
+
+// Create a customer.
+func (s *CustomersService) Create(ctx context.Context, params *CreateCustomerParams) (*Customer, error) {
+	return postItem[Customer](ctx, s.client, operation("customers", "create", ""), "customers", params)
+}
+
+// Get a customer.
+func (s *CustomersService) Get(ctx context.Context, id string) (*Customer, error) {
+	return getItem[Customer](ctx, s.client, operation("customers", "get", id), "customers/"+id)
+}
+
+// Update a customer.
+func (s *CustomersService) Update(ctx context.Context, id string, params *UpdateCustomerParams) (*Customer, error) {
+	return patchItem[Customer](ctx, s.client, operation("customers", "update", id), "customers/"+id, params)
+}
+
+type DiscountsService service
+
+// List discounts.
+func (s *DiscountsService) List(ctx context.Context, params *ListDiscountsParams) ([]*Discount, error) {
+	endpoint, queryErr := withQuery(ctx, "discounts", params)
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	return listItems[Discount](ctx, s.client, operation("discounts", "list", ""), endpoint)
+}
+
+type ListDiscountsParams struct {
+	Ids     []string         `query:"id"`
+	Status  []DiscountStatus `query:"status"`
+	Codes   []string         `query:"code"`
+	After   string           `query:"after"`
+	PerPage int              `query:"per_page"`
+	OrderBy string           `query:"order_by"`
+}
+
+// Get a discount.
+func (s *DiscountsService) Get(ctx context.Context, id string) (*Discount, error) {
+	return getItem[Discount](ctx, s.client, operation("discounts", "get", id), "discounts/"+id)
+}
+
+type PricesService service
+
+// List prices.
+func (s *PricesService) List(ctx context.Context, params *ListPricesParams) ([]*Price, error) {
+	endpoint, queryErr := withQuery(ctx, "prices", params)
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	return listItems[Price](ctx, s.client, operation("prices", "list", ""), endpoint)
+}
+
+type ListPricesParams struct {
+	IncludeProduct bool     `query:"include,value=product"`
+	Ids            []string `query:"id"`
+	ProductIds     []string `query:"product_id"`
+	Status         []Status `query:"status"`
+	Recurring      *bool    `query:"recurring"`
+	After          string   `query:"after"`
+	PerPage        int      `query:"per_page"`
+	OrderBy        string   `query:"order_by"`
+}
+
+// Create a price.
+func (s *PricesService) Create(ctx context.Context, params *CreatePriceParams) (*Price, error) {
+	return postItem[Price](ctx, s.client, operation("prices", "create", ""), "prices", params)
+}
+
+// Get a price.
+func (s *PricesService) Get(ctx context.Context, id string, includeProduct bool) (*Price, error) {
+	endpoint, queryErr := withQuery(ctx, "prices/"+id, &getPriceParams{IncludeProduct: includeProduct})
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	return getItem[Price](ctx, s.client, operation("prices", "get", id), endpoint)
+}
+
+type getPriceParams struct {
+	IncludeProduct bool `query:"include,value=product"`
+}
+
+// Update a price.
+func (s *PricesService) Update(ctx context.Context, id string, params *UpdatePriceParams) (*Price, error) {
+	return patchItem[Price](ctx, s.client, operation("prices", "update", id), "prices/"+id, params)
+}
+
+type ProductsService service
+
+// List products.
+func (s *ProductsService) List(ctx context.Context, params *ListProductsParams) ([]*Product, error) {
+	endpoint, queryErr := withQuery(ctx, "products", params)
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	return listItems[Product](ctx, s.client, operation("products", "list", ""), endpoint)
+}
+
+type ListProductsParams struct {
+	IncludePrices bool          `query:"include,value=prices"`
+	Ids           []string      `query:"id"`
+	Status        []Status      `query:"status"`
+	TaxCategory   []TaxCategory `query:"tax_category"`
+	After         string        `query:"after"`
+	PerPage       int           `query:"per_page"`
+	OrderBy       string        `query:"order_by"`
+}
+
+// Create a product.
+func (s *ProductsService) Create(ctx context.Context, params *CreateProductParams) (*Product, error) {
+	return postItem[Product](ctx, s.client, operation("products", "create", ""), "products", params)
+}
+
+// Get a product.
+func (s *ProductsService) Get(ctx context.Context, id string, includePrices bool) (*Product, error) {
+	endpoint, queryErr := withQuery(ctx, "products/"+id, &getProductParams{IncludePrices: includePrices})
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	return getItem[Product](ctx, s.client, operation("products", "get", id), endpoint)
+}
+
+type getProductParams struct {
+	IncludePrices bool `query:"include,value=prices"`
+}
+
+// Update a product.
+func (s *ProductsService) Update(ctx context.Context, id string, params *UpdateProductParams) (*Product, error) {
+	return patchItem[Product](ctx, s.client, operation("products", "update", id), "products/"+id, params)
+}
+
+type SubscriptionsService service
+
+// List subscriptions.
+func (s *SubscriptionsService) List(ctx context.Context, params *ListSubscriptionsParams) ([]*Subscription, error) {
+	endpoint, queryErr := withQuery(ctx, "subscriptions", params)
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	return listItems[Subscription](ctx, s.client, operation("subscriptions", "list", ""), endpoint)
+}
+
+// ListSubscriptionsParams filters Subscriptions.List. Paddle cannot filter
+// subscriptions by created_at, updated_at or next_billed_at; compare those
+// fields after listing instead, or list transactions by BilledAt.
+type ListSubscriptionsParams struct {
+	Ids                   []string                            `query:"id"`
+	CustomerIds           []string                            `query:"customer_id"`
+	AddressIds            []string                            `query:"address_id"`
+	PriceIds              []string                            `query:"price_id"`
+	CollectionMode        PaymentCollectionMode               `query:"collection_mode"`
+	ScheduledChangeAction []SubscriptionScheduledChangeAction `query:"scheduled_change_action"`
+	Status                []SubscriptionStatus                `query:"status"`
+	Search                string                              `query:"search"`
+	After                 string                              `query:"after"`
+	PerPage               int                                 `query:"per_page"`
+	OrderBy               string                              `query:"order_by"`
+}
+
+// Get a subscription.
+func (s *SubscriptionsService) Get(ctx context.Context, id string) (*Subscription, error) {
+	return getItem[Subscription](ctx, s.client, operation("subscriptions", "get", id), "subscriptions/"+id)
+}
+
+// Update a subscription.
+func (s *SubscriptionsService) Update(ctx context.Context, id string, params *UpdateSubscriptionParams) (*Subscription, error) {
+	return patchItem[Subscription](ctx, s.client, operation("subscriptions", "update", id), "subscriptions/"+id, params)
+}
+
+// Remove the scheduled change from a subscription.
+func (s *SubscriptionsService) RemoveScheduledCancellation(ctx context.Context, id string) (*Subscription, error) {
+	body := struct {
+		ScheduledChange *any `json:"scheduled_change"`
+	}{}
+	return patchItem[Subscription](ctx, s.client, operation("subscriptions", "remove_scheduled_cancellation", id), "subscriptions/"+id, body)
+}
+
+// Cancel a subscription.
+func (s *SubscriptionsService) Cancel(ctx context.Context, id string, params *CancelSubscriptionParams) (*Subscription, error) {
+	return postItem[Subscription](ctx, s.client, operation("subscriptions", "cancel", id), "subscriptions/"+id+"/cancel", params)
+}
+
+// Pause a subscription.
+func (s *SubscriptionsService) Pause(ctx context.Context, id string, params *PauseSubscriptionParams) (*Subscription, error) {
+	return postItem[Subscription](ctx, s.client, operation("subscriptions", "pause", id), "subscriptions/"+id+"/pause", params)
+}
+
+// Preview an update to a subscription.
+func (s *SubscriptionsService) PreviewUpdate(ctx context.Context, id string, params *UpdateSubscriptionParams) (*SubscriptionUpdatePreview, error) {
+	return patchItem[SubscriptionUpdatePreview](ctx, s.client, operation("subscriptions", "preview_update", id), "subscriptions/"+id+"/preview", params)
+}
+
+// Resume a paused subscription.
+func (s *SubscriptionsService) Resume(ctx context.Context, id string, params *ResumeSubscriptionParams) (*Subscription, error) {
+	return postItem[Subscription](ctx, s.client, operation("subscriptions", "resume", id), "subscriptions/"+id+"/resume", params)
+}
+
+// Get a transaction to update the payment method of a subscription.
+func (s *SubscriptionsService) GetUpdatePaymentMethodTransaction(ctx context.Context, id string) (*Transaction, error) {
+	return getItem[Transaction](ctx, s.client, operation("subscriptions", "get_update_payment_method_transaction", id), "subscriptions/"+id+"/update-payment-method-transaction")
+}
+
+type TransactionsService service
+
+// List transactions.
+func (s *TransactionsService) List(ctx context.Context, params *ListTransactionsParams) ([]*Transaction, error) {
+	endpoint, queryErr := withQuery(ctx, "transactions", params)
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	return listItems[Transaction](ctx, s.client, operation("transactions", "list", ""), endpoint)
+}
+
+type ListTransactionsParams struct {
+	Ids             []string                 `query:"id"`
+	Include         *TransactionIncludeParam `query:"include"`
+	CollectionMode  PaymentCollectionMode    `query:"collection_mode"`
+	CustomerIds     []string                 `query:"customer_id"`
+	SubscriptionIds []string                 `query:"subscription_id"`
+	InvoiceNumber   []string                 `query:"invoice_number"`
+	Status          []TransactionStatus      `query:"status"`
+	CreatedAt       *TimeFilter              `query:"created_at"`
+	UpdatedAt       *TimeFilter              `query:"updated_at"`
+	BilledAt        *TimeFilter              `query:"billed_at"`
+	After           string                   `query:"after"`
+	PerPage         int                      `query:"per_page"`
+	OrderBy         string                   `query:"order_by"`
+}
+
+// Get a transaction.
+func (s *TransactionsService) Get(ctx context.Context, id string, include *TransactionIncludeParam) (*Transaction, error) {
+	endpoint, queryErr := withQuery(ctx, "transactions/"+id, &getTransactionParams{Include: include})
+	if queryErr != nil {
+		return nil, queryErr
+	}
+	return getItem[Transaction](ctx, s.client, operation("transactions", "get", id), endpoint)
+}
+
+type getTransactionParams struct {
+	Include *TransactionIncludeParam `query:"include"`
+}
+
+type AddressesAPI interface {
+	List(ctx context.Context, customerId string, params *ListAddressesParams) ([]*Address, error)
+	Get(ctx context.Context, customerId string, id string) (*Address, error)
+}
+
+var _ AddressesAPI = (*AddressesService)(nil)
+
+type AdjustmentsAPI interface {
+	List(ctx context.Context, params *ListAdjustmentsParams) ([]*Adjustment, error)
+}
+
+var _ AdjustmentsAPI = (*AdjustmentsService)(nil)
+
+type BusinessesAPI interface {
+	List(ctx context.Context, customerId string, params *ListBusinessesParams) ([]*Business, error)
+	Get(ctx context.Context, customerId string, id string) (*Business, error)
+}
+
+var _ BusinessesAPI = (*BusinessesService)(nil)
+
+type CustomersAPI interface {
+	List(ctx context.Context, params *ListCustomersParams) ([]*Customer, error)
+	Create(ctx context.Context, params *CreateCustomerParams) (*Customer, error)
+	Get(ctx context.Context, id string) (*Customer, error)
+	Update(ctx context.Context, id string, params *UpdateCustomerParams) (*Customer, error)
+}
+
+var _ CustomersAPI = (*CustomersService)(nil)
+
+type DiscountsAPI interface {
+	List(ctx context.Context, params *ListDiscountsParams) ([]*Discount, error)
+	Get(ctx context.Context, id string) (*Discount, error)
+}
+
+var _ DiscountsAPI = (*DiscountsService)(nil)
+
+type PricesAPI interface {
+	List(ctx context.Context, params *ListPricesParams) ([]*Price, error)
+	Create(ctx context.Context, params *CreatePriceParams) (*Price, error)
+	Get(ctx context.Context, id string, includeProduct bool) (*Price, error)
+	Update(ctx context.Context, id string, params *UpdatePriceParams) (*Price, error)
+}
+
+var _ PricesAPI = (*PricesService)(nil)
+
+type ProductsAPI interface {
+	List(ctx context.Context, params *ListProductsParams) ([]*Product, error)
+	Create(ctx context.Context, params *CreateProductParams) (*Product, error)
+	Get(ctx context.Context, id string, includePrices bool) (*Product, error)
+	Update(ctx context.Context, id string, params *UpdateProductParams) (*Product, error)
+}
+
+var _ ProductsAPI = (*ProductsService)(nil)
+
+type SubscriptionsAPI interface {
+	List(ctx context.Context, params *ListSubscriptionsParams) ([]*Subscription, error)
+	Get(ctx context.Context, id string) (*Subscription, error)
+	Update(ctx context.Context, id string, params *UpdateSubscriptionParams) (*Subscription, error)
+	RemoveScheduledCancellation(ctx context.Context, id string) (*Subscription, error)
+	Cancel(ctx context.Context, id string, params *CancelSubscriptionParams) (*Subscription, error)
+	Pause(ctx context.Context, id string, params *PauseSubscriptionParams) (*Subscription, error)
+	PreviewUpdate(ctx context.Context, id string, params *UpdateSubscriptionParams) (*SubscriptionUpdatePreview, error)
+	Resume(ctx context.Context, id string, params *ResumeSubscriptionParams) (*Subscription, error)
+	GetUpdatePaymentMethodTransaction(ctx context.Context, id string) (*Transaction, error)
+}
+
+var _ SubscriptionsAPI = (*SubscriptionsService)(nil)
+
+type TransactionsAPI interface {
+	List(ctx context.Context, params *ListTransactionsParams) ([]*Transaction, error)
+	Get(ctx context.Context, id string, include *TransactionIncludeParam) (*Transaction, error)
+}
+
+var _ TransactionsAPI = (*TransactionsService)(nil)
+
+type generatedServices struct {
+	Addresses     *AddressesService
+	Adjustments   *AdjustmentsService
+	Businesses    *BusinessesService
+	Customers     *CustomersService
+	Discounts     *DiscountsService
+	Prices        *PricesService
+	Products      *ProductsService
+	Subscriptions *SubscriptionsService
+	Transactions  *TransactionsService
+}
+
+func (gs *generatedServices) init(s *service) {
+	gs.Addresses = (*AddressesService)(s)
+	gs.Adjustments = (*AdjustmentsService)(s)
+	gs.Businesses = (*BusinessesService)(s)
+	gs.Customers = (*CustomersService)(s)
+	gs.Discounts = (*DiscountsService)(s)
+	gs.Prices = (*PricesService)(s)
+	gs.Products = (*ProductsService)(s)
+	gs.Subscriptions = (*SubscriptionsService)(s)
+	gs.Transactions = (*TransactionsService)(s)
+}
+
+type generatedAPI interface {
+	AddressesAPI() AddressesAPI
+	AdjustmentsAPI() AdjustmentsAPI
+	BusinessesAPI() BusinessesAPI
+	CustomersAPI() CustomersAPI
+	DiscountsAPI() DiscountsAPI
+	PricesAPI() PricesAPI
+	ProductsAPI() ProductsAPI
+	SubscriptionsAPI() SubscriptionsAPI
+	TransactionsAPI() TransactionsAPI
+}
+
+func (gs *generatedServices) AddressesAPI() AddressesAPI         { return gs.Addresses }
+func (gs *generatedServices) AdjustmentsAPI() AdjustmentsAPI     { return gs.Adjustments }
+func (gs *generatedServices) BusinessesAPI() BusinessesAPI       { return gs.Businesses }
+func (gs *generatedServices) CustomersAPI() CustomersAPI         { return gs.Customers }
+func (gs *generatedServices) DiscountsAPI() DiscountsAPI         { return gs.Discounts }
+func (gs *generatedServices) PricesAPI() PricesAPI               { return gs.Prices }
+func (gs *generatedServices) ProductsAPI() ProductsAPI           { return gs.Products }
+func (gs *generatedServices) SubscriptionsAPI() SubscriptionsAPI { return gs.Subscriptions }
+func (gs *generatedServices) TransactionsAPI() TransactionsAPI   { return gs.Transactions }